@@ -1,89 +1,314 @@
 package main
 
 import (
-	"database/sql"
-	"log"
+	"context"
+	"flag"
+	"os"
 
 	admin_delivery "github.com/forum-api-back/internal/pkg/admin/handler"
-	admin_repo "github.com/forum-api-back/internal/pkg/admin/repository"
 	admin_usecase "github.com/forum-api-back/internal/pkg/admin/usecase"
+	auth_delivery "github.com/forum-api-back/internal/pkg/auth/handler"
+	auth_usecase "github.com/forum-api-back/internal/pkg/auth/usecase"
+	"github.com/forum-api-back/internal/pkg/cache"
+	"github.com/forum-api-back/internal/pkg/cache/lru"
+	"github.com/forum-api-back/internal/pkg/config"
+	"github.com/forum-api-back/internal/pkg/events"
+	events_bus "github.com/forum-api-back/internal/pkg/events/bus"
+	events_dispatcher "github.com/forum-api-back/internal/pkg/events/dispatcher"
+	events_delivery "github.com/forum-api-back/internal/pkg/events/handler"
+	events_usecase "github.com/forum-api-back/internal/pkg/events/usecase"
 	forum_delivery "github.com/forum-api-back/internal/pkg/forum/handler"
-	forum_repo "github.com/forum-api-back/internal/pkg/forum/repository"
 	forum_usecase "github.com/forum-api-back/internal/pkg/forum/usecase"
+	"github.com/forum-api-back/internal/pkg/health"
+	notifications_delivery "github.com/forum-api-back/internal/pkg/notifications/handler"
+	notifications_usecase "github.com/forum-api-back/internal/pkg/notifications/usecase"
 	post_delivery "github.com/forum-api-back/internal/pkg/post/handler"
-	post_repo "github.com/forum-api-back/internal/pkg/post/repository"
 	post_usecase "github.com/forum-api-back/internal/pkg/post/usecase"
+	roles_delivery "github.com/forum-api-back/internal/pkg/roles/handler"
+	roles_usecase "github.com/forum-api-back/internal/pkg/roles/usecase"
+	search_delivery "github.com/forum-api-back/internal/pkg/search/handler"
+	search_usecase "github.com/forum-api-back/internal/pkg/search/usecase"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
 	thread_delivery "github.com/forum-api-back/internal/pkg/thread/handler"
-	thread_repo "github.com/forum-api-back/internal/pkg/thread/repository"
 	thread_usecase "github.com/forum-api-back/internal/pkg/thread/usecase"
 	user_delivery "github.com/forum-api-back/internal/pkg/user/handler"
-	user_repo "github.com/forum-api-back/internal/pkg/user/repository"
 	user_usecase "github.com/forum-api-back/internal/pkg/user/usecase"
+	webhooks_delivery "github.com/forum-api-back/internal/pkg/webhooks/handler"
+	webhooks_sender "github.com/forum-api-back/internal/pkg/webhooks/sender"
+	webhooks_usecase "github.com/forum-api-back/internal/pkg/webhooks/usecase"
+
+	"github.com/forum-api-back/pkg/logger"
+	"github.com/forum-api-back/pkg/tools/auth_utils"
+	"github.com/forum-api-back/pkg/tools/http_utils"
+	"github.com/forum-api-back/pkg/tracing"
 
 	"github.com/fasthttp/router"
-	_ "github.com/lib/pq"
 	"github.com/valyala/fasthttp"
 )
 
 func main() {
-	// Connect to postgreSql db
-	postgreSqlConn, err := sql.Open(
-		"postgres",
-		"user=postgres "+
-			"password=postgres "+
-			"dbname=forum_db "+
-			"host=localhost "+
-			"port=5432 ",
-	)
-	postgreSqlConn.SetMaxIdleConns(100)
-	postgreSqlConn.SetMaxIdleConns(100)
+	cfg, args, err := config.Load(os.Args[0], os.Args[1:])
+	if err == flag.ErrHelp {
+		return
+	}
+	if err != nil {
+		logger.Default().Error(err.Error())
+		os.Exit(1)
+	}
+	level, _ := logger.ParseLevel(cfg.Log.Level)
+	logger.SetDefault(logger.New(os.Stderr, cfg.Log.Format, level))
+
+	if len(args) != 0 && args[0] == "migrate" {
+		err = runMigrate(cfg, args[1:])
+	} else if len(args) != 0 && args[0] == "password" {
+		err = runPassword(cfg, args[1:], os.Stdin)
+	} else {
+		err = run(cfg)
+	}
+	if err != nil {
+		logger.Default().Error(err.Error())
+		os.Exit(1)
+	}
+}
 
+func run(cfg *config.Config) error {
+	var repos *repositories
+	var bus events.Bus
+	serverMetrics := newServerMetrics()
+	healthState := health.NewState(cfg.Health.CheckTimeout.Duration)
+	healthState.SetMaintenance(cfg.Health.Maintenance)
+	switch cfg.Database.Driver {
+	case config.DriverMemory:
+		repos = newMemoryRepositories(memory.NewStorage())
+	default:
+		postgreSqlConn, err := openPostgresql(&cfg.Database)
+		if err != nil {
+			return err
+		}
+		defer postgreSqlConn.Close()
+
+		if cfg.Database.AutoMigrate {
+			if _, err := newMigrator(postgreSqlConn, &cfg.Database).Up(context.Background(), 0); err != nil {
+				return err
+			}
+		}
+		repos = newPostgresqlRepositories(postgreSqlConn)
+		serverMetrics.observeDB(postgreSqlConn)
+		healthState.AddCheck("database", health.DatabaseCheck(postgreSqlConn))
+		healthState.AddCheck("schema", health.SchemaCheck(newMigrator(postgreSqlConn, &cfg.Database)))
+		healthState.AddCheck("pool", health.PoolCheck(postgreSqlConn))
+
+		if cfg.Events.Notify {
+			bus, err = events_bus.NewNotifyBus(postgreSqlConn, cfg.Database.DSN, cfg.Events.Buffer)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	repos.measured(serverMetrics.queries, cfg.Log.SlowQuery.Duration)
+	repos.traced()
+	var hotCache cache.Cache
+	if cfg.Cache.Enabled {
+		hotCache = lru.NewCache(cfg.Cache.Size, cfg.Cache.TTL.Duration)
+		repos.withCache(hotCache)
+	}
+	if err := roles_usecase.CheckAdmins(context.Background(), repos.user, cfg.Auth.Admins); err != nil {
+		return err
+	}
+	if bus == nil {
+		bus = events_bus.NewBus(cfg.Events.Buffer)
+	}
+	// The tracer is closed after the dispatcher, so that spans ended while
+	// the dispatcher drains its queued events are still exported.
+	tracer, err := newTracer(&cfg.Tracing)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	if tracer != nil {
+		defer tracer.Close()
 	}
-	defer postgreSqlConn.Close()
-	if err := postgreSqlConn.Ping(); err != nil {
-		log.Fatal(err)
+	dispatcher := events_dispatcher.NewDispatcher(cfg.Events.Buffer)
+	defer dispatcher.Close()
+	dispatcher.Subscribe(events_bus.NewRelay(bus))
+	dispatcher.Subscribe(events.SubscriberFunc(serverMetrics.countEvents))
+	serverMetrics.observeDispatcher(dispatcher)
+
+	signer, err := newSigner(&cfg.Auth)
+	if err != nil {
+		return err
 	}
 
-	userRepo := user_repo.NewSessionPostgresqlRepository(postgreSqlConn)
-	forumRepo := forum_repo.NewSessionPostgresqlRepository(postgreSqlConn)
-	postRepo := post_repo.NewSessionPostgresqlRepository(postgreSqlConn)
-	threadRepo := thread_repo.NewSessionPostgresqlRepository(postgreSqlConn)
-	adminRepo := admin_repo.NewSessionPostgresqlRepository(postgreSqlConn)
+	rolesUCase := roles_usecase.NewTracedUseCase(
+		roles_usecase.NewUseCase(repos.roles, repos.forum, repos.user, cfg.Auth.Admins))
+	userUCase := user_usecase.NewTracedUseCase(
+		user_usecase.NewUseCase(repos.user, repos.forum, dispatcher))
+	forumUCase := forum_usecase.NewTracedUseCase(
+		forum_usecase.NewUseCase(repos.forum, repos.user, rolesUCase, dispatcher))
+	postUCase := post_usecase.NewTracedUseCase(
+		post_usecase.NewUseCase(repos.post, repos.thread, repos.forum, repos.user, rolesUCase, dispatcher))
+	threadUCase := thread_usecase.NewTracedUseCase(
+		thread_usecase.NewUseCase(repos.thread, repos.forum, rolesUCase, dispatcher))
+	adminUCase := admin_usecase.NewTracedUseCase(
+		admin_usecase.NewUseCase(repos.admin, rolesUCase, dispatcher, hotCache, healthState))
+	searchUCase := search_usecase.NewTracedUseCase(
+		search_usecase.NewUseCase(repos.search, repos.forum, repos.thread, repos.user))
+	authUCase := auth_usecase.NewTracedUseCase(
+		auth_usecase.NewUseCase(repos.auth, repos.user, signer, cfg.Auth.SessionTTL.Duration))
+	eventsUCase := events_usecase.NewTracedUseCase(
+		events_usecase.NewUseCase(bus, repos.thread, repos.forum))
+	webhooksUCase := webhooks_usecase.NewTracedUseCase(
+		webhooks_usecase.NewUseCase(repos.webhooks, repos.forum, rolesUCase))
+	notificationsUCase := notifications_usecase.NewTracedUseCase(
+		notifications_usecase.NewUseCase(repos.notifications, repos.post, repos.user))
 
-	userUCase := user_usecase.NewUseCase(userRepo, forumRepo)
-	forumUCase := forum_usecase.NewUseCase(forumRepo, userRepo)
-	postUCase := post_usecase.NewUseCase(postRepo, threadRepo, forumRepo, userRepo)
-	threadUCase := thread_usecase.NewUseCase(threadRepo, forumRepo)
-	adminUCase := admin_usecase.NewUseCase(adminRepo)
+	dispatcher.SubscribeAsync(events.SubscriberFunc(webhooksUCase.Enqueue))
+	dispatcher.SubscribeAsync(events.SubscriberFunc(notificationsUCase.Notify))
+	sender := webhooks_sender.NewSender(repos.webhooks, webhooks_sender.NewClient(cfg.Webhooks.Timeout.Duration, cfg.Webhooks.AllowPrivate),
+		webhooks_sender.Options{
+			MaxAttempts:  cfg.Webhooks.MaxAttempts,
+			Backoff:      cfg.Webhooks.Backoff.Duration,
+			MaxBackoff:   cfg.Webhooks.MaxBackoff.Duration,
+			PollInterval: cfg.Webhooks.PollInterval.Duration,
+		})
+	senderCtx, stopSender := context.WithCancel(context.Background())
+	defer stopSender()
+	go sender.Run(senderCtx)
 
 	userHandler := user_delivery.NewHandler(userUCase)
 	forumHandler := forum_delivery.NewHandler(forumUCase)
 	postHandler := post_delivery.NewHandler(postUCase)
 	threadHandler := thread_delivery.NewHandler(threadUCase)
 	adminHandler := admin_delivery.NewHandler(adminUCase)
+	searchHandler := search_delivery.NewHandler(searchUCase)
+	authHandler := auth_delivery.NewHandler(authUCase)
+	rolesHandler := roles_delivery.NewHandler(rolesUCase)
+	eventsHandler := events_delivery.NewHandler(eventsUCase, cfg.Events.Heartbeat.Duration)
+	webhooksHandler := webhooks_delivery.NewHandler(webhooksUCase, cfg.Webhooks.AllowPrivate)
+	notificationsHandler := notifications_delivery.NewHandler(notificationsUCase)
 
 	mainRouter := router.New()
-	mainRouter.POST("/api/forum/create", forumHandler.CreateNewForum)
-	mainRouter.GET("/api/forum/{slug}/details", forumHandler.GetForumDetails)
-	mainRouter.POST("/api/forum/{slug}/create", threadHandler.CreateNewThread)
-	mainRouter.GET("/api/forum/{slug}/users", userHandler.GetUsersByForum)
-	mainRouter.GET("/api/forum/{slug}/threads", threadHandler.GetThreadsByForum)
-	mainRouter.GET("/api/post/{id}/details", postHandler.GetPostDetails)
-	mainRouter.POST("/api/post/{id}/details", postHandler.UpdatePostDetails)
-	mainRouter.POST("/api/service/clear", adminHandler.ClearBase)
-	mainRouter.GET("/api/service/status", adminHandler.GetBaseDetails)
-	mainRouter.POST("/api/thread/{slug_or_id}/create", postHandler.CreateNewPosts)
-	mainRouter.GET("/api/thread/{slug_or_id}/details", threadHandler.GetThreadDetails)
-	mainRouter.POST("/api/thread/{slug_or_id}/details", threadHandler.UpdateThreadDetails)
-	mainRouter.GET("/api/thread/{slug_or_id}/posts", postHandler.GetPostsByThread)
-	mainRouter.POST("/api/thread/{slug_or_id}/vote", threadHandler.UpdateThreadVote)
-	mainRouter.POST("/api/user/{nickname}/create", userHandler.CreateNewUser)
-	mainRouter.GET("/api/user/{nickname}/profile", userHandler.GetUserProfile)
-	mainRouter.POST("/api/user/{nickname}/profile", userHandler.UpdateUserProfile)
-
-	if err := fasthttp.ListenAndServe(":5000", mainRouter.Handler); err != nil {
-		log.Fatal(err)
+	route := func(method, path string, handler fasthttp.RequestHandler) {
+		timeout := cfg.Server.RouteTimeout(method, path)
+		if tracer != nil {
+			handler = http_utils.WithTracing(handler, tracer, method, path)
+		}
+		handler = http_utils.WithRequestID(handler, logger.Default())
+		handler = http_utils.WithContext(handler, timeout)
+		handler = http_utils.WithMetrics(handler, method, path, serverMetrics.requests, serverMetrics.latencies)
+		if cfg.Log.Access {
+			handler = http_utils.WithAccessLog(handler, method, path)
+		}
+		mainRouter.Handle(method, path, handler)
 	}
+	// Routes acting on behalf of a user resolve its session token first.
+	authenticated := func(handler fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return authHandler.Authenticate(handler, cfg.Auth.Required)
+	}
+	// Deleting and archiving is never done on the word of a request body.
+	signedIn := func(handler fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return authHandler.Authenticate(handler, true)
+	}
+
+	route(fasthttp.MethodGet, "/healthz", healthState.Liveness)
+	route(fasthttp.MethodGet, "/readyz", healthState.Readiness)
+	if cfg.Features.Metrics {
+		route(fasthttp.MethodGet, "/metrics", http_utils.MetricsHandler(serverMetrics.registry))
+	}
+	route(fasthttp.MethodPost, "/api/auth/login", authHandler.Login)
+	route(fasthttp.MethodPost, "/api/auth/logout", authHandler.Logout)
+	route(fasthttp.MethodPost, "/api/forum/create", authenticated(forumHandler.CreateNewForum))
+	route(fasthttp.MethodGet, "/api/forum/{slug}/details", forumHandler.GetForumDetails)
+	route(fasthttp.MethodDelete, "/api/forum/{slug}/details", signedIn(forumHandler.DeleteForum))
+	route(fasthttp.MethodPost, "/api/forum/{slug}/archive", signedIn(forumHandler.ArchiveForum))
+	route(fasthttp.MethodPost, "/api/forum/{slug}/unarchive", signedIn(forumHandler.UnarchiveForum))
+	route(fasthttp.MethodPost, "/api/forum/{slug}/create", authenticated(threadHandler.CreateNewThread))
+	route(fasthttp.MethodGet, "/api/forum/{slug}/events", eventsHandler.StreamForumEvents)
+	route(fasthttp.MethodGet, "/api/forum/{slug}/moderators", rolesHandler.GetModerators)
+	route(fasthttp.MethodPost, "/api/forum/{slug}/moderators/{nickname}", authenticated(rolesHandler.AddModerator))
+	route(fasthttp.MethodDelete, "/api/forum/{slug}/moderators/{nickname}", authenticated(rolesHandler.RemoveModerator))
+	route(fasthttp.MethodGet, "/api/forum/{slug}/users", userHandler.GetUsersByForum)
+	route(fasthttp.MethodGet, "/api/forum/{slug}/webhooks", signedIn(webhooksHandler.GetWebhooks))
+	route(fasthttp.MethodPost, "/api/forum/{slug}/webhooks", signedIn(webhooksHandler.CreateWebhook))
+	route(fasthttp.MethodDelete, "/api/forum/{slug}/webhooks/{id}", signedIn(webhooksHandler.DeleteWebhook))
+	route(fasthttp.MethodGet, "/api/forum/{slug}/webhooks/{id}/deliveries", signedIn(webhooksHandler.GetDeliveries))
+	route(fasthttp.MethodGet, "/api/forum/{slug}/threads", threadHandler.GetThreadsByForum)
+	route(fasthttp.MethodGet, "/api/post/{id}/details", postHandler.GetPostDetails)
+	route(fasthttp.MethodPost, "/api/post/{id}/details", authenticated(postHandler.UpdatePostDetails))
+	route(fasthttp.MethodDelete, "/api/post/{id}/details", signedIn(postHandler.DeletePost))
+	route(fasthttp.MethodGet, "/api/post/{id}/history", postHandler.GetPostHistory)
+	route(fasthttp.MethodGet, "/api/post/{id}/history/diff", postHandler.DiffPostRevisions)
+	route(fasthttp.MethodPost, "/api/post/{id}/history/{revision}/restore", signedIn(postHandler.RestorePostRevision))
+	route(fasthttp.MethodGet, "/api/search", searchHandler.Search)
+	if cfg.Features.ServiceClear {
+		route(fasthttp.MethodPost, "/api/service/clear", authenticated(adminHandler.ClearBase))
+	}
+	route(fasthttp.MethodGet, "/api/service/status", authenticated(adminHandler.GetBaseDetails))
+	route(fasthttp.MethodGet, "/api/service/cache", authenticated(adminHandler.GetCacheStats))
+	route(fasthttp.MethodGet, "/api/service/maintenance", authenticated(adminHandler.GetMaintenance))
+	route(fasthttp.MethodPost, "/api/service/maintenance", authenticated(adminHandler.SetMaintenance))
+	route(fasthttp.MethodGet, "/api/service/health", authenticated(adminHandler.GetHealth))
+	route(fasthttp.MethodPost, "/api/thread/{slug_or_id}/create", authenticated(postHandler.CreateNewPosts))
+	route(fasthttp.MethodGet, "/api/thread/{slug_or_id}/details", threadHandler.GetThreadDetails)
+	route(fasthttp.MethodPost, "/api/thread/{slug_or_id}/details", authenticated(threadHandler.UpdateThreadDetails))
+	route(fasthttp.MethodDelete, "/api/thread/{slug_or_id}/details", signedIn(threadHandler.DeleteThread))
+	route(fasthttp.MethodGet, "/api/thread/{slug_or_id}/events", eventsHandler.StreamThreadEvents)
+	route(fasthttp.MethodGet, "/api/thread/{slug_or_id}/history", threadHandler.GetThreadHistory)
+	route(fasthttp.MethodPost, "/api/thread/{slug_or_id}/archive", signedIn(threadHandler.ArchiveThread))
+	route(fasthttp.MethodPost, "/api/thread/{slug_or_id}/unarchive", signedIn(threadHandler.UnarchiveThread))
+	route(fasthttp.MethodGet, "/api/thread/{slug_or_id}/posts", postHandler.GetPostsByThread)
+	route(fasthttp.MethodPost, "/api/thread/{slug_or_id}/vote", authenticated(threadHandler.UpdateThreadVote))
+	route(fasthttp.MethodPost, "/api/user/{nickname}/create", userHandler.CreateNewUser)
+	route(fasthttp.MethodGet, "/api/user/{nickname}/notifications", signedIn(notificationsHandler.GetNotifications))
+	route(fasthttp.MethodPost, "/api/user/{nickname}/notifications/read", signedIn(notificationsHandler.MarkNotificationsRead))
+	route(fasthttp.MethodPost, "/api/user/{nickname}/notifications/unread", signedIn(notificationsHandler.MarkNotificationsUnread))
+	route(fasthttp.MethodGet, "/api/user/{nickname}/notifications/status", signedIn(notificationsHandler.GetNotificationsStatus))
+	route(fasthttp.MethodGet, "/api/user/{nickname}/profile", userHandler.GetUserProfile)
+	route(fasthttp.MethodPost, "/api/user/{nickname}/profile", authenticated(userHandler.UpdateUserProfile))
+
+	server := &fasthttp.Server{
+		Handler:         mainRouter.Handler,
+		ReadTimeout:     cfg.Server.ReadTimeout.Duration,
+		WriteTimeout:    cfg.Server.WriteTimeout.Duration,
+		IdleTimeout:     cfg.Server.IdleTimeout.Duration,
+		CloseOnShutdown: true,
+		Logger:          logger.Default(),
+	}
+
+	return serve(server, &cfg.Server, healthState, bus)
+}
+
+// newSigner builds the signer of session tokens. Without a configured
+// secret the tokens only stay valid until the server restarts.
+func newSigner(cfg *config.AuthConfig) (*auth_utils.Signer, error) {
+	if cfg.Secret != "" {
+		return auth_utils.NewSigner([]byte(cfg.Secret)), nil
+	}
+
+	secret, err := auth_utils.RandomSecret()
+	if err != nil {
+		return nil, err
+	}
+	logger.Default().Warn("auth secret is not set, sessions won't survive a restart")
+	return auth_utils.NewSigner(secret), nil
+}
+
+// newTracer builds the tracer of requests, nil when tracing is off.
+func newTracer(cfg *config.TracingConfig) (*tracing.Tracer, error) {
+	var exporter tracing.Exporter
+	switch cfg.Exporter {
+	case config.TracingExporterStdout:
+		exporter = tracing.NewWriterExporter(os.Stdout)
+	case config.TracingExporterFile:
+		fileExporter, err := tracing.NewFileExporter(cfg.File)
+		if err != nil {
+			return nil, err
+		}
+		exporter = fileExporter
+	default:
+		return nil, nil
+	}
+
+	return tracing.NewTracer(cfg.Service, exporter, cfg.SampleRatio, func(err error) {
+		logger.Default().Error("tracing: can't export span", "error", err)
+	}), nil
 }
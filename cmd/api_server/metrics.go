@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/forum-api-back/internal/pkg/events"
+	"github.com/forum-api-back/pkg/metrics"
+)
+
+// serverMetrics are the metrics served at /metrics.
+type serverMetrics struct {
+	registry *metrics.Registry
+
+	requests  *metrics.CounterVec
+	latencies *metrics.HistogramVec
+	queries   *metrics.HistogramVec
+
+	events       *metrics.CounterVec
+	postsCreated *metrics.Counter
+	votesCast    *metrics.Counter
+}
+
+func newServerMetrics() *serverMetrics {
+	registry := metrics.NewRegistry()
+	return &serverMetrics{
+		registry: registry,
+		requests: registry.Counter("http_requests_total",
+			"HTTP requests by route and response status.", "method", "route", "status"),
+		latencies: registry.Histogram("http_request_duration_seconds",
+			"Latency of HTTP requests by route.", metrics.DefBuckets, "method", "route"),
+		queries: registry.Histogram("forum_repository_duration_seconds",
+			"Duration of repository methods, cache hits excluded.", metrics.QueryBuckets, "repository", "method"),
+		events: registry.Counter("forum_events_total",
+			"Domain events published by name.", "event"),
+		postsCreated: registry.Counter("forum_posts_created_total",
+			"Posts created.").With(),
+		votesCast: registry.Counter("forum_votes_cast_total",
+			"Votes cast for threads, changed votes included.").With(),
+	}
+}
+
+// observeDB exposes the statistics of the connection pool of db.
+func (m *serverMetrics) observeDB(db *sql.DB) {
+	m.registry.GaugeFunc("db_max_open_connections", "Maximum number of open connections to the database.",
+		func() float64 { return float64(db.Stats().MaxOpenConnections) })
+	m.registry.GaugeFunc("db_open_connections", "Established connections, in use and idle.",
+		func() float64 { return float64(db.Stats().OpenConnections) })
+	m.registry.GaugeFunc("db_in_use_connections", "Connections in use.",
+		func() float64 { return float64(db.Stats().InUse) })
+	m.registry.GaugeFunc("db_idle_connections", "Idle connections.",
+		func() float64 { return float64(db.Stats().Idle) })
+	m.registry.CounterFunc("db_wait_count_total", "Connections waited for.",
+		func() float64 { return float64(db.Stats().WaitCount) })
+	m.registry.CounterFunc("db_wait_duration_seconds_total", "Time spent waiting for connections.",
+		func() float64 { return db.Stats().WaitDuration.Seconds() })
+	m.registry.CounterFunc("db_max_idle_closed_total", "Connections closed as the idle pool was full.",
+		func() float64 { return float64(db.Stats().MaxIdleClosed) })
+	m.registry.CounterFunc("db_max_lifetime_closed_total", "Connections closed as they reached their lifetime.",
+		func() float64 { return float64(db.Stats().MaxLifetimeClosed) })
+}
+
+// observeDispatcher exposes the events asynchronous subscribers missed.
+func (m *serverMetrics) observeDispatcher(dispatcher events.Dispatcher) {
+	m.registry.CounterFunc("forum_events_dropped_total", "Events dropped as an asynchronous subscriber lagged behind.",
+		func() float64 { return float64(dispatcher.Dropped()) })
+}
+
+// countEvents is a subscriber keeping the business counters.
+func (m *serverMetrics) countEvents(ctx context.Context, event events.Event) error {
+	m.events.With(event.Name()).Inc()
+	switch event := event.(type) {
+	case *events.PostsCreated:
+		m.postsCreated.Add(float64(len(event.Posts)))
+	case *events.VoteCast:
+		m.votesCast.Inc()
+	}
+	return nil
+}
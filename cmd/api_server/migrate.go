@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/forum-api-back/internal/pkg/config"
+	"github.com/forum-api-back/internal/pkg/migrations"
+)
+
+const migrateUsage = "usage: migrate up [version] | down [steps] | status | version | force <version> | durability"
+
+func runMigrate(cfg *config.Config, args []string) error {
+	if cfg.Database.Driver != config.DriverPostgres {
+		return fmt.Errorf("migrations are only supported by the %s driver", config.DriverPostgres)
+	}
+	if len(args) == 0 || len(args) > 2 {
+		return fmt.Errorf(migrateUsage)
+	}
+
+	var argument int
+	if len(args) == 2 {
+		parsed, err := strconv.Atoi(args[1])
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("bad argument %q, %s", args[1], migrateUsage)
+		}
+		argument = parsed
+	}
+
+	postgreSqlConn, err := openPostgresql(&cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer postgreSqlConn.Close()
+
+	ctx := context.Background()
+	migrator := newMigrator(postgreSqlConn, &cfg.Database)
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx, argument)
+		for _, migration := range applied {
+			fmt.Printf("applied %d_%s\n", migration.Version, migration.Name)
+		}
+		return err
+	case "down":
+		if argument == 0 {
+			argument = 1
+		}
+		reverted, err := migrator.Down(ctx, argument)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %d_%s\n", migration.Version, migration.Name)
+		}
+		return err
+	case "status":
+		applied, err := migrator.Applied(ctx)
+		if err != nil {
+			return err
+		}
+		appliedAt := make(map[int]string, len(applied))
+		for _, migration := range applied {
+			appliedAt[migration.Version] = migration.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		for _, migration := range migrations.All() {
+			status, ok := appliedAt[migration.Version]
+			if !ok {
+				status = "pending"
+			}
+			fmt.Printf("%d_%s\t%s\n", migration.Version, migration.Name, status)
+		}
+		return nil
+	case "version":
+		version, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("current %d, latest %d\n", version, migrations.Latest())
+		return nil
+	case "durability":
+		if err := migrator.SetDurability(ctx); err != nil {
+			return err
+		}
+		fmt.Printf("tables are %s\n", cfg.Database.Durability)
+		return nil
+	case "force":
+		if len(args) != 2 {
+			return fmt.Errorf(migrateUsage)
+		}
+		return migrator.Force(ctx, argument)
+	default:
+		return fmt.Errorf(migrateUsage)
+	}
+}
+
+func newMigrator(db *sql.DB, cfg *config.DatabaseConfig) *migrations.Migrator {
+	return migrations.NewMigrator(db, migrations.Durability(cfg.Durability))
+}
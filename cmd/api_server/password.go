@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/forum-api-back/internal/pkg/config"
+	"github.com/forum-api-back/internal/pkg/models"
+	user_repo "github.com/forum-api-back/internal/pkg/user/repository"
+	"github.com/forum-api-back/pkg/tools/auth_utils"
+)
+
+const passwordUsage = "usage: password <nickname>, reading the password from the first line of stdin"
+
+// runPassword sets the password of a user from the server, which is how
+// users created without one, admins among them, get their first.
+func runPassword(cfg *config.Config, args []string, stdin io.Reader) error {
+	if cfg.Database.Driver != config.DriverPostgres {
+		return fmt.Errorf("passwords can only be set with the %s driver", config.DriverPostgres)
+	}
+	if len(args) != 1 {
+		return fmt.Errorf(passwordUsage)
+	}
+
+	password, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+	password = strings.TrimRight(password, "\r\n")
+	if password == "" {
+		return fmt.Errorf("empty password, %s", passwordUsage)
+	}
+
+	postgreSqlConn, err := openPostgresql(&cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer postgreSqlConn.Close()
+
+	ctx := context.Background()
+	userRepo := user_repo.NewSessionPostgresqlRepository(postgreSqlConn)
+	selectedUser, err := userRepo.SelectUserByNickName(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("can't find user %s: %v", args[0], err)
+	}
+	passwordHash, err := auth_utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	if err := userRepo.UpdateUserProfile(ctx, &models.User{NickName: selectedUser.NickName, PasswordHash: passwordHash}); err != nil {
+		return err
+	}
+	fmt.Printf("password of %s is set\n", selectedUser.NickName)
+	return nil
+}
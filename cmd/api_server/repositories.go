@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/admin"
+	admin_repo "github.com/forum-api-back/internal/pkg/admin/repository"
+	"github.com/forum-api-back/internal/pkg/auth"
+	auth_repo "github.com/forum-api-back/internal/pkg/auth/repository"
+	"github.com/forum-api-back/internal/pkg/cache"
+	"github.com/forum-api-back/internal/pkg/config"
+	"github.com/forum-api-back/internal/pkg/forum"
+	forum_repo "github.com/forum-api-back/internal/pkg/forum/repository"
+	"github.com/forum-api-back/internal/pkg/notifications"
+	notifications_repo "github.com/forum-api-back/internal/pkg/notifications/repository"
+	"github.com/forum-api-back/internal/pkg/post"
+	post_repo "github.com/forum-api-back/internal/pkg/post/repository"
+	"github.com/forum-api-back/internal/pkg/roles"
+	roles_repo "github.com/forum-api-back/internal/pkg/roles/repository"
+	"github.com/forum-api-back/internal/pkg/search"
+	search_repo "github.com/forum-api-back/internal/pkg/search/repository"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+	"github.com/forum-api-back/internal/pkg/thread"
+	thread_repo "github.com/forum-api-back/internal/pkg/thread/repository"
+	"github.com/forum-api-back/internal/pkg/user"
+	user_repo "github.com/forum-api-back/internal/pkg/user/repository"
+	"github.com/forum-api-back/internal/pkg/webhooks"
+	webhooks_repo "github.com/forum-api-back/internal/pkg/webhooks/repository"
+	"github.com/forum-api-back/pkg/metrics"
+
+	_ "github.com/lib/pq"
+)
+
+type repositories struct {
+	user          user.Repository
+	forum         forum.Repository
+	post          post.Repository
+	thread        thread.Repository
+	admin         admin.Repository
+	search        search.Repository
+	auth          auth.Repository
+	roles         roles.Repository
+	webhooks      webhooks.Repository
+	notifications notifications.Repository
+}
+
+func newPostgresqlRepositories(db *sql.DB) *repositories {
+	return &repositories{
+		user:          user_repo.NewSessionPostgresqlRepository(db),
+		forum:         forum_repo.NewSessionPostgresqlRepository(db),
+		post:          post_repo.NewSessionPostgresqlRepository(db),
+		thread:        thread_repo.NewSessionPostgresqlRepository(db),
+		admin:         admin_repo.NewSessionPostgresqlRepository(db),
+		search:        search_repo.NewSessionPostgresqlRepository(db),
+		auth:          auth_repo.NewSessionPostgresqlRepository(db),
+		roles:         roles_repo.NewSessionPostgresqlRepository(db),
+		webhooks:      webhooks_repo.NewSessionPostgresqlRepository(db),
+		notifications: notifications_repo.NewSessionPostgresqlRepository(db),
+	}
+}
+
+func newMemoryRepositories(storage *memory.Storage) *repositories {
+	return &repositories{
+		user:          user_repo.NewSessionMemoryRepository(storage),
+		forum:         forum_repo.NewSessionMemoryRepository(storage),
+		post:          post_repo.NewSessionMemoryRepository(storage),
+		thread:        thread_repo.NewSessionMemoryRepository(storage),
+		admin:         admin_repo.NewSessionMemoryRepository(storage),
+		search:        search_repo.NewSessionMemoryRepository(storage),
+		auth:          auth_repo.NewSessionMemoryRepository(storage),
+		roles:         roles_repo.NewSessionMemoryRepository(storage),
+		webhooks:      webhooks_repo.NewSessionMemoryRepository(storage),
+		notifications: notifications_repo.NewSessionMemoryRepository(storage),
+	}
+}
+
+// measured observes the durations of every repository method into
+// durations.
+func (r *repositories) measured(durations *metrics.HistogramVec, slowQuery time.Duration) {
+	r.user = user_repo.NewMeasuredRepository(r.user, durations, slowQuery)
+	r.forum = forum_repo.NewMeasuredRepository(r.forum, durations, slowQuery)
+	r.post = post_repo.NewMeasuredRepository(r.post, durations, slowQuery)
+	r.thread = thread_repo.NewMeasuredRepository(r.thread, durations, slowQuery)
+	r.admin = admin_repo.NewMeasuredRepository(r.admin, durations, slowQuery)
+	r.search = search_repo.NewMeasuredRepository(r.search, durations, slowQuery)
+	r.auth = auth_repo.NewMeasuredRepository(r.auth, durations, slowQuery)
+	r.roles = roles_repo.NewMeasuredRepository(r.roles, durations, slowQuery)
+	r.webhooks = webhooks_repo.NewMeasuredRepository(r.webhooks, durations, slowQuery)
+	r.notifications = notifications_repo.NewMeasuredRepository(r.notifications, durations, slowQuery)
+}
+
+// traced records spans of the repository calls made for traced requests.
+func (r *repositories) traced() {
+	r.user = user_repo.NewTracedRepository(r.user)
+	r.forum = forum_repo.NewTracedRepository(r.forum)
+	r.post = post_repo.NewTracedRepository(r.post)
+	r.thread = thread_repo.NewTracedRepository(r.thread)
+	r.admin = admin_repo.NewTracedRepository(r.admin)
+	r.search = search_repo.NewTracedRepository(r.search)
+	r.auth = auth_repo.NewTracedRepository(r.auth)
+	r.roles = roles_repo.NewTracedRepository(r.roles)
+	r.webhooks = webhooks_repo.NewTracedRepository(r.webhooks)
+	r.notifications = notifications_repo.NewTracedRepository(r.notifications)
+}
+
+// withCache reads forums, threads and users through c and drops what the
+// other repositories change from it.
+func (r *repositories) withCache(c cache.Cache) {
+	r.forum = forum_repo.NewCachedRepository(r.forum, c)
+	r.thread = thread_repo.NewCachedRepository(r.thread, c)
+	r.user = user_repo.NewCachedRepository(r.user, c)
+	r.post = post_repo.NewCachedRepository(r.post, c)
+	r.admin = admin_repo.NewCachedRepository(r.admin, c)
+}
+
+func openPostgresql(cfg *config.DatabaseConfig) (*sql.DB, error) {
+	postgreSqlConn, err := sql.Open("postgres", cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+	postgreSqlConn.SetMaxOpenConns(cfg.MaxOpenConns)
+	postgreSqlConn.SetMaxIdleConns(cfg.MaxIdleConns)
+	postgreSqlConn.SetConnMaxLifetime(cfg.ConnMaxLifetime.Duration)
+	postgreSqlConn.SetConnMaxIdleTime(cfg.ConnMaxIdleTime.Duration)
+
+	pingCtx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout.Duration)
+	defer cancel()
+	if err := postgreSqlConn.PingContext(pingCtx); err != nil {
+		postgreSqlConn.Close()
+		return nil, err
+	}
+
+	return postgreSqlConn, nil
+}
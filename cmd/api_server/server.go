@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/config"
+	"github.com/forum-api-back/internal/pkg/health"
+	"github.com/forum-api-back/pkg/logger"
+	"github.com/forum-api-back/pkg/tools/http_utils"
+
+	"github.com/valyala/fasthttp"
+)
+
+// serve runs the server until it fails or the process receives SIGINT or
+// SIGTERM. On a signal the server is reported as not ready, keeps serving
+// for the shutdown delay and then drains in-flight requests for at most
+// the drain timeout. Streams are closed before draining, as they would
+// hold their connections open until the timeout.
+func serve(server *fasthttp.Server, cfg *config.ServerConfig, healthState *health.State, streams io.Closer) error {
+	listener, err := net.Listen("tcp", cfg.ListenAddr)
+	if err != nil {
+		return err
+	}
+
+	serverErrors := make(chan error, 1)
+	go func() {
+		serverErrors <- server.Serve(http_utils.NewWatchedListener(listener))
+	}()
+	healthState.SetReady(true)
+	logger.Default().Info("listening", "addr", listener.Addr().String())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	select {
+	case err := <-serverErrors:
+		healthState.SetReady(false)
+		streams.Close()
+		return err
+	case sig := <-signals:
+		logger.Default().Info("shutting down", "signal", sig.String())
+	}
+
+	healthState.SetReady(false)
+	if cfg.ShutdownDelay.Duration > 0 {
+		time.Sleep(cfg.ShutdownDelay.Duration)
+	}
+	streams.Close()
+
+	shutdownErrors := make(chan error, 1)
+	go func() {
+		shutdownErrors <- server.Shutdown()
+	}()
+
+	select {
+	case err := <-shutdownErrors:
+		return err
+	case <-time.After(cfg.DrainTimeout.Duration):
+		return fmt.Errorf("server wasn't drained in %s", cfg.DrainTimeout.Duration)
+	}
+}
@@ -6,4 +6,5 @@ require (
 	github.com/fasthttp/router v1.3.14
 	github.com/lib/pq v1.10.2
 	github.com/valyala/fasthttp v1.26.0
+	golang.org/x/crypto v0.0.0-20211117183948-ae814b36b871
 )
@@ -5,4 +5,8 @@ import "github.com/valyala/fasthttp"
 type Handler interface {
 	ClearBase(ctx *fasthttp.RequestCtx)
 	GetBaseDetails(ctx *fasthttp.RequestCtx)
+	GetCacheStats(ctx *fasthttp.RequestCtx)
+	GetMaintenance(ctx *fasthttp.RequestCtx)
+	SetMaintenance(ctx *fasthttp.RequestCtx)
+	GetHealth(ctx *fasthttp.RequestCtx)
 }
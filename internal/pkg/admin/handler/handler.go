@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/forum-api-back/internal/pkg/admin"
+	"github.com/forum-api-back/internal/pkg/models"
 	"github.com/forum-api-back/pkg/errors"
 	"github.com/forum-api-back/pkg/tools/http_utils"
 
@@ -21,21 +23,66 @@ func NewHandler(adminUCase admin.UseCase) admin.Handler {
 }
 
 func (h *AdminHandler) ClearBase(ctx *fasthttp.RequestCtx) {
-	err := h.AdminUCase.ClearBase()
-	switch err {
-	case nil:
-		http_utils.SetJSONResponse(ctx, struct{}{}, http.StatusOK)
-	default:
-		http_utils.SetJSONResponse(ctx, errors.ErrInternalError, http.StatusInternalServerError)
+	if err := h.AdminUCase.ClearBase(http_utils.Context(ctx)); err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
 	}
+
+	http_utils.SetJSONResponse(ctx, struct{}{}, http.StatusOK)
 }
 
 func (h *AdminHandler) GetBaseDetails(ctx *fasthttp.RequestCtx) {
-	baseDetails, err := h.AdminUCase.GetBaseDetails()
-	switch err {
-	case nil:
-		http_utils.SetJSONResponse(ctx, baseDetails, http.StatusOK)
-	default:
-		http_utils.SetJSONResponse(ctx, errors.ErrInternalError, http.StatusInternalServerError)
+	baseDetails, err := h.AdminUCase.GetBaseDetails(http_utils.Context(ctx))
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, baseDetails, http.StatusOK)
+}
+
+func (h *AdminHandler) GetCacheStats(ctx *fasthttp.RequestCtx) {
+	stats, err := h.AdminUCase.GetCacheStats(http_utils.Context(ctx))
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, stats, http.StatusOK)
+}
+
+func (h *AdminHandler) GetMaintenance(ctx *fasthttp.RequestCtx) {
+	maintenance, err := h.AdminUCase.GetMaintenance(http_utils.Context(ctx))
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, maintenance, http.StatusOK)
+}
+
+func (h *AdminHandler) SetMaintenance(ctx *fasthttp.RequestCtx) {
+	maintenance := &models.Maintenance{}
+	if err := json.Unmarshal(ctx.PostBody(), maintenance); err != nil {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadRequest.Wrap(err))
+		return
+	}
+
+	maintenance, err := h.AdminUCase.SetMaintenance(http_utils.Context(ctx), maintenance)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
 	}
+
+	http_utils.SetJSONResponse(ctx, maintenance, http.StatusOK)
+}
+
+func (h *AdminHandler) GetHealth(ctx *fasthttp.RequestCtx) {
+	health, err := h.AdminUCase.GetHealth(http_utils.Context(ctx))
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, health, http.StatusOK)
 }
@@ -1,8 +1,13 @@
 package admin
 
-import "github.com/forum-api-back/internal/pkg/models"
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
 
 type Repository interface {
-	ClearBase() error
-	SelectBaseDetails() (*models.BaseDetails, error)
+	ClearBase(ctx context.Context) error
+	SelectBaseDetails(ctx context.Context) (*models.BaseDetails, error)
+	SelectDurability(ctx context.Context) (string, error)
 }
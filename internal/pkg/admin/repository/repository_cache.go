@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/admin"
+	"github.com/forum-api-back/internal/pkg/cache"
+)
+
+// CachedRepository empties the cache of hot reads with the base.
+type CachedRepository struct {
+	admin.Repository
+	cache cache.Cache
+}
+
+func NewCachedRepository(repo admin.Repository, c cache.Cache) admin.Repository {
+	return &CachedRepository{
+		Repository: repo,
+		cache:      c,
+	}
+}
+
+func (r *CachedRepository) ClearBase(ctx context.Context) error {
+	err := r.Repository.ClearBase(ctx)
+	r.cache.Clear(ctx)
+	return err
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"github.com/forum-api-back/internal/pkg/admin"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+)
+
+type MemoryRepository struct {
+	storage *memory.Storage
+}
+
+func NewSessionMemoryRepository(storage *memory.Storage) admin.Repository {
+	return &MemoryRepository{
+		storage: storage,
+	}
+}
+
+func (r *MemoryRepository) ClearBase(ctx context.Context) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	r.storage.Clear()
+	return nil
+}
+
+func (r *MemoryRepository) SelectBaseDetails(ctx context.Context) (*models.BaseDetails, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	var posts uint64
+	for _, selectedPost := range r.storage.Posts {
+		if !selectedPost.IsDeleted {
+			posts++
+		}
+	}
+
+	return &models.BaseDetails{
+		User:   uint64(len(r.storage.Users)),
+		Forum:  uint64(len(r.storage.Forums)),
+		Thread: uint64(len(r.storage.Threads)),
+		Post:   posts,
+	}, nil
+}
+
+func (r *MemoryRepository) SelectDurability(ctx context.Context) (string, error) {
+	return "memory", nil
+}
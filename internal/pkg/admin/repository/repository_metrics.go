@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/admin"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/pkg/logger"
+	"github.com/forum-api-back/pkg/metrics"
+)
+
+// MeasuredRepository observes how long every method takes and logs the
+// calls taking slowQuery or longer.
+type MeasuredRepository struct {
+	repo      admin.Repository
+	durations *metrics.HistogramVec
+	slowQuery time.Duration
+}
+
+// NewMeasuredRepository observes the durations of the methods of repo into
+// durations, labeled with the repository and the method. Calls taking
+// slowQuery or longer are logged as well, unless it is zero.
+func NewMeasuredRepository(repo admin.Repository, durations *metrics.HistogramVec,
+	slowQuery time.Duration) admin.Repository {
+	return &MeasuredRepository{
+		repo:      repo,
+		durations: durations,
+		slowQuery: slowQuery,
+	}
+}
+
+func (r *MeasuredRepository) ClearBase(ctx context.Context) error {
+	defer r.observe(ctx, "ClearBase", time.Now())
+	return r.repo.ClearBase(ctx)
+}
+
+func (r *MeasuredRepository) SelectBaseDetails(ctx context.Context) (*models.BaseDetails, error) {
+	defer r.observe(ctx, "SelectBaseDetails", time.Now())
+	return r.repo.SelectBaseDetails(ctx)
+}
+
+func (r *MeasuredRepository) SelectDurability(ctx context.Context) (string, error) {
+	defer r.observe(ctx, "SelectDurability", time.Now())
+	return r.repo.SelectDurability(ctx)
+}
+
+func (r *MeasuredRepository) observe(ctx context.Context, method string, start time.Time) {
+	elapsed := time.Since(start)
+	r.durations.With("admin", method).Observe(elapsed.Seconds())
+	if r.slowQuery > 0 && elapsed >= r.slowQuery {
+		logger.FromContext(ctx).Warn("slow query", "repository", "admin", "method", method, "duration", elapsed)
+	}
+}
@@ -1,11 +1,13 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/forum-api-back/internal/pkg/admin"
 	"github.com/forum-api-back/internal/pkg/models"
 	"github.com/forum-api-back/pkg/errors"
+	"github.com/forum-api-back/pkg/tools/sql_utils"
 )
 
 type PostgresqlRepository struct {
@@ -18,22 +20,19 @@ func NewSessionPostgresqlRepository(db *sql.DB) admin.Repository {
 	}
 }
 
-func (r *PostgresqlRepository) ClearBase() error {
-	_, err := r.db.Exec("TRUNCATE  votes, posts, threads, forums, users CASCADE")
+func (r *PostgresqlRepository) ClearBase(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, "TRUNCATE  votes, posts, threads, forums, users CASCADE")
 
-	if err != nil {
-		return errors.ErrInternalError
-	}
-	return nil
+	return sql_utils.Error(err, errors.ErrNotFoundInDB)
 }
 
-func (r *PostgresqlRepository) SelectBaseDetails() (*models.BaseDetails, error) {
+func (r *PostgresqlRepository) SelectBaseDetails(ctx context.Context) (*models.BaseDetails, error) {
 	baseDetails := &models.BaseDetails{}
-	row := r.db.QueryRow(
-		"SELECT " +
-			"(SELECT COUNT(*) FROM forums) AS forums, " +
-			"(SELECT COUNT(*) FROM threads) AS threads, " +
-			"(SELECT COUNT(*) FROM posts) AS posts, " +
+	row := r.db.QueryRowContext(ctx,
+		"SELECT "+
+			"(SELECT COUNT(*) FROM forums) AS forums, "+
+			"(SELECT COUNT(*) FROM threads) AS threads, "+
+			"(SELECT COUNT(*) FROM posts WHERE NOT is_deleted) AS posts, "+
 			"(SELECT COUNT(*) FROM users) AS users",
 	)
 
@@ -45,8 +44,33 @@ func (r *PostgresqlRepository) SelectBaseDetails() (*models.BaseDetails, error)
 	)
 
 	if err != nil {
-		return nil, errors.ErrInternalError
+		return nil, sql_utils.Error(err, errors.ErrNotFoundInDB)
 	}
 
-	return baseDetails, err
+	return baseDetails, nil
+}
+
+func (r *PostgresqlRepository) SelectDurability(ctx context.Context) (string, error) {
+	row := r.db.QueryRowContext(ctx,
+		"SELECT "+
+			"COUNT(*) FILTER (WHERE c.relpersistence = 'p') AS logged, "+
+			"COUNT(*) FILTER (WHERE c.relpersistence = 'u') AS unlogged "+
+			"FROM pg_class c "+
+			"JOIN pg_namespace n ON (n.oid = c.relnamespace) "+
+			"WHERE n.nspname = current_schema() AND c.relkind = 'r' AND c.relname <> 'schema_version'",
+	)
+
+	var logged, unlogged uint64
+	if err := row.Scan(&logged, &unlogged); err != nil {
+		return "", sql_utils.Error(err, errors.ErrNotFoundInDB)
+	}
+
+	switch {
+	case unlogged == 0:
+		return "logged", nil
+	case logged == 0:
+		return "unlogged", nil
+	default:
+		return "mixed", nil
+	}
 }
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/admin"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/pkg/tracing"
+)
+
+// TracedRepository records a span for every method called within a traced request.
+type TracedRepository struct {
+	repo admin.Repository
+}
+
+func NewTracedRepository(repo admin.Repository) admin.Repository {
+	return &TracedRepository{
+		repo: repo,
+	}
+}
+
+func (r *TracedRepository) ClearBase(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "admin.repository.ClearBase")
+	defer func() { span.End(err) }()
+	return r.repo.ClearBase(ctx)
+}
+
+func (r *TracedRepository) SelectBaseDetails(ctx context.Context) (_ *models.BaseDetails, err error) {
+	ctx, span := tracing.Start(ctx, "admin.repository.SelectBaseDetails")
+	defer func() { span.End(err) }()
+	return r.repo.SelectBaseDetails(ctx)
+}
+
+func (r *TracedRepository) SelectDurability(ctx context.Context) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "admin.repository.SelectDurability")
+	defer func() { span.End(err) }()
+	return r.repo.SelectDurability(ctx)
+}
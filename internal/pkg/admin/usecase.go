@@ -1,8 +1,26 @@
 package admin
 
-import "github.com/forum-api-back/internal/pkg/models"
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
 
 type UseCase interface {
-	ClearBase() error
-	GetBaseDetails() (*models.BaseDetails, error)
+	ClearBase(ctx context.Context) error
+	GetBaseDetails(ctx context.Context) (*models.BaseDetails, error)
+	GetCacheStats(ctx context.Context) (*models.CacheStats, error)
+	GetMaintenance(ctx context.Context) (*models.Maintenance, error)
+	SetMaintenance(ctx context.Context, maintenance *models.Maintenance) (*models.Maintenance, error)
+	// GetHealth returns the readiness of the instance with the results of
+	// its checks, which the public probe leaves out.
+	GetHealth(ctx context.Context) (*models.Health, error)
+}
+
+// Health is the health state of the instance: its maintenance mode and
+// the checks behind its readiness.
+type Health interface {
+	SetMaintenance(maintenance bool)
+	InMaintenance() bool
+	Check(ctx context.Context) *models.Health
 }
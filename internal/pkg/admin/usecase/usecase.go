@@ -1,24 +1,108 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/forum-api-back/internal/pkg/admin"
+	"github.com/forum-api-back/internal/pkg/auth"
+	"github.com/forum-api-back/internal/pkg/cache"
+	"github.com/forum-api-back/internal/pkg/events"
 	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/roles"
+	"github.com/forum-api-back/pkg/logger"
 )
 
 type AdminUseCase struct {
-	AdminRepo admin.Repository
+	AdminRepo  admin.Repository
+	RolesUCase roles.UseCase
+	Events     events.Publisher
+	// Cache is nil when hot reads are not cached.
+	Cache  cache.Cache
+	Health admin.Health
 }
 
-func NewUseCase(adminRepo admin.Repository) admin.UseCase {
+func NewUseCase(adminRepo admin.Repository, rolesUCase roles.UseCase, publisher events.Publisher,
+	c cache.Cache, health admin.Health) admin.UseCase {
 	return &AdminUseCase{
-		AdminRepo: adminRepo,
+		AdminRepo:  adminRepo,
+		RolesUCase: rolesUCase,
+		Events:     publisher,
+		Cache:      c,
+		Health:     health,
+	}
+}
+
+func (u *AdminUseCase) ClearBase(ctx context.Context) error {
+	if err := u.RolesUCase.Authorize(ctx, "", models.RoleAdmin); err != nil {
+		return err
+	}
+
+	if err := u.AdminRepo.ClearBase(ctx); err != nil {
+		return err
+	}
+	u.Events.Publish(ctx, &events.BaseCleared{})
+
+	return nil
+}
+
+func (u *AdminUseCase) GetBaseDetails(ctx context.Context) (*models.BaseDetails, error) {
+	if err := u.RolesUCase.Authorize(ctx, "", models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	baseDetails, err := u.AdminRepo.SelectBaseDetails(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	baseDetails.Durability, err = u.AdminRepo.SelectDurability(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return baseDetails, nil
+}
+
+func (u *AdminUseCase) GetCacheStats(ctx context.Context) (*models.CacheStats, error) {
+	if err := u.RolesUCase.Authorize(ctx, "", models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	if u.Cache == nil {
+		return &models.CacheStats{}, nil
 	}
+	return u.Cache.Stats(), nil
 }
 
-func (u *AdminUseCase) ClearBase() error {
-	return u.AdminRepo.ClearBase()
+func (u *AdminUseCase) GetMaintenance(ctx context.Context) (*models.Maintenance, error) {
+	if err := u.RolesUCase.Authorize(ctx, "", models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	return &models.Maintenance{Enabled: u.Health.InMaintenance()}, nil
 }
 
-func (u *AdminUseCase) GetBaseDetails() (*models.BaseDetails, error) {
-	return u.AdminRepo.SelectBaseDetails()
+// SetMaintenance switches the maintenance mode of this instance only, the
+// others sharing the database keep theirs.
+func (u *AdminUseCase) SetMaintenance(ctx context.Context,
+	maintenance *models.Maintenance) (*models.Maintenance, error) {
+	if err := u.RolesUCase.Authorize(ctx, "", models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	if u.Health.InMaintenance() != maintenance.Enabled {
+		u.Health.SetMaintenance(maintenance.Enabled)
+		caller, _ := auth.Caller(ctx)
+		logger.FromContext(ctx).Warn("maintenance mode switched", "enabled", maintenance.Enabled, "by", caller)
+	}
+
+	return &models.Maintenance{Enabled: u.Health.InMaintenance()}, nil
+}
+
+func (u *AdminUseCase) GetHealth(ctx context.Context) (*models.Health, error) {
+	if err := u.RolesUCase.Authorize(ctx, "", models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	return u.Health.Check(ctx), nil
 }
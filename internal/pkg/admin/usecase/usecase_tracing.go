@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/admin"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/pkg/tracing"
+)
+
+// TracedUseCase records a span for every method called within a traced request.
+type TracedUseCase struct {
+	ucase admin.UseCase
+}
+
+func NewTracedUseCase(ucase admin.UseCase) admin.UseCase {
+	return &TracedUseCase{
+		ucase: ucase,
+	}
+}
+
+func (u *TracedUseCase) ClearBase(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "admin.usecase.ClearBase")
+	defer func() { span.End(err) }()
+	return u.ucase.ClearBase(ctx)
+}
+
+func (u *TracedUseCase) GetBaseDetails(ctx context.Context) (_ *models.BaseDetails, err error) {
+	ctx, span := tracing.Start(ctx, "admin.usecase.GetBaseDetails")
+	defer func() { span.End(err) }()
+	return u.ucase.GetBaseDetails(ctx)
+}
+
+func (u *TracedUseCase) GetCacheStats(ctx context.Context) (_ *models.CacheStats, err error) {
+	ctx, span := tracing.Start(ctx, "admin.usecase.GetCacheStats")
+	defer func() { span.End(err) }()
+	return u.ucase.GetCacheStats(ctx)
+}
+
+func (u *TracedUseCase) GetMaintenance(ctx context.Context) (_ *models.Maintenance, err error) {
+	ctx, span := tracing.Start(ctx, "admin.usecase.GetMaintenance")
+	defer func() { span.End(err) }()
+	return u.ucase.GetMaintenance(ctx)
+}
+
+func (u *TracedUseCase) SetMaintenance(ctx context.Context,
+	maintenance *models.Maintenance) (_ *models.Maintenance, err error) {
+	ctx, span := tracing.Start(ctx, "admin.usecase.SetMaintenance")
+	defer func() { span.End(err) }()
+	return u.ucase.SetMaintenance(ctx, maintenance)
+}
+
+func (u *TracedUseCase) GetHealth(ctx context.Context) (_ *models.Health, err error) {
+	ctx, span := tracing.Start(ctx, "admin.usecase.GetHealth")
+	defer func() { span.End(err) }()
+	return u.ucase.GetHealth(ctx)
+}
@@ -0,0 +1,19 @@
+package auth
+
+import "context"
+
+type callerKey struct{}
+
+// WithCaller returns a context carrying the nickname of the authenticated
+// user a request is made by.
+func WithCaller(ctx context.Context, nickname string) context.Context {
+	return context.WithValue(ctx, callerKey{}, nickname)
+}
+
+// Caller returns the nickname set by WithCaller. Requests without a
+// session have no caller and keep naming users without a password in
+// their bodies.
+func Caller(ctx context.Context) (string, bool) {
+	nickname, ok := ctx.Value(callerKey{}).(string)
+	return nickname, ok
+}
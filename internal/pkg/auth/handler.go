@@ -0,0 +1,9 @@
+package auth
+
+import "github.com/valyala/fasthttp"
+
+type Handler interface {
+	Login(ctx *fasthttp.RequestCtx)
+	Logout(ctx *fasthttp.RequestCtx)
+	Authenticate(next fasthttp.RequestHandler, required bool) fasthttp.RequestHandler
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/auth"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/pkg/errors"
+	"github.com/forum-api-back/pkg/tools/http_utils"
+
+	"github.com/valyala/fasthttp"
+)
+
+const sessionCookie = "session"
+
+var bearerPrefix = []byte("Bearer ")
+
+type AuthHandler struct {
+	AuthUCase auth.UseCase
+}
+
+func NewHandler(authUCase auth.UseCase) auth.Handler {
+	return &AuthHandler{
+		AuthUCase: authUCase,
+	}
+}
+
+func (h *AuthHandler) Login(ctx *fasthttp.RequestCtx) {
+	credentials := &models.Credentials{}
+	if err := json.Unmarshal(ctx.PostBody(), credentials); err != nil {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadRequest.Wrap(err))
+		return
+	}
+
+	details := make([]*errors.FieldError, 0)
+	if credentials.NickName == "" {
+		details = append(details, &errors.FieldError{Field: "nickname", Message: "must not be empty"})
+	}
+	if credentials.Password == "" {
+		details = append(details, &errors.FieldError{Field: "password", Message: "must not be empty"})
+	}
+	if len(details) != 0 {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(details...))
+		return
+	}
+
+	newSession, err := h.AuthUCase.Login(http_utils.Context(ctx), credentials)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	setSessionCookie(ctx, newSession.Token, newSession.ExpiresAt)
+	http_utils.SetJSONResponse(ctx, newSession, http.StatusOK)
+}
+
+func (h *AuthHandler) Logout(ctx *fasthttp.RequestCtx) {
+	token := sessionToken(ctx)
+	if token == "" {
+		http_utils.SetErrorResponse(ctx, errors.ErrUnauthorized)
+		return
+	}
+
+	if err := h.AuthUCase.Logout(http_utils.Context(ctx), token); err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	setSessionCookie(ctx, "", fasthttp.CookieExpireDelete)
+	http_utils.SetJSONResponse(ctx, struct{}{}, http.StatusOK)
+}
+
+// Authenticate resolves the session token of a request, if there is one,
+// and passes the nickname it belongs to down to next through the request
+// context. Requests with a bad token are rejected; requests without one
+// are only rejected when required is set.
+func (h *AuthHandler) Authenticate(next fasthttp.RequestHandler, required bool) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		token := sessionToken(ctx)
+		if token == "" {
+			if required {
+				http_utils.SetErrorResponse(ctx, errors.ErrUnauthorized)
+				return
+			}
+			next(ctx)
+			return
+		}
+
+		requestCtx := http_utils.Context(ctx)
+		nickname, err := h.AuthUCase.Authenticate(requestCtx, token)
+		if err != nil {
+			http_utils.SetErrorResponse(ctx, err)
+			return
+		}
+
+		http_utils.SetContext(ctx, auth.WithCaller(requestCtx, nickname))
+		next(ctx)
+	}
+}
+
+func setSessionCookie(ctx *fasthttp.RequestCtx, token string, expires time.Time) {
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+
+	cookie.SetKey(sessionCookie)
+	cookie.SetValue(token)
+	cookie.SetPath("/")
+	cookie.SetExpire(expires)
+	cookie.SetHTTPOnly(true)
+	cookie.SetSameSite(fasthttp.CookieSameSiteStrictMode)
+	ctx.Response.Header.SetCookie(cookie)
+}
+
+// sessionToken takes the token from the Authorization header, falling
+// back to the cookie set on login.
+func sessionToken(ctx *fasthttp.RequestCtx) string {
+	if header := ctx.Request.Header.Peek(fasthttp.HeaderAuthorization); bytes.HasPrefix(header, bearerPrefix) {
+		return string(bytes.TrimSpace(header[len(bearerPrefix):]))
+	}
+	return string(ctx.Request.Header.Cookie(sessionCookie))
+}
@@ -0,0 +1,14 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
+
+type Repository interface {
+	InsertSession(ctx context.Context, session *models.Session) error
+	SelectSession(ctx context.Context, sessionId string) (*models.Session, error)
+	DeleteSession(ctx context.Context, sessionId string) error
+	DeleteExpiredSessions(ctx context.Context, nickname string) error
+}
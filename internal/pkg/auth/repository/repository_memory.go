@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/auth"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+	"github.com/forum-api-back/pkg/errors"
+)
+
+type MemoryRepository struct {
+	storage *memory.Storage
+}
+
+func NewSessionMemoryRepository(storage *memory.Storage) auth.Repository {
+	return &MemoryRepository{
+		storage: storage,
+	}
+}
+
+func (r *MemoryRepository) InsertSession(ctx context.Context, session *models.Session) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	selectedUser, ok := r.storage.UserByNickName(session.NickName)
+	if !ok {
+		return errors.ErrDataConflict
+	}
+	if _, ok := r.storage.Sessions[session.Id]; ok {
+		return errors.ErrDataConflict
+	}
+
+	newSession := *session
+	newSession.Token = ""
+	newSession.NickName = selectedUser.NickName
+	r.storage.Sessions[session.Id] = &newSession
+
+	return nil
+}
+
+func (r *MemoryRepository) SelectSession(ctx context.Context, sessionId string) (*models.Session, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	selectedSession, ok := r.storage.Sessions[sessionId]
+	if !ok || !selectedSession.ExpiresAt.After(time.Now()) {
+		return nil, errors.ErrNotFoundInDB
+	}
+	if _, ok := r.storage.UserByNickName(selectedSession.NickName); !ok {
+		return nil, errors.ErrNotFoundInDB
+	}
+
+	copiedSession := *selectedSession
+	return &copiedSession, nil
+}
+
+func (r *MemoryRepository) DeleteSession(ctx context.Context, sessionId string) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	delete(r.storage.Sessions, sessionId)
+	return nil
+}
+
+func (r *MemoryRepository) DeleteExpiredSessions(ctx context.Context, nickname string) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	now := time.Now()
+	for sessionId, selectedSession := range r.storage.Sessions {
+		if memory.Key(selectedSession.NickName) == memory.Key(nickname) && !selectedSession.ExpiresAt.After(now) {
+			delete(r.storage.Sessions, sessionId)
+		}
+	}
+	return nil
+}
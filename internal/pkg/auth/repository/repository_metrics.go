@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/auth"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/pkg/logger"
+	"github.com/forum-api-back/pkg/metrics"
+)
+
+// MeasuredRepository observes how long every method takes and logs the
+// calls taking slowQuery or longer.
+type MeasuredRepository struct {
+	repo      auth.Repository
+	durations *metrics.HistogramVec
+	slowQuery time.Duration
+}
+
+// NewMeasuredRepository observes the durations of the methods of repo into
+// durations, labeled with the repository and the method. Calls taking
+// slowQuery or longer are logged as well, unless it is zero.
+func NewMeasuredRepository(repo auth.Repository, durations *metrics.HistogramVec,
+	slowQuery time.Duration) auth.Repository {
+	return &MeasuredRepository{
+		repo:      repo,
+		durations: durations,
+		slowQuery: slowQuery,
+	}
+}
+
+func (r *MeasuredRepository) InsertSession(ctx context.Context, session *models.Session) error {
+	defer r.observe(ctx, "InsertSession", time.Now())
+	return r.repo.InsertSession(ctx, session)
+}
+
+func (r *MeasuredRepository) SelectSession(ctx context.Context, sessionId string) (*models.Session, error) {
+	defer r.observe(ctx, "SelectSession", time.Now())
+	return r.repo.SelectSession(ctx, sessionId)
+}
+
+func (r *MeasuredRepository) DeleteSession(ctx context.Context, sessionId string) error {
+	defer r.observe(ctx, "DeleteSession", time.Now())
+	return r.repo.DeleteSession(ctx, sessionId)
+}
+
+func (r *MeasuredRepository) DeleteExpiredSessions(ctx context.Context, nickname string) error {
+	defer r.observe(ctx, "DeleteExpiredSessions", time.Now())
+	return r.repo.DeleteExpiredSessions(ctx, nickname)
+}
+
+func (r *MeasuredRepository) observe(ctx context.Context, method string, start time.Time) {
+	elapsed := time.Since(start)
+	r.durations.With("auth", method).Observe(elapsed.Seconds())
+	if r.slowQuery > 0 && elapsed >= r.slowQuery {
+		logger.FromContext(ctx).Warn("slow query", "repository", "auth", "method", method, "duration", elapsed)
+	}
+}
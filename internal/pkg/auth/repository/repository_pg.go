@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/forum-api-back/internal/pkg/auth"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/pkg/errors"
+	"github.com/forum-api-back/pkg/tools/sql_utils"
+)
+
+type PostgresqlRepository struct {
+	db *sql.DB
+}
+
+func NewSessionPostgresqlRepository(db *sql.DB) auth.Repository {
+	return &PostgresqlRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresqlRepository) InsertSession(ctx context.Context, session *models.Session) error {
+	_, err := r.db.ExecContext(ctx,
+		"INSERT INTO sessions(id, user_nickname, date_created, expires_at) "+
+			"VALUES ($1, $2, $3, $4)",
+		session.Id,
+		session.NickName,
+		session.DateCreated,
+		session.ExpiresAt,
+	)
+
+	return sql_utils.Error(err, errors.ErrNotFoundInDB)
+}
+
+func (r *PostgresqlRepository) SelectSession(ctx context.Context, sessionId string) (*models.Session, error) {
+	row := r.db.QueryRowContext(ctx,
+		"SELECT id, user_nickname, date_created, expires_at "+
+			"FROM sessions "+
+			"WHERE id = $1 AND expires_at > now()",
+		sessionId,
+	)
+
+	selectedSession := &models.Session{}
+	err := row.Scan(
+		&selectedSession.Id,
+		&selectedSession.NickName,
+		&selectedSession.DateCreated,
+		&selectedSession.ExpiresAt,
+	)
+	if err != nil {
+		return nil, sql_utils.Error(err, errors.ErrNotFoundInDB)
+	}
+
+	return selectedSession, nil
+}
+
+func (r *PostgresqlRepository) DeleteSession(ctx context.Context, sessionId string) error {
+	_, err := r.db.ExecContext(ctx,
+		"DELETE FROM sessions "+
+			"WHERE id = $1",
+		sessionId,
+	)
+
+	return sql_utils.Error(err, errors.ErrNotFoundInDB)
+}
+
+func (r *PostgresqlRepository) DeleteExpiredSessions(ctx context.Context, nickname string) error {
+	_, err := r.db.ExecContext(ctx,
+		"DELETE FROM sessions "+
+			"WHERE user_nickname = $1 AND expires_at <= now()",
+		nickname,
+	)
+
+	return sql_utils.Error(err, errors.ErrNotFoundInDB)
+}
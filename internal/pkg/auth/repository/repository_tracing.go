@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/auth"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/pkg/tracing"
+)
+
+// TracedRepository records a span for every method called within a traced request.
+type TracedRepository struct {
+	repo auth.Repository
+}
+
+func NewTracedRepository(repo auth.Repository) auth.Repository {
+	return &TracedRepository{
+		repo: repo,
+	}
+}
+
+func (r *TracedRepository) InsertSession(ctx context.Context, session *models.Session) (err error) {
+	ctx, span := tracing.Start(ctx, "auth.repository.InsertSession")
+	defer func() { span.End(err) }()
+	return r.repo.InsertSession(ctx, session)
+}
+
+func (r *TracedRepository) SelectSession(ctx context.Context,
+	sessionId string) (_ *models.Session, err error) {
+	ctx, span := tracing.Start(ctx, "auth.repository.SelectSession")
+	defer func() { span.End(err) }()
+	return r.repo.SelectSession(ctx, sessionId)
+}
+
+func (r *TracedRepository) DeleteSession(ctx context.Context, sessionId string) (err error) {
+	ctx, span := tracing.Start(ctx, "auth.repository.DeleteSession")
+	defer func() { span.End(err) }()
+	return r.repo.DeleteSession(ctx, sessionId)
+}
+
+func (r *TracedRepository) DeleteExpiredSessions(ctx context.Context, nickname string) (err error) {
+	ctx, span := tracing.Start(ctx, "auth.repository.DeleteExpiredSessions")
+	defer func() { span.End(err) }()
+	return r.repo.DeleteExpiredSessions(ctx, nickname)
+}
@@ -0,0 +1,13 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
+
+type UseCase interface {
+	Login(ctx context.Context, credentials *models.Credentials) (*models.Session, error)
+	Logout(ctx context.Context, token string) error
+	Authenticate(ctx context.Context, token string) (string, error)
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/auth"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/user"
+	"github.com/forum-api-back/pkg/errors"
+	"github.com/forum-api-back/pkg/tools/auth_utils"
+)
+
+type AuthUseCase struct {
+	AuthRepo   auth.Repository
+	UserRepo   user.Repository
+	Signer     *auth_utils.Signer
+	SessionTTL time.Duration
+}
+
+func NewUseCase(authRepo auth.Repository, userRepo user.Repository,
+	signer *auth_utils.Signer, sessionTTL time.Duration) auth.UseCase {
+	return &AuthUseCase{
+		AuthRepo:   authRepo,
+		UserRepo:   userRepo,
+		Signer:     signer,
+		SessionTTL: sessionTTL,
+	}
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     string
+)
+
+// checkNoPassword takes as long as checking a password does, so unknown
+// nicknames and users without a password can't be told apart by timing.
+func checkNoPassword(password string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = auth_utils.HashPassword("no password")
+	})
+	auth_utils.CheckPassword(dummyHash, password)
+}
+
+func (u *AuthUseCase) Login(ctx context.Context, credentials *models.Credentials) (*models.Session, error) {
+	selectedUser, err := u.UserRepo.SelectUserByNickName(ctx, credentials.NickName)
+	if err != nil {
+		if errors.From(err).Code == errors.CodeNotFound {
+			checkNoPassword(credentials.Password)
+		}
+		return nil, invalidWhenNotFound(err, errors.ErrInvalidCredentials)
+	}
+	if selectedUser.PasswordHash == "" {
+		checkNoPassword(credentials.Password)
+		return nil, errors.ErrInvalidCredentials
+	}
+	if !auth_utils.CheckPassword(selectedUser.PasswordHash, credentials.Password) {
+		return nil, errors.ErrInvalidCredentials
+	}
+
+	if err := u.AuthRepo.DeleteExpiredSessions(ctx, selectedUser.NickName); err != nil {
+		return nil, err
+	}
+
+	sessionId, token, err := u.Signer.NewToken()
+	if err != nil {
+		return nil, errors.ErrInternalError.Wrap(err)
+	}
+
+	now := time.Now()
+	newSession := &models.Session{
+		Id:          sessionId,
+		Token:       token,
+		NickName:    selectedUser.NickName,
+		DateCreated: now,
+		ExpiresAt:   now.Add(u.SessionTTL),
+	}
+	if err := u.AuthRepo.InsertSession(ctx, newSession); err != nil {
+		return nil, err
+	}
+
+	return newSession, nil
+}
+
+func (u *AuthUseCase) Logout(ctx context.Context, token string) error {
+	sessionId, ok := u.Signer.Verify(token)
+	if !ok {
+		return errors.ErrInvalidToken
+	}
+
+	return u.AuthRepo.DeleteSession(ctx, sessionId)
+}
+
+func (u *AuthUseCase) Authenticate(ctx context.Context, token string) (string, error) {
+	sessionId, ok := u.Signer.Verify(token)
+	if !ok {
+		return "", errors.ErrInvalidToken
+	}
+
+	selectedSession, err := u.AuthRepo.SelectSession(ctx, sessionId)
+	if err != nil {
+		return "", invalidWhenNotFound(err, errors.ErrInvalidToken)
+	}
+	if !selectedSession.ExpiresAt.After(time.Now()) {
+		return "", errors.ErrInvalidToken
+	}
+
+	return selectedSession.NickName, nil
+}
+
+// invalidWhenNotFound hides which of the user or the session is missing,
+// so failed logins don't tell whether a nickname is taken.
+func invalidWhenNotFound(err error, invalid *errors.Error) error {
+	if errors.From(err).Code == errors.CodeNotFound {
+		return invalid.Wrap(err)
+	}
+	return err
+}
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/auth"
+	auth_repo "github.com/forum-api-back/internal/pkg/auth/repository"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+	user_repo "github.com/forum-api-back/internal/pkg/user/repository"
+	"github.com/forum-api-back/pkg/errors"
+	"github.com/forum-api-back/pkg/tools/auth_utils"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newTestUseCase(t *testing.T, sessionTTL time.Duration) auth.UseCase {
+	t.Helper()
+
+	storage := memory.NewStorage()
+	userRepo := user_repo.NewSessionMemoryRepository(storage)
+
+	passwordHash, err := auth_utils.HashPassword("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, newUser := range []*models.User{
+		{NickName: "alice", Email: "alice@example.com", PasswordHash: passwordHash},
+		{NickName: "bob", Email: "bob@example.com"},
+	} {
+		if err := userRepo.InsertUser(context.Background(), newUser); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return NewUseCase(auth_repo.NewSessionMemoryRepository(storage), userRepo,
+		auth_utils.NewSigner([]byte("test secret")), sessionTTL)
+}
+
+func TestLogin(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials models.Credentials
+		code        errors.Code
+	}{
+		{"valid", models.Credentials{NickName: "alice", Password: "secret"}, ""},
+		{"other case", models.Credentials{NickName: "ALICE", Password: "secret"}, ""},
+		{"wrong password", models.Credentials{NickName: "alice", Password: "guess"}, errors.CodeUnauthorized},
+		{"unknown user", models.Credentials{NickName: "carol", Password: "secret"}, errors.CodeUnauthorized},
+		{"no password", models.Credentials{NickName: "bob", Password: ""}, errors.CodeUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestUseCase(t, time.Hour)
+			ctx := context.Background()
+
+			session, err := u.Login(ctx, &tt.credentials)
+			if tt.code != "" {
+				if errors.From(err).Code != tt.code {
+					t.Fatalf("Login() error = %v, want code %v", err, tt.code)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Login() error = %v", err)
+			}
+			if session.NickName != "alice" {
+				t.Errorf("session nickname = %q, want %q", session.NickName, "alice")
+			}
+
+			caller, err := u.Authenticate(ctx, session.Token)
+			if err != nil || caller != "alice" {
+				t.Errorf("Authenticate() = %q, %v, want %q", caller, err, "alice")
+			}
+		})
+	}
+}
+
+func TestLoginChecksUnknownUsersAgainstDummyHash(t *testing.T) {
+	u := newTestUseCase(t, time.Hour)
+
+	if _, err := u.Login(context.Background(), &models.Credentials{NickName: "carol", Password: "no password"}); err == nil {
+		t.Fatal("Login() of an unknown user succeeded")
+	}
+	// The dummy hash has to cost as much as the hashes of passwords.
+	cost, err := bcrypt.Cost([]byte(dummyHash))
+	if err != nil {
+		t.Fatalf("dummy hash is invalid: %v", err)
+	}
+	if cost != bcrypt.DefaultCost {
+		t.Errorf("dummy hash cost = %d, want %d", cost, bcrypt.DefaultCost)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	u := newTestUseCase(t, time.Hour)
+	ctx := context.Background()
+
+	session, err := u.Login(ctx, &models.Credentials{NickName: "alice", Password: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Logout(ctx, session.Token); err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+
+	if _, err := u.Authenticate(ctx, session.Token); errors.From(err).Code != errors.CodeUnauthorized {
+		t.Errorf("Authenticate() after logout error = %v, want unauthorized", err)
+	}
+}
+
+func TestAuthenticateRejectsTokens(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(token string) string
+	}{
+		{"empty", func(string) string { return "" }},
+		{"no signature", func(token string) string { return token[:64] }},
+		{"other id", func(token string) string { return flip(token, 0) }},
+		{"other signature", func(token string) string { return flip(token, len(token)-1) }},
+		{"signed with another secret", func(token string) string {
+			_, forged, _ := auth_utils.NewSigner([]byte("other secret")).NewToken()
+			return forged
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestUseCase(t, time.Hour)
+			ctx := context.Background()
+
+			session, err := u.Login(ctx, &models.Credentials{NickName: "alice", Password: "secret"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := u.Authenticate(ctx, tt.tamper(session.Token)); errors.From(err).Code != errors.CodeUnauthorized {
+				t.Errorf("Authenticate() error = %v, want unauthorized", err)
+			}
+		})
+	}
+}
+
+func TestAuthenticateRejectsExpiredSessions(t *testing.T) {
+	u := newTestUseCase(t, -time.Second)
+	ctx := context.Background()
+
+	session, err := u.Login(ctx, &models.Credentials{NickName: "alice", Password: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := u.Authenticate(ctx, session.Token); errors.From(err).Code != errors.CodeUnauthorized {
+		t.Errorf("Authenticate() error = %v, want unauthorized", err)
+	}
+}
+
+// flip changes the character of token at i to another one of its alphabet.
+func flip(token string, i int) string {
+	replacement := byte('a')
+	if token[i] == 'a' {
+		replacement = 'b'
+	}
+	return token[:i] + string(replacement) + token[i+1:]
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/auth"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/pkg/tracing"
+)
+
+// TracedUseCase records a span for every method called within a traced request.
+type TracedUseCase struct {
+	ucase auth.UseCase
+}
+
+func NewTracedUseCase(ucase auth.UseCase) auth.UseCase {
+	return &TracedUseCase{
+		ucase: ucase,
+	}
+}
+
+func (u *TracedUseCase) Login(ctx context.Context,
+	credentials *models.Credentials) (_ *models.Session, err error) {
+	ctx, span := tracing.Start(ctx, "auth.usecase.Login")
+	defer func() { span.End(err) }()
+	return u.ucase.Login(ctx, credentials)
+}
+
+func (u *TracedUseCase) Logout(ctx context.Context, token string) (err error) {
+	ctx, span := tracing.Start(ctx, "auth.usecase.Logout")
+	defer func() { span.End(err) }()
+	return u.ucase.Logout(ctx, token)
+}
+
+func (u *TracedUseCase) Authenticate(ctx context.Context, token string) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "auth.usecase.Authenticate")
+	defer func() { span.End(err) }()
+	return u.ucase.Authenticate(ctx, token)
+}
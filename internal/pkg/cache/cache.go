@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
+
+// Cache keeps copies of values read from repositories. Keys start with a
+// namespace and a colon, statistics are kept by namespace. A failing
+// cache only misses, so its methods return no errors.
+type Cache interface {
+	// Get copies the value stored under key into value, a pointer to a
+	// variable of the type that was stored.
+	Get(ctx context.Context, key string, value interface{}) bool
+	Set(ctx context.Context, key string, value interface{})
+	Delete(ctx context.Context, keys ...string)
+	// DeletePrefix removes the keys starting with prefix.
+	DeletePrefix(ctx context.Context, prefix string)
+	Clear(ctx context.Context)
+	Stats() *models.CacheStats
+}
+
+const (
+	ForumNamespace  = "forum:"
+	ThreadNamespace = "thread:"
+	UserNamespace   = "user:"
+)
+
+// Slugs and nicknames are folded like the CITEXT columns compare them.
+
+func ForumKey(forumSlug string) string {
+	return ForumNamespace + strings.ToLower(forumSlug)
+}
+
+func ThreadKey(threadId uint64) string {
+	return ThreadNamespace + strconv.FormatUint(threadId, 10)
+}
+
+// ThreadSlugKey stores the id of the thread with the slug.
+func ThreadSlugKey(threadSlug string) string {
+	return ThreadNamespace + "slug:" + strings.ToLower(threadSlug)
+}
+
+func UserKey(nickname string) string {
+	return UserNamespace + strings.ToLower(nickname)
+}
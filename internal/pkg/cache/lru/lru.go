@@ -0,0 +1,173 @@
+package lru
+
+import (
+	"container/list"
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/cache"
+	"github.com/forum-api-back/internal/pkg/models"
+)
+
+// Cache is an in-process cache holding up to capacity entries for ttl
+// each. The least recently used entry makes room for new ones.
+type Cache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	items    map[string]*list.Element
+	// order has the most recently used entries in front.
+	order *list.List
+
+	hits       uint64
+	misses     uint64
+	evictions  uint64
+	expired    uint64
+	namespaces map[string]*models.CacheCounters
+}
+
+type entry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+func NewCache(capacity int, ttl time.Duration) cache.Cache {
+	return &Cache{
+		capacity:   capacity,
+		ttl:        ttl,
+		items:      make(map[string]*list.Element),
+		order:      list.New(),
+		namespaces: make(map[string]*models.CacheCounters),
+	}
+}
+
+func (c *Cache) Get(ctx context.Context, key string, value interface{}) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counters := c.counters(key)
+	element, ok := c.items[key]
+	if ok && time.Now().After(element.Value.(*entry).expiresAt) {
+		c.remove(element)
+		c.expired++
+		ok = false
+	}
+	if ok {
+		stored := reflect.ValueOf(element.Value.(*entry).value)
+		target := reflect.ValueOf(value)
+		if target.Kind() != reflect.Ptr || !stored.Type().AssignableTo(target.Elem().Type()) {
+			ok = false
+		} else {
+			target.Elem().Set(stored)
+		}
+	}
+
+	if !ok {
+		c.misses++
+		counters.Misses++
+		return false
+	}
+
+	c.order.MoveToFront(element)
+	c.hits++
+	counters.Hits++
+	return true
+}
+
+func (c *Cache) Set(ctx context.Context, key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := time.Now().Add(c.ttl)
+	if element, ok := c.items[key]; ok {
+		element.Value.(*entry).value = value
+		element.Value.(*entry).expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+		c.evictions++
+	}
+}
+
+func (c *Cache) Delete(ctx context.Context, keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := c.items[key]; ok {
+			c.remove(element)
+		}
+	}
+}
+
+func (c *Cache) DeletePrefix(ctx context.Context, prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, element := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(element)
+		}
+	}
+}
+
+func (c *Cache) Clear(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+func (c *Cache) Stats() *models.CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := &models.CacheStats{
+		Enabled:    true,
+		Capacity:   c.capacity,
+		Entries:    c.order.Len(),
+		Hits:       c.hits,
+		Misses:     c.misses,
+		Evictions:  c.evictions,
+		Expired:    c.expired,
+		Namespaces: make(map[string]*models.CacheCounters, len(c.namespaces)),
+	}
+	if lookups := c.hits + c.misses; lookups != 0 {
+		stats.HitRatio = float64(c.hits) / float64(lookups)
+	}
+	for namespace, counters := range c.namespaces {
+		copiedCounters := *counters
+		stats.Namespaces[namespace] = &copiedCounters
+	}
+
+	return stats
+}
+
+func (c *Cache) remove(element *list.Element) {
+	delete(c.items, element.Value.(*entry).key)
+	c.order.Remove(element)
+}
+
+// counters returns the counters of the namespace of key.
+func (c *Cache) counters(key string) *models.CacheCounters {
+	namespace := key
+	if colon := strings.IndexByte(key, ':'); colon >= 0 {
+		namespace = key[:colon]
+	}
+
+	counters, ok := c.namespaces[namespace]
+	if !ok {
+		counters = &models.CacheCounters{}
+		c.namespaces[namespace] = counters
+	}
+	return counters
+}
@@ -0,0 +1,156 @@
+package lru
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
+
+func has(c *Cache, key string) bool {
+	var value int
+	return c.Get(context.Background(), key, &value)
+}
+
+func TestEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewCache(3, time.Hour).(*Cache)
+	ctx := context.Background()
+	for i, key := range []string{"n:a", "n:b", "n:c"} {
+		c.Set(ctx, key, i)
+	}
+
+	// a becomes the most recently used, b the least.
+	if !has(c, "n:a") {
+		t.Fatal("n:a is missing")
+	}
+	c.Set(ctx, "n:d", 3)
+	if has(c, "n:b") {
+		t.Error("n:b survived although it was the least recently used")
+	}
+	for _, key := range []string{"n:a", "n:c", "n:d"} {
+		if !has(c, key) {
+			t.Errorf("%s was evicted", key)
+		}
+	}
+
+	// Setting a present key refreshes it instead of adding an entry.
+	c.Set(ctx, "n:a", 10)
+	c.Set(ctx, "n:e", 4)
+	var value int
+	if !c.Get(ctx, "n:a", &value) || value != 10 {
+		t.Errorf("n:a = %d, want 10", value)
+	}
+	if has(c, "n:c") {
+		t.Error("n:c survived although it was the least recently used")
+	}
+
+	if stats := c.Stats(); stats.Entries != 3 || stats.Evictions != 2 {
+		t.Errorf("entries = %d, evictions = %d, want 3 and 2", stats.Entries, stats.Evictions)
+	}
+}
+
+func TestExpires(t *testing.T) {
+	c := NewCache(10, 20*time.Millisecond).(*Cache)
+	ctx := context.Background()
+	c.Set(ctx, "n:a", 1)
+	if !has(c, "n:a") {
+		t.Fatal("n:a is missing before its ttl")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if has(c, "n:a") {
+		t.Error("n:a is found after its ttl")
+	}
+	if stats := c.Stats(); stats.Expired != 1 || stats.Entries != 0 {
+		t.Errorf("expired = %d, entries = %d, want 1 and 0", stats.Expired, stats.Entries)
+	}
+}
+
+func TestGetMissesOtherTypes(t *testing.T) {
+	c := NewCache(10, time.Hour).(*Cache)
+	ctx := context.Background()
+	c.Set(ctx, "forum:f", models.Forum{Slug: "f"})
+
+	var thread models.Thread
+	if c.Get(ctx, "forum:f", &thread) {
+		t.Error("a forum is read into a thread")
+	}
+	var forumPointer *models.Forum
+	if c.Get(ctx, "forum:f", &forumPointer) {
+		t.Error("a forum is read into a pointer")
+	}
+	var forum models.Forum
+	if c.Get(ctx, "forum:f", forum) {
+		t.Error("a forum is read into a value that isn't a pointer")
+	}
+	if !c.Get(ctx, "forum:f", &forum) || forum.Slug != "f" {
+		t.Errorf("forum = %+v, want f", forum)
+	}
+	if stats := c.Stats(); stats.Misses != 3 || stats.Hits != 1 {
+		t.Errorf("misses = %d, hits = %d, want 3 and 1", stats.Misses, stats.Hits)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	c := NewCache(10, time.Hour).(*Cache)
+	ctx := context.Background()
+	for i, key := range []string{"thread:1", "thread:slug:s", "threads:2", "forum:f"} {
+		c.Set(ctx, key, i)
+	}
+
+	c.DeletePrefix(ctx, "thread:")
+	for key, want := range map[string]bool{"thread:1": false, "thread:slug:s": false, "threads:2": true, "forum:f": true} {
+		if has(c, key) != want {
+			t.Errorf("%s present = %v, want %v after DeletePrefix", key, !want, want)
+		}
+	}
+
+	c.Delete(ctx, "forum:f", "unknown:key")
+	if has(c, "forum:f") {
+		t.Error("forum:f present after Delete")
+	}
+
+	c.Clear(ctx)
+	if stats := c.Stats(); stats.Entries != 0 || has(c, "threads:2") {
+		t.Errorf("entries = %d after Clear", stats.Entries)
+	}
+}
+
+func TestStats(t *testing.T) {
+	c := NewCache(10, time.Hour).(*Cache)
+	ctx := context.Background()
+	c.Set(ctx, "forum:f", 1)
+	c.Set(ctx, "user:alice", 2)
+
+	for _, key := range []string{"forum:f", "forum:f", "forum:g", "user:alice", "user:bob", "user:carol", "bare"} {
+		has(c, key)
+	}
+
+	stats := c.Stats()
+	if !stats.Enabled || stats.Capacity != 10 || stats.Entries != 2 {
+		t.Errorf("stats = %+v, want an enabled cache of 10 with 2 entries", stats)
+	}
+	if stats.Hits != 3 || stats.Misses != 4 || stats.HitRatio != 3.0/7 {
+		t.Errorf("hits = %d, misses = %d, ratio = %v, want 3, 4 and 3/7", stats.Hits, stats.Misses, stats.HitRatio)
+	}
+	want := map[string]models.CacheCounters{
+		"forum": {Hits: 2, Misses: 1},
+		"user":  {Hits: 1, Misses: 2},
+		"bare":  {Misses: 1},
+	}
+	if len(stats.Namespaces) != len(want) {
+		t.Errorf("namespaces = %v, want %v", stats.Namespaces, want)
+	}
+	for namespace, counters := range want {
+		if got := stats.Namespaces[namespace]; got == nil || *got != counters {
+			t.Errorf("namespace %s = %+v, want %+v", namespace, got, counters)
+		}
+	}
+
+	// Stats are copies.
+	stats.Namespaces["forum"].Hits = 100
+	if c.Stats().Namespaces["forum"].Hits != 2 {
+		t.Error("changing the stats changed the cache")
+	}
+}
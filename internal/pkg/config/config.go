@@ -0,0 +1,595 @@
+package config
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const envPrefix = "FORUM_"
+
+const (
+	DriverPostgres = "postgres"
+	DriverMemory   = "memory"
+)
+
+const (
+	DurabilityUnlogged = "unlogged"
+	DurabilityLogged   = "logged"
+)
+
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+const (
+	TracingExporterNone   = "none"
+	TracingExporterStdout = "stdout"
+	TracingExporterFile   = "file"
+)
+
+type Config struct {
+	Server   ServerConfig   `json:"server"`
+	Database DatabaseConfig `json:"database"`
+	Auth     AuthConfig     `json:"auth"`
+	Events   EventsConfig   `json:"events"`
+	Webhooks WebhooksConfig `json:"webhooks"`
+	Cache    CacheConfig    `json:"cache"`
+	Log      LogConfig      `json:"log"`
+	Tracing  TracingConfig  `json:"tracing"`
+	Health   HealthConfig   `json:"health"`
+	Features FeaturesConfig `json:"features"`
+}
+
+type ServerConfig struct {
+	ListenAddr   string   `json:"listen_addr"`
+	ReadTimeout  Duration `json:"read_timeout"`
+	WriteTimeout Duration `json:"write_timeout"`
+	IdleTimeout  Duration `json:"idle_timeout"`
+	// ShutdownDelay is how long the server keeps serving after readiness
+	// has been flipped off, DrainTimeout bounds waiting for in-flight requests.
+	ShutdownDelay Duration `json:"shutdown_delay"`
+	DrainTimeout  Duration `json:"drain_timeout"`
+	// RequestTimeout bounds the work done for a request, RouteTimeouts
+	// override it for single routes keyed like "GET /api/forum/{slug}/details".
+	RequestTimeout Duration            `json:"request_timeout"`
+	RouteTimeouts  map[string]Duration `json:"route_timeouts"`
+}
+
+func (c *ServerConfig) RouteTimeout(method, path string) time.Duration {
+	if timeout, ok := c.RouteTimeouts[method+" "+path]; ok {
+		return timeout.Duration
+	}
+	return c.RequestTimeout.Duration
+}
+
+type DatabaseConfig struct {
+	Driver          string   `json:"driver"`
+	DSN             string   `json:"dsn"`
+	MaxOpenConns    int      `json:"max_open_conns"`
+	MaxIdleConns    int      `json:"max_idle_conns"`
+	ConnMaxLifetime Duration `json:"conn_max_lifetime"`
+	ConnMaxIdleTime Duration `json:"conn_max_idle_time"`
+	ConnectTimeout  Duration `json:"connect_timeout"`
+	AutoMigrate     bool     `json:"auto_migrate"`
+	// Durability selects between unlogged tables, which are fast but lost
+	// on a PostgreSQL crash, and logged ones.
+	Durability string `json:"durability"`
+}
+
+type AuthConfig struct {
+	// Secret signs session tokens. When it is empty a random one is made
+	// on startup, so sessions don't survive a restart.
+	Secret     string   `json:"secret"`
+	SessionTTL Duration `json:"session_ttl"`
+	// Required rejects writes without a session token. Otherwise they are
+	// attributed to the users named in request bodies, as before.
+	Required bool `json:"required"`
+	// Admins are the nicknames of the site administrators.
+	Admins []string `json:"admins"`
+}
+
+type EventsConfig struct {
+	// Buffer is how many events a stream may lag behind before it is
+	// disconnected, and an asynchronous subscriber before publishing waits
+	// for it.
+	Buffer    int      `json:"buffer"`
+	Heartbeat Duration `json:"heartbeat"`
+	// Notify relays events between the instances sharing a PostgreSQL
+	// database through LISTEN/NOTIFY.
+	Notify bool `json:"notify"`
+}
+
+type WebhooksConfig struct {
+	// Timeout bounds a single delivery attempt.
+	Timeout Duration `json:"timeout"`
+	// MaxAttempts is how many times a delivery is tried before it fails.
+	// Retries wait Backoff, doubled after every attempt up to MaxBackoff.
+	MaxAttempts int      `json:"max_attempts"`
+	Backoff     Duration `json:"backoff"`
+	MaxBackoff  Duration `json:"max_backoff"`
+	// PollInterval is how often due deliveries are looked up.
+	PollInterval Duration `json:"poll_interval"`
+	// AllowPrivate lets webhooks reach loopback, private and link-local
+	// addresses, which are refused so that forum owners can't reach the
+	// internal network of the server.
+	AllowPrivate bool `json:"allow_private"`
+}
+
+// CacheConfig sets up the in-process cache of forums, threads and users.
+// Every instance has its own, so with several instances sharing a database
+// changes made through the others show up within TTL.
+type CacheConfig struct {
+	Enabled bool     `json:"enabled"`
+	Size    int      `json:"size"`
+	TTL     Duration `json:"ttl"`
+}
+
+type LogConfig struct {
+	// Level is the lowest level logged: debug, info, warn or error.
+	Level  string `json:"level"`
+	Format string `json:"format"`
+	// Access logs every request once it is served.
+	Access bool `json:"access"`
+	// SlowQuery is the duration from which repository calls are logged,
+	// zero turns it off.
+	SlowQuery Duration `json:"slow_query"`
+}
+
+type TracingConfig struct {
+	// Exporter writes finished spans as JSON lines to stdout or to File,
+	// none turns tracing off.
+	Exporter string `json:"exporter"`
+	File     string `json:"file"`
+	Service  string `json:"service"`
+	// SampleRatio is the share of new traces recorded. Traces continued
+	// from a traceparent header keep the decision of the caller.
+	SampleRatio float64 `json:"sample_ratio"`
+}
+
+type HealthConfig struct {
+	// CheckTimeout bounds the dependency checks of a readiness probe.
+	CheckTimeout Duration `json:"check_timeout"`
+	// Maintenance starts the server in maintenance mode, failing readiness
+	// until an administrator switches it off.
+	Maintenance bool `json:"maintenance"`
+}
+
+type FeaturesConfig struct {
+	ServiceClear bool `json:"service_clear"`
+	Metrics      bool `json:"metrics"`
+}
+
+// Duration accepts both Go duration strings ("1m30s") and integer
+// nanoseconds in JSON config files.
+type Duration struct {
+	time.Duration
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case float64:
+		d.Duration = time.Duration(v)
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		d.Duration = parsed
+	default:
+		return fmt.Errorf("invalid duration %s", string(data))
+	}
+
+	return nil
+}
+
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			ListenAddr:     ":5000",
+			ReadTimeout:    Duration{10 * time.Second},
+			WriteTimeout:   Duration{10 * time.Second},
+			IdleTimeout:    Duration{time.Minute},
+			ShutdownDelay:  Duration{0},
+			DrainTimeout:   Duration{30 * time.Second},
+			RequestTimeout: Duration{30 * time.Second},
+		},
+		Database: DatabaseConfig{
+			Driver:          DriverPostgres,
+			DSN:             "user=postgres password=postgres dbname=forum_db host=localhost port=5432",
+			MaxOpenConns:    100,
+			MaxIdleConns:    100,
+			ConnMaxLifetime: Duration{time.Hour},
+			ConnMaxIdleTime: Duration{5 * time.Minute},
+			ConnectTimeout:  Duration{5 * time.Second},
+			Durability:      DurabilityUnlogged,
+		},
+		Auth: AuthConfig{
+			SessionTTL: Duration{24 * time.Hour},
+		},
+		Events: EventsConfig{
+			Buffer:    64,
+			Heartbeat: Duration{15 * time.Second},
+		},
+		Webhooks: WebhooksConfig{
+			Timeout:      Duration{10 * time.Second},
+			MaxAttempts:  8,
+			Backoff:      Duration{10 * time.Second},
+			MaxBackoff:   Duration{time.Hour},
+			PollInterval: Duration{time.Second},
+		},
+		Cache: CacheConfig{
+			Enabled: true,
+			Size:    10000,
+			TTL:     Duration{time.Minute},
+		},
+		Log: LogConfig{
+			Level:     "info",
+			Format:    LogFormatText,
+			Access:    true,
+			SlowQuery: Duration{200 * time.Millisecond},
+		},
+		Tracing: TracingConfig{
+			Exporter:    TracingExporterNone,
+			File:        "traces.jsonl",
+			Service:     "forum-api",
+			SampleRatio: 1,
+		},
+		Health: HealthConfig{
+			CheckTimeout: Duration{2 * time.Second},
+		},
+		Features: FeaturesConfig{
+			ServiceClear: true,
+			Metrics:      true,
+		},
+	}
+}
+
+type option struct {
+	flag  string
+	usage string
+	value flag.Value
+}
+
+func (o option) env() string {
+	return envPrefix + strings.ToUpper(strings.Replace(o.flag, "-", "_", -1))
+}
+
+func (c *Config) options() []option {
+	return []option{
+		{"listen-addr", "address the HTTP server listens on", (*stringValue)(&c.Server.ListenAddr)},
+		{"read-timeout", "maximum duration for reading a request", (*durationValue)(&c.Server.ReadTimeout.Duration)},
+		{"write-timeout", "maximum duration for writing a response", (*durationValue)(&c.Server.WriteTimeout.Duration)},
+		{"idle-timeout", "maximum keep-alive idle time", (*durationValue)(&c.Server.IdleTimeout.Duration)},
+		{"shutdown-delay", "time to keep serving after readiness is switched off", (*durationValue)(&c.Server.ShutdownDelay.Duration)},
+		{"drain-timeout", "maximum time to wait for in-flight requests on shutdown", (*durationValue)(&c.Server.DrainTimeout.Duration)},
+		{"request-timeout", "deadline of a single request (0 is unlimited)", (*durationValue)(&c.Server.RequestTimeout.Duration)},
+		{"db-driver", "storage backend: postgres or memory", (*stringValue)(&c.Database.Driver)},
+		{"db-dsn", "PostgreSQL connection string", (*stringValue)(&c.Database.DSN)},
+		{"db-max-open-conns", "maximum number of open database connections (0 is unlimited)", (*intValue)(&c.Database.MaxOpenConns)},
+		{"db-max-idle-conns", "maximum number of idle database connections", (*intValue)(&c.Database.MaxIdleConns)},
+		{"db-conn-max-lifetime", "maximum lifetime of a database connection (0 is unlimited)", (*durationValue)(&c.Database.ConnMaxLifetime.Duration)},
+		{"db-conn-max-idle-time", "maximum idle time of a database connection (0 is unlimited)", (*durationValue)(&c.Database.ConnMaxIdleTime.Duration)},
+		{"db-connect-timeout", "timeout of the initial database ping", (*durationValue)(&c.Database.ConnectTimeout.Duration)},
+		{"db-auto-migrate", "apply pending schema migrations on startup", (*boolValue)(&c.Database.AutoMigrate)},
+		{"db-durability", "durability of tables created by migrations and set by migrate durability: unlogged or logged", (*stringValue)(&c.Database.Durability)},
+		{"auth-secret", "key signing session tokens (random when empty)", (*stringValue)(&c.Auth.Secret)},
+		{"auth-session-ttl", "lifetime of a session", (*durationValue)(&c.Auth.SessionTTL.Duration)},
+		{"auth-required", "reject writes without a session token", (*boolValue)(&c.Auth.Required)},
+		{"auth-admins", "comma-separated nicknames of site administrators", (*listValue)(&c.Auth.Admins)},
+		{"events-buffer", "number of events a stream or an asynchronous subscriber may lag behind", (*intValue)(&c.Events.Buffer)},
+		{"events-heartbeat", "interval of keep-alive comments on idle event streams", (*durationValue)(&c.Events.Heartbeat.Duration)},
+		{"events-notify", "relay events between instances through PostgreSQL LISTEN/NOTIFY", (*boolValue)(&c.Events.Notify)},
+		{"webhooks-timeout", "timeout of a webhook delivery attempt", (*durationValue)(&c.Webhooks.Timeout.Duration)},
+		{"webhooks-max-attempts", "number of attempts before a webhook delivery fails", (*intValue)(&c.Webhooks.MaxAttempts)},
+		{"webhooks-backoff", "delay before the first retry of a webhook delivery, doubled after every attempt", (*durationValue)(&c.Webhooks.Backoff.Duration)},
+		{"webhooks-max-backoff", "maximum delay between webhook delivery attempts", (*durationValue)(&c.Webhooks.MaxBackoff.Duration)},
+		{"webhooks-poll-interval", "interval of looking up due webhook deliveries", (*durationValue)(&c.Webhooks.PollInterval.Duration)},
+		{"webhooks-allow-private", "let webhooks reach loopback, private and link-local addresses", (*boolValue)(&c.Webhooks.AllowPrivate)},
+		{"cache-enabled", "cache forums, threads and users in memory", (*boolValue)(&c.Cache.Enabled)},
+		{"cache-size", "maximum number of cached entries", (*intValue)(&c.Cache.Size)},
+		{"cache-ttl", "lifetime of a cached entry", (*durationValue)(&c.Cache.TTL.Duration)},
+		{"log-level", "lowest level logged: debug, info, warn or error", (*stringValue)(&c.Log.Level)},
+		{"log-format", "format of log records: text or json", (*stringValue)(&c.Log.Format)},
+		{"log-access", "log every served request", (*boolValue)(&c.Log.Access)},
+		{"log-slow-query", "duration from which repository calls are logged (0 is off)", (*durationValue)(&c.Log.SlowQuery.Duration)},
+		{"tracing-exporter", "where finished spans are written: none, stdout or file", (*stringValue)(&c.Tracing.Exporter)},
+		{"tracing-file", "file the file exporter appends spans to", (*stringValue)(&c.Tracing.File)},
+		{"tracing-service", "service name recorded in spans", (*stringValue)(&c.Tracing.Service)},
+		{"tracing-sample-ratio", "share of new traces recorded, from 0 to 1", (*floatValue)(&c.Tracing.SampleRatio)},
+		{"health-check-timeout", "timeout of the dependency checks of a readiness probe", (*durationValue)(&c.Health.CheckTimeout.Duration)},
+		{"health-maintenance", "start in maintenance mode, not ready until switched off", (*boolValue)(&c.Health.Maintenance)},
+		{"feature-service-clear", "enable POST /api/service/clear", (*boolValue)(&c.Features.ServiceClear)},
+		{"feature-metrics", "enable GET /metrics", (*boolValue)(&c.Features.Metrics)},
+	}
+}
+
+// Load builds the configuration from defaults, an optional JSON file,
+// FORUM_* environment variables and command-line flags. Later sources
+// override earlier ones. The arguments left after the flags are returned.
+func Load(name string, args []string) (*Config, []string, error) {
+	cfg := Default()
+	options := cfg.options()
+
+	flagSet := flag.NewFlagSet(name, flag.ContinueOnError)
+	configPath := flagSet.String("config", os.Getenv(envPrefix+"CONFIG"), "path to a JSON config file")
+	rawFlags := make(map[string]*rawValue, len(options))
+	for _, opt := range options {
+		raw := &rawValue{value: opt.value.String()}
+		_, raw.isBool = opt.value.(*boolValue)
+		rawFlags[opt.flag] = raw
+		flagSet.Var(raw, opt.flag, fmt.Sprintf("%s (env %s)", opt.usage, opt.env()))
+	}
+	if err := flagSet.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	if *configPath != "" {
+		if err := cfg.loadFile(*configPath); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, opt := range options {
+		if value, ok := os.LookupEnv(opt.env()); ok {
+			if err := opt.value.Set(value); err != nil {
+				return nil, nil, fmt.Errorf("invalid value %q for %s: %v", value, opt.env(), err)
+			}
+		}
+	}
+
+	var flagErr error
+	flagSet.Visit(func(f *flag.Flag) {
+		raw, ok := rawFlags[f.Name]
+		if !ok || flagErr != nil {
+			return
+		}
+		for _, opt := range options {
+			if opt.flag == f.Name {
+				if err := opt.value.Set(raw.value); err != nil {
+					flagErr = fmt.Errorf("invalid value %q for -%s: %v", raw.value, f.Name, err)
+				}
+			}
+		}
+	})
+	if flagErr != nil {
+		return nil, nil, flagErr
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return cfg, flagSet.Args(), nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("can't read config file: %v", err)
+	}
+
+	if err := json.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("can't parse config file %s: %v", path, err)
+	}
+
+	return nil
+}
+
+func (c *Config) Validate() error {
+	if c.Server.ListenAddr == "" {
+		return fmt.Errorf("config: listen address is empty")
+	}
+	switch c.Database.Driver {
+	case DriverPostgres:
+		if c.Database.DSN == "" {
+			return fmt.Errorf("config: database dsn is empty")
+		}
+	case DriverMemory:
+	default:
+		return fmt.Errorf("config: unknown database driver %q", c.Database.Driver)
+	}
+	if c.Database.Durability != DurabilityUnlogged && c.Database.Durability != DurabilityLogged {
+		return fmt.Errorf("config: unknown durability %q", c.Database.Durability)
+	}
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 {
+		return fmt.Errorf("config: database pool sizes must not be negative")
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		return fmt.Errorf("config: max idle connections (%d) exceed max open connections (%d)",
+			c.Database.MaxIdleConns, c.Database.MaxOpenConns)
+	}
+	if c.Auth.SessionTTL.Duration <= 0 {
+		return fmt.Errorf("config: session ttl must be positive")
+	}
+	if c.Events.Buffer <= 0 {
+		return fmt.Errorf("config: events buffer must be positive")
+	}
+	if c.Events.Heartbeat.Duration <= 0 {
+		return fmt.Errorf("config: events heartbeat must be positive")
+	}
+	if c.Events.Notify && c.Database.Driver != DriverPostgres {
+		return fmt.Errorf("config: events notify needs the %s driver", DriverPostgres)
+	}
+	if c.Webhooks.MaxAttempts <= 0 {
+		return fmt.Errorf("config: webhooks max attempts must be positive")
+	}
+	if c.Webhooks.Timeout.Duration <= 0 || c.Webhooks.Backoff.Duration <= 0 || c.Webhooks.PollInterval.Duration <= 0 {
+		return fmt.Errorf("config: webhooks timeout, backoff and poll interval must be positive")
+	}
+	if c.Webhooks.MaxBackoff.Duration < c.Webhooks.Backoff.Duration {
+		return fmt.Errorf("config: webhooks max backoff (%s) is below the backoff (%s)",
+			c.Webhooks.MaxBackoff.Duration, c.Webhooks.Backoff.Duration)
+	}
+
+	if c.Cache.Enabled && (c.Cache.Size <= 0 || c.Cache.TTL.Duration <= 0) {
+		return fmt.Errorf("config: cache size and ttl must be positive")
+	}
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("config: unknown log level %q", c.Log.Level)
+	}
+	if c.Log.Format != LogFormatText && c.Log.Format != LogFormatJSON {
+		return fmt.Errorf("config: unknown log format %q", c.Log.Format)
+	}
+	switch c.Tracing.Exporter {
+	case TracingExporterNone, TracingExporterStdout:
+	case TracingExporterFile:
+		if c.Tracing.File == "" {
+			return fmt.Errorf("config: tracing file is empty")
+		}
+	default:
+		return fmt.Errorf("config: unknown tracing exporter %q", c.Tracing.Exporter)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("config: tracing sample ratio must be between 0 and 1")
+	}
+
+	durations := map[string]time.Duration{
+		"read timeout":         c.Server.ReadTimeout.Duration,
+		"write timeout":        c.Server.WriteTimeout.Duration,
+		"idle timeout":         c.Server.IdleTimeout.Duration,
+		"shutdown delay":       c.Server.ShutdownDelay.Duration,
+		"drain timeout":        c.Server.DrainTimeout.Duration,
+		"request timeout":      c.Server.RequestTimeout.Duration,
+		"conn max lifetime":    c.Database.ConnMaxLifetime.Duration,
+		"conn max idle time":   c.Database.ConnMaxIdleTime.Duration,
+		"connect timeout":      c.Database.ConnectTimeout.Duration,
+		"slow query":           c.Log.SlowQuery.Duration,
+		"health check timeout": c.Health.CheckTimeout.Duration,
+	}
+	for route, timeout := range c.Server.RouteTimeouts {
+		durations["timeout of "+route] = timeout.Duration
+	}
+	for name, value := range durations {
+		if value < 0 {
+			return fmt.Errorf("config: %s must not be negative", name)
+		}
+	}
+
+	return nil
+}
+
+type rawValue struct {
+	value  string
+	isBool bool
+}
+
+func (v *rawValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.value
+}
+
+func (v *rawValue) Set(value string) error {
+	v.value = value
+	return nil
+}
+
+func (v *rawValue) IsBoolFlag() bool {
+	return v.isBool
+}
+
+type stringValue string
+
+func (v *stringValue) String() string {
+	return string(*v)
+}
+
+func (v *stringValue) Set(value string) error {
+	*v = stringValue(value)
+	return nil
+}
+
+type intValue int
+
+func (v *intValue) String() string {
+	return strconv.Itoa(int(*v))
+}
+
+func (v *intValue) Set(value string) error {
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	*v = intValue(parsed)
+	return nil
+}
+
+type boolValue bool
+
+func (v *boolValue) String() string {
+	return strconv.FormatBool(bool(*v))
+}
+
+func (v *boolValue) Set(value string) error {
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	*v = boolValue(parsed)
+	return nil
+}
+
+type floatValue float64
+
+func (v *floatValue) String() string {
+	return strconv.FormatFloat(float64(*v), 'g', -1, 64)
+}
+
+func (v *floatValue) Set(value string) error {
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return err
+	}
+	*v = floatValue(parsed)
+	return nil
+}
+
+type durationValue time.Duration
+
+func (v *durationValue) String() string {
+	return time.Duration(*v).String()
+}
+
+func (v *durationValue) Set(value string) error {
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*v = durationValue(parsed)
+	return nil
+}
+
+type listValue []string
+
+func (v *listValue) String() string {
+	return strings.Join(*v, ",")
+}
+
+func (v *listValue) Set(value string) error {
+	*v = listValue{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*v = append(*v, item)
+		}
+	}
+	return nil
+}
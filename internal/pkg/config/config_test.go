@@ -0,0 +1,173 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setenv(t *testing.T, key, value string) {
+	t.Helper()
+	previous, ok := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, previous)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeConfig(t, `{
+		"server": {"listen_addr": ":6000", "read_timeout": "3s"},
+		"events": {"buffer": 16, "heartbeat": 2000000000},
+		"log": {"level": "debug"}
+	}`)
+	setenv(t, "FORUM_READ_TIMEOUT", "4s")
+	setenv(t, "FORUM_EVENTS_BUFFER", "32")
+	setenv(t, "FORUM_AUTH_ADMINS", "root, admin")
+
+	cfg, args, err := Load("test", []string{"-config", path, "-events-buffer", "48", "migrate", "up"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Server.ListenAddr != ":6000" {
+		t.Errorf("listen addr = %q, want the one of the file", cfg.Server.ListenAddr)
+	}
+	if cfg.Events.Heartbeat.Duration != 2*time.Second {
+		t.Errorf("heartbeat = %s, want nanoseconds of the file", cfg.Events.Heartbeat.Duration)
+	}
+	if cfg.Server.ReadTimeout.Duration != 4*time.Second {
+		t.Errorf("read timeout = %s, want the environment overriding the file", cfg.Server.ReadTimeout.Duration)
+	}
+	if cfg.Events.Buffer != 48 {
+		t.Errorf("events buffer = %d, want the flag overriding the environment and the file", cfg.Events.Buffer)
+	}
+	if strings.Join(cfg.Auth.Admins, ",") != "root,admin" {
+		t.Errorf("admins = %q, want root and admin", cfg.Auth.Admins)
+	}
+	if cfg.Server.WriteTimeout != Default().Server.WriteTimeout {
+		t.Errorf("write timeout = %s, want the default", cfg.Server.WriteTimeout.Duration)
+	}
+	if strings.Join(args, " ") != "migrate up" {
+		t.Errorf("args = %q, want migrate up", args)
+	}
+}
+
+func TestLoadConfigFromEnvironment(t *testing.T) {
+	setenv(t, "FORUM_CONFIG", writeConfig(t, `{"database": {"driver": "memory"}}`))
+
+	cfg, _, err := Load("test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Database.Driver != DriverMemory {
+		t.Errorf("driver = %q, want the one of the file named by FORUM_CONFIG", cfg.Database.Driver)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+	}{
+		{"missing file", nil, []string{"-config", filepath.Join(t.TempDir(), "missing.json")}},
+		{"broken file", nil, []string{"-config", writeConfig(t, `{"server": `)}},
+		{"bad duration in file", nil, []string{"-config", writeConfig(t, `{"server": {"read_timeout": "soon"}}`)}},
+		{"bad environment value", map[string]string{"FORUM_EVENTS_BUFFER": "many"}, nil},
+		{"bad flag value", nil, []string{"-events-buffer", "many"}},
+		{"unknown flag", nil, []string{"-unknown"}},
+		{"invalid result", map[string]string{"FORUM_DB_DRIVER": "sqlite"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				setenv(t, key, value)
+			}
+			if _, _, err := Load("test", tt.args); err == nil {
+				t.Error("Load succeeded")
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Fatalf("default config is invalid: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(cfg *Config)
+		valid  bool
+	}{
+		{"memory driver without dsn", func(cfg *Config) { cfg.Database.Driver, cfg.Database.DSN = DriverMemory, "" }, true},
+		{"unlimited pool", func(cfg *Config) { cfg.Database.MaxOpenConns = 0 }, true},
+		{"cache disabled without size", func(cfg *Config) { cfg.Cache.Enabled, cfg.Cache.Size = false, 0 }, true},
+		{"upper case log level", func(cfg *Config) { cfg.Log.Level = "WARN" }, true},
+		{"empty listen address", func(cfg *Config) { cfg.Server.ListenAddr = "" }, false},
+		{"unknown driver", func(cfg *Config) { cfg.Database.Driver = "sqlite" }, false},
+		{"postgres without dsn", func(cfg *Config) { cfg.Database.DSN = "" }, false},
+		{"unknown durability", func(cfg *Config) { cfg.Database.Durability = "durable" }, false},
+		{"negative pool", func(cfg *Config) { cfg.Database.MaxIdleConns = -1 }, false},
+		{"more idle than open", func(cfg *Config) { cfg.Database.MaxOpenConns = 10 }, false},
+		{"zero session ttl", func(cfg *Config) { cfg.Auth.SessionTTL.Duration = 0 }, false},
+		{"zero events buffer", func(cfg *Config) { cfg.Events.Buffer = 0 }, false},
+		{"zero heartbeat", func(cfg *Config) { cfg.Events.Heartbeat.Duration = 0 }, false},
+		{"notify without postgres", func(cfg *Config) { cfg.Events.Notify, cfg.Database.Driver = true, DriverMemory }, false},
+		{"zero webhook attempts", func(cfg *Config) { cfg.Webhooks.MaxAttempts = 0 }, false},
+		{"zero webhook timeout", func(cfg *Config) { cfg.Webhooks.Timeout.Duration = 0 }, false},
+		{"max backoff below backoff", func(cfg *Config) { cfg.Webhooks.MaxBackoff.Duration = time.Second }, false},
+		{"enabled cache without size", func(cfg *Config) { cfg.Cache.Size = 0 }, false},
+		{"unknown log level", func(cfg *Config) { cfg.Log.Level = "trace" }, false},
+		{"unknown log format", func(cfg *Config) { cfg.Log.Format = "xml" }, false},
+		{"unknown tracing exporter", func(cfg *Config) { cfg.Tracing.Exporter = "jaeger" }, false},
+		{"file exporter without file", func(cfg *Config) { cfg.Tracing.Exporter, cfg.Tracing.File = TracingExporterFile, "" }, false},
+		{"sample ratio above one", func(cfg *Config) { cfg.Tracing.SampleRatio = 1.5 }, false},
+		{"negative read timeout", func(cfg *Config) { cfg.Server.ReadTimeout.Duration = -time.Second }, false},
+		{"negative route timeout", func(cfg *Config) {
+			cfg.Server.RouteTimeouts = map[string]Duration{"GET /api/forum/{slug}/details": {-time.Second}}
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.change(cfg)
+			if err := cfg.Validate(); (err == nil) != tt.valid {
+				t.Errorf("Validate() = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestRouteTimeout(t *testing.T) {
+	cfg := Default().Server
+	cfg.RouteTimeouts = map[string]Duration{"GET /api/events": {0}}
+
+	if timeout := cfg.RouteTimeout("GET", "/api/events"); timeout != 0 {
+		t.Errorf("route timeout = %s, want the override", timeout)
+	}
+	if timeout := cfg.RouteTimeout("POST", "/api/events"); timeout != cfg.RequestTimeout.Duration {
+		t.Errorf("route timeout = %s, want the request timeout", timeout)
+	}
+}
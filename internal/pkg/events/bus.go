@@ -0,0 +1,51 @@
+package events
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
+
+// Bus fans out the updates streamed to clients. Publishing never blocks on
+// subscribers.
+type Bus interface {
+	Publish(event *models.Event)
+	Subscribe(filter Filter) *Subscription
+	// Close ends every subscription and makes later ones end at once.
+	Close() error
+}
+
+// Filter selects the events of a forum, of a thread or, when it is empty,
+// all of them.
+type Filter struct {
+	Forum  string
+	Thread uint64
+}
+
+func (f Filter) Match(event *models.Event) bool {
+	if f.Forum != "" && !strings.EqualFold(f.Forum, event.Forum) {
+		return false
+	}
+	return f.Thread == 0 || f.Thread == event.Thread
+}
+
+// Subscription receives the matching events until it is closed. Events is
+// also closed by the bus when the subscriber falls too far behind.
+type Subscription struct {
+	Events <-chan *models.Event
+
+	closeOnce sync.Once
+	cancel    func()
+}
+
+func NewSubscription(events <-chan *models.Event, cancel func()) *Subscription {
+	return &Subscription{
+		Events: events,
+		cancel: cancel,
+	}
+}
+
+func (s *Subscription) Close() {
+	s.closeOnce.Do(s.cancel)
+}
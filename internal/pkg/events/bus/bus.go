@@ -0,0 +1,92 @@
+package bus
+
+import (
+	"sync"
+
+	"github.com/forum-api-back/internal/pkg/events"
+	"github.com/forum-api-back/internal/pkg/models"
+)
+
+// Bus delivers events to the subscribers of this process.
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+	buffer      int
+	closed      bool
+}
+
+type subscriber struct {
+	filter events.Filter
+	events chan *models.Event
+}
+
+// NewBus makes a bus whose subscribers may lag behind by buffer events.
+func NewBus(buffer int) events.Bus {
+	return newBus(buffer)
+}
+
+func newBus(buffer int) *Bus {
+	return &Bus{
+		subscribers: make(map[*subscriber]struct{}),
+		buffer:      buffer,
+	}
+}
+
+// Publish hands the event to every matching subscriber. A subscriber with
+// a full buffer is dropped instead of holding back the others: its channel
+// is closed and a stream client reconnects.
+func (b *Bus) Publish(event *models.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for s := range b.subscribers {
+		if !s.filter.Match(event) {
+			continue
+		}
+		select {
+		case s.events <- event:
+		default:
+			b.remove(s)
+		}
+	}
+}
+
+func (b *Bus) Subscribe(filter events.Filter) *events.Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := &subscriber{
+		filter: filter,
+		events: make(chan *models.Event, b.buffer),
+	}
+	if b.closed {
+		close(s.events)
+	} else {
+		b.subscribers[s] = struct{}{}
+	}
+
+	return events.NewSubscription(s.events, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(s)
+	})
+}
+
+func (b *Bus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for s := range b.subscribers {
+		b.remove(s)
+	}
+
+	return nil
+}
+
+func (b *Bus) remove(s *subscriber) {
+	if _, ok := b.subscribers[s]; ok {
+		delete(b.subscribers, s)
+		close(s.events)
+	}
+}
@@ -0,0 +1,140 @@
+package bus
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/events"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/pkg/logger"
+
+	"github.com/lib/pq"
+)
+
+const notifyChannel = "forum_events"
+
+// maxPayload keeps notifications under the 8000 bytes PostgreSQL accepts.
+const maxPayload = 7900
+
+// notifyTimeout bounds a notification, so a stuck connection drops events
+// instead of stopping the relay.
+const notifyTimeout = 5 * time.Second
+
+// NotifyBus is a Bus that also relays events between the instances
+// sharing a database through LISTEN/NOTIFY.
+type NotifyBus struct {
+	*Bus
+
+	db       *sql.DB
+	listener *pq.Listener
+	origin   string
+	outbox   chan []byte
+	done     chan struct{}
+}
+
+type notification struct {
+	Origin string        `json:"origin"`
+	Event  *models.Event `json:"event"`
+}
+
+func NewNotifyBus(db *sql.DB, dsn string, buffer int) (events.Bus, error) {
+	origin := make([]byte, 8)
+	if _, err := rand.Read(origin); err != nil {
+		return nil, err
+	}
+
+	listener := pq.NewListener(dsn, time.Second, time.Minute, nil)
+	if err := listener.Listen(notifyChannel); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	b := &NotifyBus{
+		Bus:      newBus(buffer),
+		db:       db,
+		listener: listener,
+		origin:   hex.EncodeToString(origin),
+		outbox:   make(chan []byte, buffer),
+		done:     make(chan struct{}),
+	}
+	go b.receive()
+	go b.send()
+
+	return b, nil
+}
+
+// Publish delivers the event here at once and queues it for the other
+// instances. Events too large for a notification reach them without
+// their data.
+func (b *NotifyBus) Publish(event *models.Event) {
+	b.Bus.Publish(event)
+
+	payload, err := json.Marshal(&notification{Origin: b.origin, Event: event})
+	if err == nil && len(payload) > maxPayload {
+		stripped := *event
+		stripped.Data = nil
+		payload, err = json.Marshal(&notification{Origin: b.origin, Event: &stripped})
+	}
+	if err != nil {
+		logger.Default().Error("events: can't encode event", "event", event.Type, "error", err)
+		return
+	}
+
+	select {
+	case b.outbox <- payload:
+	default:
+		logger.Default().Warn("events: notification queue is full, event is not relayed", "event", event.Type)
+	}
+}
+
+func (b *NotifyBus) Close() error {
+	b.Bus.Close()
+	close(b.done)
+	return b.listener.Close()
+}
+
+// send notifies the queued events one by one, so the other instances get
+// them in the order they were published.
+func (b *NotifyBus) send() {
+	for {
+		select {
+		case payload := <-b.outbox:
+			b.notify(payload)
+		case <-b.done:
+			return
+		}
+	}
+}
+
+func (b *NotifyBus) notify(payload []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	if _, err := b.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", notifyChannel, string(payload)); err != nil {
+		logger.Default().Error("events: can't notify", "error", err)
+	}
+}
+
+func (b *NotifyBus) receive() {
+	for received := range b.listener.Notify {
+		// A nil notification follows a reconnect of the listener.
+		if received == nil {
+			continue
+		}
+
+		var message notification
+		if err := json.Unmarshal([]byte(received.Extra), &message); err != nil {
+			logger.Default().Error("events: can't decode notification", "error", err)
+			continue
+		}
+		if message.Origin == b.origin || message.Event == nil {
+			continue
+		}
+
+		b.Bus.Publish(message.Event)
+	}
+}
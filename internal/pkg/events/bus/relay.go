@@ -0,0 +1,46 @@
+package bus
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/events"
+	"github.com/forum-api-back/internal/pkg/models"
+)
+
+// NewRelay makes a subscriber passing the changes of threads and posts on
+// to the streams of a bus.
+func NewRelay(bus events.Bus) events.Subscriber {
+	return events.SubscriberFunc(func(ctx context.Context, event events.Event) error {
+		switch e := event.(type) {
+		case *events.PostsCreated:
+			for _, newPost := range e.Posts {
+				bus.Publish(postEvent(models.EventPostCreated, newPost))
+			}
+		case *events.PostEdited:
+			bus.Publish(postEvent(models.EventPostEdited, e.Post))
+		case *events.ThreadEdited:
+			bus.Publish(threadEvent(models.EventThreadEdited, e.Thread))
+		case *events.VoteCast:
+			bus.Publish(threadEvent(models.EventThreadVoted, e.Thread))
+		}
+		return nil
+	})
+}
+
+func postEvent(eventType string, changedPost *models.Post) *models.Event {
+	return &models.Event{
+		Type:   eventType,
+		Forum:  changedPost.Forum,
+		Thread: changedPost.Thread,
+		Data:   changedPost,
+	}
+}
+
+func threadEvent(eventType string, changedThread *models.Thread) *models.Event {
+	return &models.Event{
+		Type:   eventType,
+		Forum:  changedThread.Forum,
+		Thread: changedThread.Id,
+		Data:   changedThread,
+	}
+}
@@ -0,0 +1,35 @@
+package events
+
+import "context"
+
+// Publisher is what usecases report their changes to once they are
+// stored.
+type Publisher interface {
+	Publish(ctx context.Context, event Event)
+}
+
+type Subscriber interface {
+	Handle(ctx context.Context, event Event) error
+}
+
+type SubscriberFunc func(ctx context.Context, event Event) error
+
+func (f SubscriberFunc) Handle(ctx context.Context, event Event) error {
+	return f(ctx, event)
+}
+
+type Dispatcher interface {
+	Publisher
+	// Subscribe runs the subscriber within Publish, with the context of
+	// the request. It must be quick and must not block.
+	Subscribe(subscriber Subscriber)
+	// SubscribeAsync runs the subscriber in its own goroutine, in the
+	// order events are published and without the request context.
+	SubscribeAsync(subscriber Subscriber)
+	// Dropped returns the number of events asynchronous subscribers
+	// missed as their queue was full.
+	Dropped() uint64
+	// Close waits for the asynchronous subscribers to handle the events
+	// already published.
+	Close() error
+}
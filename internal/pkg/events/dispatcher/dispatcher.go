@@ -0,0 +1,104 @@
+package dispatcher
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/forum-api-back/internal/pkg/events"
+	"github.com/forum-api-back/pkg/logger"
+)
+
+type Dispatcher struct {
+	mu          sync.RWMutex
+	subscribers []events.Subscriber
+	queues      []chan events.Event
+	queueSize   int
+	closed      bool
+	workers     sync.WaitGroup
+	dropped     uint64
+}
+
+// NewDispatcher makes a dispatcher whose asynchronous subscribers may lag
+// behind by queueSize events. Events past a full queue are dropped rather
+// than holding up the request publishing them.
+func NewDispatcher(queueSize int) events.Dispatcher {
+	return &Dispatcher{
+		queueSize: queueSize,
+	}
+}
+
+func (d *Dispatcher) Publish(ctx context.Context, event events.Event) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		return
+	}
+	for _, subscriber := range d.subscribers {
+		handle(ctx, subscriber, event)
+	}
+	for _, queue := range d.queues {
+		select {
+		case queue <- event:
+		default:
+			atomic.AddUint64(&d.dropped, 1)
+			logger.FromContext(ctx).Warn("events: queue is full, event dropped", "event", event.Name())
+		}
+	}
+}
+
+func (d *Dispatcher) Dropped() uint64 {
+	return atomic.LoadUint64(&d.dropped)
+}
+
+func (d *Dispatcher) Subscribe(subscriber events.Subscriber) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.subscribers = append(d.subscribers, subscriber)
+}
+
+func (d *Dispatcher) SubscribeAsync(subscriber events.Subscriber) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	queue := make(chan events.Event, d.queueSize)
+	d.queues = append(d.queues, queue)
+
+	d.workers.Add(1)
+	go func() {
+		defer d.workers.Done()
+		for event := range queue {
+			handle(context.Background(), subscriber, event)
+		}
+	}()
+}
+
+func (d *Dispatcher) Close() error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		for _, queue := range d.queues {
+			close(queue)
+		}
+	}
+	d.mu.Unlock()
+
+	d.workers.Wait()
+	return nil
+}
+
+// handle keeps a failing subscriber from failing the change, which is
+// already stored, or from taking the server down.
+func handle(ctx context.Context, subscriber events.Subscriber, event events.Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.FromContext(ctx).Error("events: subscriber panicked", "event", event.Name(), "panic", r)
+		}
+	}()
+
+	if err := subscriber.Handle(ctx, event); err != nil {
+		logger.FromContext(ctx).Error("events: subscriber failed", "event", event.Name(), "error", err)
+	}
+}
@@ -0,0 +1,163 @@
+package dispatcher
+
+import (
+	"context"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/events"
+	"github.com/forum-api-back/pkg/logger"
+)
+
+type testEvent int
+
+func (e testEvent) Name() string {
+	return "test"
+}
+
+// recorder is a subscriber keeping the events it handles.
+type recorder struct {
+	mu     sync.Mutex
+	events []testEvent
+}
+
+func (r *recorder) Handle(ctx context.Context, event events.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event.(testEvent))
+	return nil
+}
+
+func (r *recorder) handled() []testEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]testEvent(nil), r.events...)
+}
+
+func checkHandled(t *testing.T, name string, got []testEvent, count int) {
+	t.Helper()
+	if len(got) != count {
+		t.Fatalf("%s handled %d events, want %d", name, len(got), count)
+	}
+	for i, event := range got {
+		if event != testEvent(i) {
+			t.Fatalf("%s handled event %d as number %d", name, event, i)
+		}
+	}
+}
+
+// quietContext keeps the logs of failing subscribers out of the test
+// output.
+func quietContext() context.Context {
+	return logger.NewContext(context.Background(), logger.New(ioutil.Discard, logger.FormatText, logger.LevelError+1))
+}
+
+func TestPublishOrder(t *testing.T) {
+	const count = 100
+
+	d := NewDispatcher(count)
+	syncRecorder, asyncRecorder := &recorder{}, &recorder{}
+	d.Subscribe(syncRecorder)
+	d.SubscribeAsync(asyncRecorder)
+
+	for i := 0; i < count; i++ {
+		d.Publish(context.Background(), testEvent(i))
+	}
+	checkHandled(t, "subscriber", syncRecorder.handled(), count)
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+	checkHandled(t, "asynchronous subscriber", asyncRecorder.handled(), count)
+
+	d.Publish(context.Background(), testEvent(count))
+	checkHandled(t, "asynchronous subscriber after Close", asyncRecorder.handled(), count)
+}
+
+func TestPanickingSubscriber(t *testing.T) {
+	panicking := events.SubscriberFunc(func(ctx context.Context, event events.Event) error {
+		panic("subscriber bug")
+	})
+
+	d := NewDispatcher(10)
+	syncRecorder, asyncRecorder := &recorder{}, &recorder{}
+	d.Subscribe(panicking)
+	d.Subscribe(syncRecorder)
+	d.SubscribeAsync(panicking)
+	d.SubscribeAsync(asyncRecorder)
+
+	for i := 0; i < 3; i++ {
+		d.Publish(quietContext(), testEvent(i))
+	}
+	d.Close()
+
+	checkHandled(t, "subscriber after a panicking one", syncRecorder.handled(), 3)
+	checkHandled(t, "asynchronous subscriber next to a panicking one", asyncRecorder.handled(), 3)
+}
+
+func TestCloseDrainsQueues(t *testing.T) {
+	release := make(chan struct{})
+	blocked := &recorder{}
+	d := NewDispatcher(10)
+	d.SubscribeAsync(events.SubscriberFunc(func(ctx context.Context, event events.Event) error {
+		<-release
+		return blocked.Handle(ctx, event)
+	}))
+
+	for i := 0; i < 5; i++ {
+		d.Publish(context.Background(), testEvent(i))
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		d.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		t.Fatal("Close returned before the queued events were handled")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	<-closed
+	checkHandled(t, "asynchronous subscriber", blocked.handled(), 5)
+}
+
+func TestPublishDropsPastFullQueue(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	slow := &recorder{}
+	d := NewDispatcher(2)
+	d.SubscribeAsync(events.SubscriberFunc(func(ctx context.Context, event events.Event) error {
+		if event == testEvent(0) {
+			close(started)
+		}
+		<-release
+		return slow.Handle(ctx, event)
+	}))
+
+	d.Publish(context.Background(), testEvent(0))
+	<-started
+
+	published := make(chan struct{})
+	go func() {
+		for i := 1; i <= 4; i++ {
+			d.Publish(quietContext(), testEvent(i))
+		}
+		close(published)
+	}()
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("Publish waits for a lagging subscriber")
+	}
+	if dropped := d.Dropped(); dropped != 2 {
+		t.Errorf("Dropped() = %d, want 2", dropped)
+	}
+
+	close(release)
+	d.Close()
+	checkHandled(t, "slow subscriber", slow.handled(), 3)
+}
@@ -0,0 +1,55 @@
+package events
+
+import "github.com/forum-api-back/internal/pkg/models"
+
+// Event is a change made by a usecase. Its fields are copies, so
+// subscribers may keep them.
+type Event interface {
+	Name() string
+}
+
+type UserCreated struct {
+	User *models.User `json:"user"`
+}
+
+type ForumCreated struct {
+	Forum *models.Forum `json:"forum"`
+}
+
+type ThreadCreated struct {
+	Thread *models.Thread `json:"thread"`
+}
+
+type ThreadEdited struct {
+	Thread *models.Thread `json:"thread"`
+	Editor string         `json:"editor"`
+}
+
+// PostsCreated is a batch of posts created in a thread by one request.
+type PostsCreated struct {
+	Thread *models.Thread `json:"thread"`
+	Posts  []*models.Post `json:"posts"`
+}
+
+// PostEdited follows both edits and restored revisions.
+type PostEdited struct {
+	Post   *models.Post `json:"post"`
+	Editor string       `json:"editor"`
+}
+
+// VoteCast carries the thread with its votes after the vote.
+type VoteCast struct {
+	Thread *models.Thread     `json:"thread"`
+	Vote   *models.ThreadVote `json:"vote"`
+}
+
+type BaseCleared struct{}
+
+func (e *UserCreated) Name() string   { return "user_created" }
+func (e *ForumCreated) Name() string  { return "forum_created" }
+func (e *ThreadCreated) Name() string { return "thread_created" }
+func (e *ThreadEdited) Name() string  { return "thread_edited" }
+func (e *PostsCreated) Name() string  { return "posts_created" }
+func (e *PostEdited) Name() string    { return "post_edited" }
+func (e *VoteCast) Name() string      { return "vote_cast" }
+func (e *BaseCleared) Name() string   { return "base_cleared" }
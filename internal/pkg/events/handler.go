@@ -0,0 +1,8 @@
+package events
+
+import "github.com/valyala/fasthttp"
+
+type Handler interface {
+	StreamThreadEvents(ctx *fasthttp.RequestCtx)
+	StreamForumEvents(ctx *fasthttp.RequestCtx)
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/events"
+	"github.com/forum-api-back/pkg/errors"
+	"github.com/forum-api-back/pkg/logger"
+	"github.com/forum-api-back/pkg/tools/http_utils"
+
+	"github.com/valyala/fasthttp"
+)
+
+type EventsHandler struct {
+	EventsUCase events.UseCase
+	// Heartbeat is how often an idle stream gets a comment, so proxies
+	// keep it open and gone clients are noticed.
+	Heartbeat time.Duration
+}
+
+func NewHandler(eventsUCase events.UseCase, heartbeat time.Duration) events.Handler {
+	return &EventsHandler{
+		EventsUCase: eventsUCase,
+		Heartbeat:   heartbeat,
+	}
+}
+
+func (h *EventsHandler) StreamThreadEvents(ctx *fasthttp.RequestCtx) {
+	threadSlugOrId := ctx.UserValue("slug_or_id").(string)
+	if threadSlugOrId == "" {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "slug_or_id", Message: "must not be empty"}))
+		return
+	}
+
+	subscription, err := h.EventsUCase.SubscribeThread(http_utils.Context(ctx), threadSlugOrId)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	h.stream(ctx, subscription)
+}
+
+func (h *EventsHandler) StreamForumEvents(ctx *fasthttp.RequestCtx) {
+	forumSlug := ctx.UserValue("slug").(string)
+	if forumSlug == "" {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "slug", Message: "must not be empty"}))
+		return
+	}
+
+	subscription, err := h.EventsUCase.SubscribeForum(http_utils.Context(ctx), forumSlug)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	h.stream(ctx, subscription)
+}
+
+// stream sends the events of a subscription as Server-Sent Events until
+// the client goes away or the subscription ends.
+func (h *EventsHandler) stream(ctx *fasthttp.RequestCtx, subscription *events.Subscription) {
+	ctx.SetContentType("text/event-stream")
+	ctx.Response.Header.Set("Cache-Control", "no-cache")
+	ctx.Response.Header.Set("X-Accel-Buffering", "no")
+	ctx.SetStatusCode(http.StatusOK)
+
+	conn := ctx.Conn()
+	heartbeat := h.Heartbeat
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer subscription.Close()
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+
+		fmt.Fprintf(w, "retry: %d\n\n", heartbeat.Milliseconds())
+		for {
+			// The server write timeout only covers the start of the
+			// response, every write of the stream gets its own.
+			conn.SetWriteDeadline(time.Now().Add(heartbeat))
+			if err := w.Flush(); err != nil {
+				return
+			}
+
+			select {
+			case event, ok := <-subscription.Events:
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(event)
+				if err != nil {
+					logger.Default().Error("events: can't encode event", "event", event.Type, "error", err)
+					continue
+				}
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			case <-ticker.C:
+				w.WriteString(": ping\n\n")
+			}
+		}
+	})
+}
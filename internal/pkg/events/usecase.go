@@ -0,0 +1,8 @@
+package events
+
+import "context"
+
+type UseCase interface {
+	SubscribeThread(ctx context.Context, threadSlugOrId string) (*Subscription, error)
+	SubscribeForum(ctx context.Context, forumSlug string) (*Subscription, error)
+}
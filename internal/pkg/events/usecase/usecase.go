@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/forum-api-back/internal/pkg/events"
+	"github.com/forum-api-back/internal/pkg/forum"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/thread"
+	"github.com/forum-api-back/pkg/errors"
+)
+
+type EventsUseCase struct {
+	Bus        events.Bus
+	ThreadRepo thread.Repository
+	ForumRepo  forum.Repository
+}
+
+func NewUseCase(bus events.Bus, threadRepo thread.Repository, forumRepo forum.Repository) events.UseCase {
+	return &EventsUseCase{
+		Bus:        bus,
+		ThreadRepo: threadRepo,
+		ForumRepo:  forumRepo,
+	}
+}
+
+func (u *EventsUseCase) SubscribeThread(ctx context.Context, threadSlugOrId string) (*events.Subscription, error) {
+	threadId, err := strconv.Atoi(threadSlugOrId)
+
+	var selectedThread *models.Thread
+	if err != nil {
+		selectedThread, err = u.ThreadRepo.SelectThreadBySlug(ctx, threadSlugOrId)
+	} else if threadId >= 1 {
+		selectedThread, err = u.ThreadRepo.SelectThreadById(ctx, uint64(threadId))
+	} else {
+		return nil, threadNotFound(threadSlugOrId)
+	}
+
+	if err != nil {
+		return nil, errors.Translate(err, threadNotFound(threadSlugOrId))
+	}
+
+	return u.Bus.Subscribe(events.Filter{Thread: selectedThread.Id}), nil
+}
+
+func (u *EventsUseCase) SubscribeForum(ctx context.Context, forumSlug string) (*events.Subscription, error) {
+	selectedForum, err := u.ForumRepo.SelectForumBySlug(ctx, forumSlug)
+	if err != nil {
+		return nil, errors.Translate(err, errors.ErrForumNotFound.WithMessage("Can't find forum by slug: %s", forumSlug))
+	}
+
+	return u.Bus.Subscribe(events.Filter{Forum: selectedForum.Slug}), nil
+}
+
+func threadNotFound(threadSlugOrId string) *errors.Error {
+	return errors.ErrThreadNotFound.WithMessage("Can't find thread by slug or id: %s", threadSlugOrId)
+}
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/events"
+	"github.com/forum-api-back/pkg/tracing"
+)
+
+// TracedUseCase records a span for every method called within a traced request.
+type TracedUseCase struct {
+	ucase events.UseCase
+}
+
+func NewTracedUseCase(ucase events.UseCase) events.UseCase {
+	return &TracedUseCase{
+		ucase: ucase,
+	}
+}
+
+func (u *TracedUseCase) SubscribeThread(ctx context.Context,
+	threadSlugOrId string) (_ *events.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "events.usecase.SubscribeThread")
+	defer func() { span.End(err) }()
+	return u.ucase.SubscribeThread(ctx, threadSlugOrId)
+}
+
+func (u *TracedUseCase) SubscribeForum(ctx context.Context,
+	forumSlug string) (_ *events.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "events.usecase.SubscribeForum")
+	defer func() { span.End(err) }()
+	return u.ucase.SubscribeForum(ctx, forumSlug)
+}
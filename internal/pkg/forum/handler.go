@@ -7,4 +7,7 @@ import (
 type Handler interface {
 	CreateNewForum(ctx *fasthttp.RequestCtx)
 	GetForumDetails(ctx *fasthttp.RequestCtx)
+	ArchiveForum(ctx *fasthttp.RequestCtx)
+	UnarchiveForum(ctx *fasthttp.RequestCtx)
+	DeleteForum(ctx *fasthttp.RequestCtx)
 }
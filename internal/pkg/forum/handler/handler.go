@@ -25,37 +25,75 @@ func NewHandler(forumUCase forum.UseCase) forum.Handler {
 func (h *ForumHandler) CreateNewForum(ctx *fasthttp.RequestCtx) {
 	forumInfo := &models.ForumCreate{}
 	if err := json.Unmarshal(ctx.PostBody(), forumInfo); err != nil {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadRequest, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadRequest.Wrap(err))
 		return
 	}
 
-	newForum, err := h.ForumUCase.CreateNewForum(forumInfo)
-	switch err {
-	case nil:
+	newForum, err := h.ForumUCase.CreateNewForum(http_utils.Context(ctx), forumInfo)
+	switch {
+	case err == nil:
 		http_utils.SetJSONResponse(ctx, newForum, http.StatusCreated)
-	case errors.ErrUserNotFound:
-		http_utils.SetJSONResponse(ctx, errors.ErrUserNotFound, http.StatusNotFound)
-	case errors.ErrAlreadyExists:
+	case errors.Is(err, errors.ErrAlreadyExists):
 		http_utils.SetJSONResponse(ctx, newForum, http.StatusConflict)
 	default:
-		http_utils.SetJSONResponse(ctx, errors.ErrInternalError, http.StatusInternalServerError)
+		http_utils.SetErrorResponse(ctx, err)
 	}
 }
 
 func (h *ForumHandler) GetForumDetails(ctx *fasthttp.RequestCtx) {
 	forumSlug := ctx.UserValue("slug").(string)
 	if forumSlug == "" {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadArguments, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "slug", Message: "must not be empty"}))
 		return
 	}
 
-	selectedForum, err := h.ForumUCase.GetForumDetails(forumSlug)
-	switch err {
-	case nil:
-		http_utils.SetJSONResponse(ctx, selectedForum, http.StatusOK)
-	case errors.ErrForumNotFound:
-		http_utils.SetJSONResponse(ctx, errors.ErrForumNotFound, http.StatusNotFound)
-	default:
-		http_utils.SetJSONResponse(ctx, errors.ErrInternalError, http.StatusInternalServerError)
+	selectedForum, err := h.ForumUCase.GetForumDetails(http_utils.Context(ctx), forumSlug)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, selectedForum, http.StatusOK)
+}
+
+func (h *ForumHandler) ArchiveForum(ctx *fasthttp.RequestCtx) {
+	h.setForumArchived(ctx, true)
+}
+
+func (h *ForumHandler) UnarchiveForum(ctx *fasthttp.RequestCtx) {
+	h.setForumArchived(ctx, false)
+}
+
+func (h *ForumHandler) setForumArchived(ctx *fasthttp.RequestCtx, isArchived bool) {
+	forumSlug := ctx.UserValue("slug").(string)
+	if forumSlug == "" {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "slug", Message: "must not be empty"}))
+		return
 	}
+
+	updatedForum, err := h.ForumUCase.SetForumArchived(http_utils.Context(ctx), forumSlug, isArchived)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, updatedForum, http.StatusOK)
+}
+
+func (h *ForumHandler) DeleteForum(ctx *fasthttp.RequestCtx) {
+	forumSlug := ctx.UserValue("slug").(string)
+	if forumSlug == "" {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "slug", Message: "must not be empty"}))
+		return
+	}
+
+	if err := h.ForumUCase.DeleteForum(http_utils.Context(ctx), forumSlug); err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, struct{}{}, http.StatusOK)
 }
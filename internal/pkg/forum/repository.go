@@ -1,8 +1,17 @@
 package forum
 
-import "github.com/forum-api-back/internal/pkg/models"
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
 
 type Repository interface {
-	InsertForum(forumInfo *models.ForumCreate) error
-	SelectForumBySlug(forumSlug string) (*models.Forum, error)
+	InsertForum(ctx context.Context, forumInfo *models.ForumCreate) error
+	SelectForumBySlug(ctx context.Context, forumSlug string) (*models.Forum, error)
+	// DeleteForumBySlug removes a forum with its threads, posts, votes,
+	// authors and moderators.
+	DeleteForumBySlug(ctx context.Context, forumSlug string) error
+	// UpdateForumArchived archives or reopens a forum and all its threads.
+	UpdateForumArchived(ctx context.Context, forumSlug string, isArchived bool) error
 }
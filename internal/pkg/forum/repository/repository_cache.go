@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/cache"
+	"github.com/forum-api-back/internal/pkg/forum"
+	"github.com/forum-api-back/internal/pkg/models"
+)
+
+// CachedRepository reads forums through a cache. Forums are also dropped
+// from it when threads and posts change their counters, see the thread
+// and post repositories.
+type CachedRepository struct {
+	forum.Repository
+	cache cache.Cache
+}
+
+func NewCachedRepository(repo forum.Repository, c cache.Cache) forum.Repository {
+	return &CachedRepository{
+		Repository: repo,
+		cache:      c,
+	}
+}
+
+func (r *CachedRepository) SelectForumBySlug(ctx context.Context, forumSlug string) (*models.Forum, error) {
+	var cachedForum models.Forum
+	if r.cache.Get(ctx, cache.ForumKey(forumSlug), &cachedForum) {
+		return &cachedForum, nil
+	}
+
+	selectedForum, err := r.Repository.SelectForumBySlug(ctx, forumSlug)
+	if err != nil {
+		return nil, err
+	}
+	r.cache.Set(ctx, cache.ForumKey(forumSlug), *selectedForum)
+
+	return selectedForum, nil
+}
+
+// DeleteForumBySlug removes every thread of the forum, so all the cached
+// threads are dropped.
+func (r *CachedRepository) DeleteForumBySlug(ctx context.Context, forumSlug string) error {
+	err := r.Repository.DeleteForumBySlug(ctx, forumSlug)
+	r.cache.Delete(ctx, cache.ForumKey(forumSlug))
+	r.cache.DeletePrefix(ctx, cache.ThreadNamespace)
+	return err
+}
+
+func (r *CachedRepository) UpdateForumArchived(ctx context.Context, forumSlug string, isArchived bool) error {
+	err := r.Repository.UpdateForumArchived(ctx, forumSlug, isArchived)
+	r.cache.Delete(ctx, cache.ForumKey(forumSlug))
+	return err
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/cache"
+	"github.com/forum-api-back/internal/pkg/cache/lru"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+)
+
+func TestCachedRepositoryInvalidates(t *testing.T) {
+	tests := []struct {
+		name         string
+		change       func(ctx context.Context, r *CachedRepository) error
+		keepsThreads bool
+	}{
+		{"archive", func(ctx context.Context, r *CachedRepository) error {
+			return r.UpdateForumArchived(ctx, "F", true)
+		}, true},
+		{"delete", func(ctx context.Context, r *CachedRepository) error {
+			return r.DeleteForumBySlug(ctx, "F")
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := memory.NewStorage()
+			storage.Forums["f"] = &models.Forum{Title: "Forum", AuthorNickName: "alice", Slug: "f"}
+			storage.Threads[1] = &models.Thread{Id: 1, Forum: "f"}
+			hotCache := lru.NewCache(10, time.Hour)
+			r := NewCachedRepository(NewSessionMemoryRepository(storage), hotCache).(*CachedRepository)
+			ctx := context.Background()
+
+			if _, err := r.SelectForumBySlug(ctx, "f"); err != nil {
+				t.Fatal(err)
+			}
+			hotCache.Set(ctx, cache.ThreadKey(1), *storage.Threads[1])
+
+			// Reads are served from the cache until a change drops them.
+			storage.Forums["f"].Title = "Changed"
+			if cachedForum, _ := r.SelectForumBySlug(ctx, "f"); cachedForum.Title != "Forum" {
+				t.Fatalf("title = %q, want the cached one", cachedForum.Title)
+			}
+
+			if err := tt.change(ctx, r); err != nil {
+				t.Fatal(err)
+			}
+			var cachedForum models.Forum
+			if hotCache.Get(ctx, cache.ForumKey("f"), &cachedForum) {
+				t.Errorf("forum %+v is still cached", cachedForum)
+			}
+			var cachedThread models.Thread
+			if isCached := hotCache.Get(ctx, cache.ThreadKey(1), &cachedThread); isCached != tt.keepsThreads {
+				t.Errorf("thread of the forum cached = %v, want %v", isCached, tt.keepsThreads)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/forum"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+	"github.com/forum-api-back/pkg/errors"
+)
+
+type MemoryRepository struct {
+	storage *memory.Storage
+}
+
+func NewSessionMemoryRepository(storage *memory.Storage) forum.Repository {
+	return &MemoryRepository{
+		storage: storage,
+	}
+}
+
+func (r *MemoryRepository) InsertForum(ctx context.Context, forumInfo *models.ForumCreate) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	forumKey := memory.Key(forumInfo.Slug)
+	if _, ok := r.storage.Forums[forumKey]; ok {
+		return errors.ErrDataConflict
+	}
+	if _, ok := r.storage.UserByNickName(forumInfo.AuthorNickName); !ok {
+		return errors.ErrDataConflict
+	}
+
+	r.storage.Forums[forumKey] = &models.Forum{
+		Title:          forumInfo.Title,
+		AuthorNickName: forumInfo.AuthorNickName,
+		Slug:           forumInfo.Slug,
+	}
+
+	return nil
+}
+
+func (r *MemoryRepository) SelectForumBySlug(ctx context.Context, forumSlug string) (*models.Forum, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	selectedForum, ok := r.storage.Forums[memory.Key(forumSlug)]
+	if !ok {
+		return nil, errors.ErrNotFoundInDB
+	}
+
+	copiedForum := *selectedForum
+	return &copiedForum, nil
+}
+
+func (r *MemoryRepository) DeleteForumBySlug(ctx context.Context, forumSlug string) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	forumKey := memory.Key(forumSlug)
+	if _, ok := r.storage.Forums[forumKey]; !ok {
+		return errors.ErrNotFoundInDB
+	}
+
+	for threadId, selectedThread := range r.storage.Threads {
+		if memory.Key(selectedThread.Forum) == forumKey {
+			r.storage.RemoveThread(threadId)
+		}
+	}
+	for webhookId, webhook := range r.storage.Webhooks {
+		if memory.Key(webhook.Forum) == forumKey {
+			r.storage.RemoveWebhook(webhookId)
+		}
+	}
+	delete(r.storage.Authors, forumKey)
+	delete(r.storage.Moderators, forumKey)
+	delete(r.storage.Forums, forumKey)
+
+	return nil
+}
+
+func (r *MemoryRepository) UpdateForumArchived(ctx context.Context, forumSlug string, isArchived bool) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	selectedForum, ok := r.storage.Forums[memory.Key(forumSlug)]
+	if !ok {
+		return errors.ErrNotFoundInDB
+	}
+	selectedForum.IsArchived = isArchived
+
+	return nil
+}
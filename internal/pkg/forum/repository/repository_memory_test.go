@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+	"github.com/forum-api-back/pkg/errors"
+)
+
+func TestUpdateForumArchivedKeepsThreads(t *testing.T) {
+	storage := memory.NewStorage()
+	storage.Forums["f"] = &models.Forum{Title: "Forum", AuthorNickName: "alice", Slug: "f"}
+	storage.Threads[1] = &models.Thread{Id: 1, Forum: "f", IsArchived: true}
+	storage.Threads[2] = &models.Thread{Id: 2, Forum: "f"}
+	r := NewSessionMemoryRepository(storage)
+	ctx := context.Background()
+
+	for _, isArchived := range []bool{true, false} {
+		if err := r.UpdateForumArchived(ctx, "F", isArchived); err != nil {
+			t.Fatal(err)
+		}
+		selectedForum, err := r.SelectForumBySlug(ctx, "f")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if selectedForum.IsArchived != isArchived {
+			t.Errorf("forum archived = %v, want %v", selectedForum.IsArchived, isArchived)
+		}
+		if !storage.Threads[1].IsArchived || storage.Threads[2].IsArchived {
+			t.Errorf("threads archived = %v and %v, want them unchanged",
+				storage.Threads[1].IsArchived, storage.Threads[2].IsArchived)
+		}
+	}
+
+	if err := r.UpdateForumArchived(ctx, "g", true); !errors.Is(err, errors.ErrNotFoundInDB) {
+		t.Errorf("UpdateForumArchived() error = %v, want %v", err, errors.ErrNotFoundInDB)
+	}
+}
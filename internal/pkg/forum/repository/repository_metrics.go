@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/forum"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/pkg/logger"
+	"github.com/forum-api-back/pkg/metrics"
+)
+
+// MeasuredRepository observes how long every method takes and logs the
+// calls taking slowQuery or longer.
+type MeasuredRepository struct {
+	repo      forum.Repository
+	durations *metrics.HistogramVec
+	slowQuery time.Duration
+}
+
+// NewMeasuredRepository observes the durations of the methods of repo into
+// durations, labeled with the repository and the method. Calls taking
+// slowQuery or longer are logged as well, unless it is zero.
+func NewMeasuredRepository(repo forum.Repository, durations *metrics.HistogramVec,
+	slowQuery time.Duration) forum.Repository {
+	return &MeasuredRepository{
+		repo:      repo,
+		durations: durations,
+		slowQuery: slowQuery,
+	}
+}
+
+func (r *MeasuredRepository) InsertForum(ctx context.Context, forumInfo *models.ForumCreate) error {
+	defer r.observe(ctx, "InsertForum", time.Now())
+	return r.repo.InsertForum(ctx, forumInfo)
+}
+
+func (r *MeasuredRepository) SelectForumBySlug(ctx context.Context, forumSlug string) (*models.Forum, error) {
+	defer r.observe(ctx, "SelectForumBySlug", time.Now())
+	return r.repo.SelectForumBySlug(ctx, forumSlug)
+}
+
+func (r *MeasuredRepository) DeleteForumBySlug(ctx context.Context, forumSlug string) error {
+	defer r.observe(ctx, "DeleteForumBySlug", time.Now())
+	return r.repo.DeleteForumBySlug(ctx, forumSlug)
+}
+
+func (r *MeasuredRepository) UpdateForumArchived(ctx context.Context, forumSlug string, isArchived bool) error {
+	defer r.observe(ctx, "UpdateForumArchived", time.Now())
+	return r.repo.UpdateForumArchived(ctx, forumSlug, isArchived)
+}
+
+func (r *MeasuredRepository) observe(ctx context.Context, method string, start time.Time) {
+	elapsed := time.Since(start)
+	r.durations.With("forum", method).Observe(elapsed.Seconds())
+	if r.slowQuery > 0 && elapsed >= r.slowQuery {
+		logger.FromContext(ctx).Warn("slow query", "repository", "forum", "method", method, "duration", elapsed)
+	}
+}
@@ -1,11 +1,13 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/forum-api-back/internal/pkg/forum"
 	"github.com/forum-api-back/internal/pkg/models"
 	"github.com/forum-api-back/pkg/errors"
+	"github.com/forum-api-back/pkg/tools/sql_utils"
 )
 
 type PostgresqlRepository struct {
@@ -18,8 +20,8 @@ func NewSessionPostgresqlRepository(db *sql.DB) forum.Repository {
 	}
 }
 
-func (r *PostgresqlRepository) InsertForum(forumInfo *models.ForumCreate) error {
-	_, err := r.db.Exec(
+func (r *PostgresqlRepository) InsertForum(ctx context.Context, forumInfo *models.ForumCreate) error {
+	_, err := r.db.ExecContext(ctx,
 		"INSERT INTO forums(title, author_nickname, slug) "+
 			"VALUES ($1, $2, $3)",
 		forumInfo.Title,
@@ -27,16 +29,12 @@ func (r *PostgresqlRepository) InsertForum(forumInfo *models.ForumCreate) error
 		forumInfo.Slug,
 	)
 
-	if err != nil {
-		return errors.ErrDataConflict
-	}
-
-	return nil
+	return sql_utils.Error(err, errors.ErrNotFoundInDB)
 }
 
-func (r *PostgresqlRepository) SelectForumBySlug(forumSlug string) (*models.Forum, error) {
-	row := r.db.QueryRow(
-		"SELECT title, author_nickname, slug, count_posts, count_threads "+
+func (r *PostgresqlRepository) SelectForumBySlug(ctx context.Context, forumSlug string) (*models.Forum, error) {
+	row := r.db.QueryRowContext(ctx,
+		"SELECT title, author_nickname, slug, count_posts, count_threads, is_archived "+
 			"FROM forums "+
 			"WHERE slug = $1",
 		forumSlug,
@@ -49,14 +47,55 @@ func (r *PostgresqlRepository) SelectForumBySlug(forumSlug string) (*models.Foru
 		&selectedForum.Slug,
 		&selectedForum.Posts,
 		&selectedForum.Threads,
+		&selectedForum.IsArchived,
+	)
+
+	if err != nil {
+		return nil, sql_utils.Error(err, errors.ErrNotFoundInDB)
+	}
+
+	return selectedForum, nil
+}
+
+func (r *PostgresqlRepository) DeleteForumBySlug(ctx context.Context, forumSlug string) error {
+	result, err := r.db.ExecContext(ctx,
+		"DELETE FROM forums "+
+			"WHERE slug = $1",
+		forumSlug,
 	)
+	if err != nil {
+		return sql_utils.Error(err, errors.ErrNotFoundInDB)
+	}
 
-	switch err {
-	case nil:
-		return selectedForum, nil
-	case sql.ErrNoRows:
-		return nil, errors.ErrNotFoundInDB
-	default:
-		return nil, errors.ErrInternalError
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return sql_utils.Error(err, errors.ErrNotFoundInDB)
 	}
+	if deleted == 0 {
+		return errors.ErrNotFoundInDB
+	}
+
+	return nil
+}
+
+func (r *PostgresqlRepository) UpdateForumArchived(ctx context.Context, forumSlug string, isArchived bool) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE forums SET "+
+			"is_archived = $2 "+
+			"WHERE slug = $1",
+		forumSlug,
+		isArchived,
+	)
+	if err != nil {
+		return sql_utils.Error(err, errors.ErrNotFoundInDB)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return sql_utils.Error(err, errors.ErrNotFoundInDB)
+	}
+	if updated == 0 {
+		return errors.ErrNotFoundInDB
+	}
+
+	return nil
 }
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/forum"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/pkg/tracing"
+)
+
+// TracedRepository records a span for every method called within a traced request.
+type TracedRepository struct {
+	repo forum.Repository
+}
+
+func NewTracedRepository(repo forum.Repository) forum.Repository {
+	return &TracedRepository{
+		repo: repo,
+	}
+}
+
+func (r *TracedRepository) InsertForum(ctx context.Context, forumInfo *models.ForumCreate) (err error) {
+	ctx, span := tracing.Start(ctx, "forum.repository.InsertForum")
+	defer func() { span.End(err) }()
+	return r.repo.InsertForum(ctx, forumInfo)
+}
+
+func (r *TracedRepository) SelectForumBySlug(ctx context.Context,
+	forumSlug string) (_ *models.Forum, err error) {
+	ctx, span := tracing.Start(ctx, "forum.repository.SelectForumBySlug")
+	defer func() { span.End(err) }()
+	return r.repo.SelectForumBySlug(ctx, forumSlug)
+}
+
+func (r *TracedRepository) DeleteForumBySlug(ctx context.Context, forumSlug string) (err error) {
+	ctx, span := tracing.Start(ctx, "forum.repository.DeleteForumBySlug")
+	defer func() { span.End(err) }()
+	return r.repo.DeleteForumBySlug(ctx, forumSlug)
+}
+
+func (r *TracedRepository) UpdateForumArchived(ctx context.Context, forumSlug string,
+	isArchived bool) (err error) {
+	ctx, span := tracing.Start(ctx, "forum.repository.UpdateForumArchived")
+	defer func() { span.End(err) }()
+	return r.repo.UpdateForumArchived(ctx, forumSlug, isArchived)
+}
@@ -1,8 +1,14 @@
 package forum
 
-import "github.com/forum-api-back/internal/pkg/models"
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
 
 type UseCase interface {
-	CreateNewForum(forumInfo *models.ForumCreate) (*models.Forum, error)
-	GetForumDetails(slug string) (*models.Forum, error)
+	CreateNewForum(ctx context.Context, forumInfo *models.ForumCreate) (*models.Forum, error)
+	GetForumDetails(ctx context.Context, slug string) (*models.Forum, error)
+	SetForumArchived(ctx context.Context, slug string, isArchived bool) (*models.Forum, error)
+	DeleteForum(ctx context.Context, slug string) error
 }
@@ -1,55 +1,98 @@
 package usecase
 
 import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/events"
 	"github.com/forum-api-back/internal/pkg/forum"
 	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/roles"
 	"github.com/forum-api-back/internal/pkg/user"
 	"github.com/forum-api-back/pkg/errors"
 )
 
 type ForumUseCase struct {
-	ForumRepo forum.Repository
-	UserRepo  user.Repository
+	ForumRepo  forum.Repository
+	UserRepo   user.Repository
+	RolesUCase roles.UseCase
+	Events     events.Publisher
 }
 
-func NewUseCase(forumRepo forum.Repository, userRepo user.Repository) forum.UseCase {
+func NewUseCase(forumRepo forum.Repository, userRepo user.Repository, rolesUCase roles.UseCase,
+	publisher events.Publisher) forum.UseCase {
 	return &ForumUseCase{
-		ForumRepo: forumRepo,
-		UserRepo:  userRepo,
+		ForumRepo:  forumRepo,
+		UserRepo:   userRepo,
+		RolesUCase: rolesUCase,
+		Events:     publisher,
 	}
 }
 
-func (u *ForumUseCase) CreateNewForum(forumInfo *models.ForumCreate) (*models.Forum, error) {
-	author, err := u.UserRepo.SelectUserByNickName(forumInfo.AuthorNickName)
+func (u *ForumUseCase) CreateNewForum(ctx context.Context, forumInfo *models.ForumCreate) (*models.Forum, error) {
+	actor, err := u.RolesUCase.Actor(ctx, forumInfo.AuthorNickName)
 	if err != nil {
-		return nil, errors.ErrUserNotFound
+		return nil, err
+	}
+	forumInfo.AuthorNickName = actor
+
+	author, err := u.UserRepo.SelectUserByNickName(ctx, forumInfo.AuthorNickName)
+	if err != nil {
+		return nil, errors.Translate(err, errors.ErrUserNotFound.WithMessage("Can't find user by nickname: %s", forumInfo.AuthorNickName))
 	}
 	forumInfo.AuthorNickName = author.NickName
 
-	err = u.ForumRepo.InsertForum(forumInfo)
-	switch err {
-	case nil:
-		return &models.Forum{
+	err = u.ForumRepo.InsertForum(ctx, forumInfo)
+	switch {
+	case err == nil:
+		newForum := &models.Forum{
 			Title:          forumInfo.Title,
 			AuthorNickName: forumInfo.AuthorNickName,
 			Slug:           forumInfo.Slug,
-		}, nil
-	case errors.ErrDataConflict:
-		selectedForum, err := u.ForumRepo.SelectForumBySlug(forumInfo.Slug)
-		if err != nil {
-			return nil, errors.ErrInternalError
 		}
-		return selectedForum, errors.ErrAlreadyExists
+		copiedForum := *newForum
+		u.Events.Publish(ctx, &events.ForumCreated{Forum: &copiedForum})
+		return newForum, nil
+	case errors.Is(err, errors.ErrDataConflict):
+		selectedForum, selectErr := u.ForumRepo.SelectForumBySlug(ctx, forumInfo.Slug)
+		if selectErr != nil {
+			return nil, selectErr
+		}
+		return selectedForum, errors.ErrAlreadyExists.Wrap(err)
 	default:
-		return nil, errors.ErrInternalError
+		return nil, err
 	}
 }
 
-func (u *ForumUseCase) GetForumDetails(forumSlug string) (*models.Forum, error) {
-	selectedForum, err := u.ForumRepo.SelectForumBySlug(forumSlug)
+func (u *ForumUseCase) GetForumDetails(ctx context.Context, forumSlug string) (*models.Forum, error) {
+	selectedForum, err := u.ForumRepo.SelectForumBySlug(ctx, forumSlug)
 	if err != nil {
-		return nil, errors.ErrForumNotFound
+		return nil, errors.Translate(err, forumNotFound(forumSlug))
 	}
 
 	return selectedForum, nil
 }
+
+func (u *ForumUseCase) SetForumArchived(ctx context.Context, forumSlug string, isArchived bool) (*models.Forum, error) {
+	if err := u.RolesUCase.Authorize(ctx, forumSlug, models.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	if err := u.ForumRepo.UpdateForumArchived(ctx, forumSlug, isArchived); err != nil {
+		return nil, errors.Translate(err, forumNotFound(forumSlug))
+	}
+
+	return u.GetForumDetails(ctx, forumSlug)
+}
+
+func (u *ForumUseCase) DeleteForum(ctx context.Context, forumSlug string) error {
+	if err := u.RolesUCase.Authorize(ctx, forumSlug, models.RoleOwner); err != nil {
+		return err
+	}
+
+	err := u.ForumRepo.DeleteForumBySlug(ctx, forumSlug)
+	return errors.Translate(err, forumNotFound(forumSlug))
+}
+
+func forumNotFound(forumSlug string) *errors.Error {
+	return errors.ErrForumNotFound.WithMessage("Can't find forum by slug: %s", forumSlug)
+}
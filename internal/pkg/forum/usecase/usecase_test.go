@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/forum-api-back/internal/pkg/auth"
+	events_dispatcher "github.com/forum-api-back/internal/pkg/events/dispatcher"
+	"github.com/forum-api-back/internal/pkg/forum"
+	forum_repo "github.com/forum-api-back/internal/pkg/forum/repository"
+	"github.com/forum-api-back/internal/pkg/models"
+	roles_repo "github.com/forum-api-back/internal/pkg/roles/repository"
+	roles_usecase "github.com/forum-api-back/internal/pkg/roles/usecase"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+	user_repo "github.com/forum-api-back/internal/pkg/user/repository"
+	"github.com/forum-api-back/pkg/errors"
+)
+
+// newTestUseCase returns a usecase over users "guest", without a password,
+// and "member", with one.
+func newTestUseCase(t *testing.T) forum.UseCase {
+	t.Helper()
+	ctx := context.Background()
+
+	storage := memory.NewStorage()
+	userRepo := user_repo.NewSessionMemoryRepository(storage)
+	forumRepo := forum_repo.NewSessionMemoryRepository(storage)
+
+	for _, nickname := range []string{"member", "guest"} {
+		newUser := &models.User{NickName: nickname, Email: nickname + "@example.com"}
+		if nickname != "guest" {
+			newUser.PasswordHash = "hash"
+		}
+		if err := userRepo.InsertUser(ctx, newUser); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rolesUCase := roles_usecase.NewUseCase(roles_repo.NewSessionMemoryRepository(storage), forumRepo, userRepo, nil)
+	return NewUseCase(forumRepo, userRepo, rolesUCase, events_dispatcher.NewDispatcher(0))
+}
+
+func callerContext(caller string) context.Context {
+	if caller == "" {
+		return context.Background()
+	}
+	return auth.WithCaller(context.Background(), caller)
+}
+
+func checkCode(t *testing.T, err error, code errors.Code) {
+	t.Helper()
+	switch {
+	case code == "" && err != nil:
+		t.Fatalf("error = %v", err)
+	case code != "" && errors.From(err).Code != code:
+		t.Fatalf("error = %v, want code %v", err, code)
+	}
+}
+
+func TestCreateNewForumAuthor(t *testing.T) {
+	tests := []struct {
+		name   string
+		caller string
+		author string
+		want   string
+		code   errors.Code
+	}{
+		{"session replaces the body", "member", "guest", "member", ""},
+		{"no session, author without password", "", "GUEST", "guest", ""},
+		{"no session, author with password", "", "member", "", errors.CodeUnauthorized},
+		{"no session, unknown author", "", "nobody", "", errors.CodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestUseCase(t)
+
+			newForum, err := u.CreateNewForum(callerContext(tt.caller),
+				&models.ForumCreate{Title: "Forum", AuthorNickName: tt.author, Slug: "f"})
+			checkCode(t, err, tt.code)
+			if err == nil && newForum.AuthorNickName != tt.want {
+				t.Errorf("author = %q, want %q", newForum.AuthorNickName, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateNewForum(t *testing.T) {
+	tests := []struct {
+		name  string
+		forum models.ForumCreate
+		want  models.Forum
+		code  errors.Code
+	}{
+		{
+			name:  "new slug",
+			forum: models.ForumCreate{Title: "Other", AuthorNickName: "guest", Slug: "g"},
+			want:  models.Forum{Title: "Other", AuthorNickName: "guest", Slug: "g"},
+		},
+		{
+			name:  "taken slug returns the existing forum",
+			forum: models.ForumCreate{Title: "Other", AuthorNickName: "guest", Slug: "F"},
+			want:  models.Forum{Title: "Forum", AuthorNickName: "guest", Slug: "f"},
+			code:  errors.CodeConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestUseCase(t)
+			ctx := context.Background()
+			_, err := u.CreateNewForum(ctx, &models.ForumCreate{Title: "Forum", AuthorNickName: "guest", Slug: "f"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			newForum, err := u.CreateNewForum(ctx, &tt.forum)
+			checkCode(t, err, tt.code)
+			if newForum == nil || *newForum != tt.want {
+				t.Errorf("forum = %+v, want %+v", newForum, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetForumDetails(t *testing.T) {
+	u := newTestUseCase(t)
+	ctx := context.Background()
+	_, err := u.CreateNewForum(ctx, &models.ForumCreate{Title: "Forum", AuthorNickName: "guest", Slug: "f"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	selectedForum, err := u.GetForumDetails(ctx, "F")
+	if err != nil || selectedForum.Slug != "f" {
+		t.Errorf("GetForumDetails() = %+v, %v, want forum f", selectedForum, err)
+	}
+
+	_, err = u.GetForumDetails(ctx, "g")
+	checkCode(t, err, errors.CodeNotFound)
+}
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/forum"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/pkg/tracing"
+)
+
+// TracedUseCase records a span for every method called within a traced request.
+type TracedUseCase struct {
+	ucase forum.UseCase
+}
+
+func NewTracedUseCase(ucase forum.UseCase) forum.UseCase {
+	return &TracedUseCase{
+		ucase: ucase,
+	}
+}
+
+func (u *TracedUseCase) CreateNewForum(ctx context.Context,
+	forumInfo *models.ForumCreate) (_ *models.Forum, err error) {
+	ctx, span := tracing.Start(ctx, "forum.usecase.CreateNewForum")
+	defer func() { span.End(err) }()
+	return u.ucase.CreateNewForum(ctx, forumInfo)
+}
+
+func (u *TracedUseCase) GetForumDetails(ctx context.Context, slug string) (_ *models.Forum, err error) {
+	ctx, span := tracing.Start(ctx, "forum.usecase.GetForumDetails")
+	defer func() { span.End(err) }()
+	return u.ucase.GetForumDetails(ctx, slug)
+}
+
+func (u *TracedUseCase) SetForumArchived(ctx context.Context, slug string,
+	isArchived bool) (_ *models.Forum, err error) {
+	ctx, span := tracing.Start(ctx, "forum.usecase.SetForumArchived")
+	defer func() { span.End(err) }()
+	return u.ucase.SetForumArchived(ctx, slug, isArchived)
+}
+
+func (u *TracedUseCase) DeleteForum(ctx context.Context, slug string) (err error) {
+	ctx, span := tracing.Start(ctx, "forum.usecase.DeleteForum")
+	defer func() { span.End(err) }()
+	return u.ucase.DeleteForum(ctx, slug)
+}
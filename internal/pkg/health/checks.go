@@ -0,0 +1,75 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/forum-api-back/internal/pkg/migrations"
+)
+
+// DatabaseCheck pings the database.
+func DatabaseCheck(db *sql.DB) Check {
+	return func(ctx context.Context) (interface{}, error) {
+		return nil, db.PingContext(ctx)
+	}
+}
+
+type SchemaDetails struct {
+	Version int `json:"version"`
+	Latest  int `json:"latest"`
+}
+
+// SchemaVersion reads the version of the schema, like migrations.Migrator.
+type SchemaVersion interface {
+	Version(ctx context.Context) (int, error)
+}
+
+// SchemaCheck fails while the schema lacks migrations known to this
+// build. A newer schema, migrated by a newer build, is fine.
+func SchemaCheck(schema SchemaVersion) Check {
+	return func(ctx context.Context) (interface{}, error) {
+		version, err := schema.Version(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		details := &SchemaDetails{Version: version, Latest: migrations.Latest()}
+		if details.Version < details.Latest {
+			return details, fmt.Errorf("schema version %d is behind %d", details.Version, details.Latest)
+		}
+		return details, nil
+	}
+}
+
+type PoolDetails struct {
+	MaxOpen int `json:"maxOpen"`
+	Open    int `json:"open"`
+	InUse   int `json:"inUse"`
+	Idle    int `json:"idle"`
+	// Saturation is the share of MaxOpen in use, zero without a limit.
+	Saturation   float64 `json:"saturation"`
+	WaitCount    int64   `json:"waitCount"`
+	WaitDuration string  `json:"waitDuration"`
+}
+
+// PoolCheck reports how busy the connection pool is. A saturated pool
+// makes requests wait but doesn't fail readiness, as taking a busy
+// instance out would only load the others more.
+func PoolCheck(db *sql.DB) Check {
+	return func(ctx context.Context) (interface{}, error) {
+		stats := db.Stats()
+		details := &PoolDetails{
+			MaxOpen:      stats.MaxOpenConnections,
+			Open:         stats.OpenConnections,
+			InUse:        stats.InUse,
+			Idle:         stats.Idle,
+			WaitCount:    stats.WaitCount,
+			WaitDuration: stats.WaitDuration.String(),
+		}
+		if stats.MaxOpenConnections > 0 {
+			details.Saturation = float64(stats.InUse) / float64(stats.MaxOpenConnections)
+		}
+		return details, nil
+	}
+}
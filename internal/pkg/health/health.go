@@ -0,0 +1,151 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/pkg/tools/http_utils"
+
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	StatusAlive    = "alive"
+	StatusReady    = "ready"
+	StatusNotReady = "not ready"
+)
+
+// Status is what the probes answer anyone asking. The checks behind
+// readiness may expose database errors and pool internals, so only Check
+// returns them, for admins.
+type Status struct {
+	Status      string `json:"status"`
+	Maintenance bool   `json:"maintenance,omitempty"`
+}
+
+// Check looks at a dependency of the server. An error makes the server
+// not ready, details are reported either way.
+type Check func(ctx context.Context) (details interface{}, err error)
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// State tracks whether the server accepts new traffic. It is flipped to
+// not ready before shutdown so load balancers stop routing requests here
+// while in-flight ones are drained, and while the server is in
+// maintenance. Liveness only depends on the process serving requests.
+type State struct {
+	ready       int32
+	maintenance int32
+
+	checkTimeout time.Duration
+	checks       []namedCheck
+}
+
+// NewState returns a state whose readiness checks, added by AddCheck, get
+// checkTimeout to finish together.
+func NewState(checkTimeout time.Duration) *State {
+	return &State{
+		checkTimeout: checkTimeout,
+	}
+}
+
+// AddCheck adds a readiness check. Checks must be added before serving.
+func (s *State) AddCheck(name string, check Check) {
+	s.checks = append(s.checks, namedCheck{name: name, check: check})
+}
+
+func (s *State) SetReady(ready bool) {
+	atomic.StoreInt32(&s.ready, boolToInt32(ready))
+}
+
+func (s *State) IsReady() bool {
+	return atomic.LoadInt32(&s.ready) == 1
+}
+
+// SetMaintenance switches the maintenance mode, in which the server keeps
+// serving but reports it isn't ready.
+func (s *State) SetMaintenance(maintenance bool) {
+	atomic.StoreInt32(&s.maintenance, boolToInt32(maintenance))
+}
+
+func (s *State) InMaintenance() bool {
+	return atomic.LoadInt32(&s.maintenance) == 1
+}
+
+func (s *State) Liveness(ctx *fasthttp.RequestCtx) {
+	http_utils.SetJSONResponse(ctx, Status{Status: StatusAlive, Maintenance: s.InMaintenance()}, http.StatusOK)
+}
+
+// Readiness answers whether the server is ready, without telling why not.
+func (s *State) Readiness(ctx *fasthttp.RequestCtx) {
+	health := s.Check(http_utils.Context(ctx))
+	if health.Status != StatusReady {
+		http_utils.SetJSONResponse(ctx, Status{Status: health.Status}, http.StatusServiceUnavailable)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, Status{Status: health.Status}, http.StatusOK)
+}
+
+// Check runs the checks. The server isn't ready when one of them fails,
+// when it is shutting down or is in maintenance.
+func (s *State) Check(ctx context.Context) *models.Health {
+	health := &models.Health{
+		Status:      StatusReady,
+		Maintenance: s.InMaintenance(),
+		Checks:      s.runChecks(ctx),
+	}
+	isReady := s.IsReady() && !health.Maintenance
+	for _, result := range health.Checks {
+		if result.Error != "" {
+			isReady = false
+		}
+	}
+	if !isReady {
+		health.Status = StatusNotReady
+	}
+
+	return health
+}
+
+func (s *State) runChecks(ctx context.Context) map[string]*models.HealthCheck {
+	if len(s.checks) == 0 {
+		return nil
+	}
+	if s.checkTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.checkTimeout)
+		defer cancel()
+	}
+
+	results := make(map[string]*models.HealthCheck, len(s.checks))
+	for _, named := range s.checks {
+		start := time.Now()
+		details, err := named.check(ctx)
+		result := &models.HealthCheck{
+			Status:     "ok",
+			Details:    details,
+			DurationMs: float64(time.Since(start)) / float64(time.Millisecond),
+		}
+		if err != nil {
+			result.Status = "failing"
+			result.Error = err.Error()
+		}
+		results[named.name] = result
+	}
+
+	return results
+}
+
+func boolToInt32(value bool) int32 {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,137 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/forum-api-back/internal/pkg/migrations"
+
+	"github.com/valyala/fasthttp"
+)
+
+func failingCheck(ctx context.Context) (interface{}, error) {
+	return nil, errors.New("dial tcp 10.0.0.5:5432: connection refused")
+}
+
+func passingCheck(ctx context.Context) (interface{}, error) {
+	return &PoolDetails{MaxOpen: 10, InUse: 3}, nil
+}
+
+func TestReadiness(t *testing.T) {
+	tests := []struct {
+		name        string
+		notReady    bool
+		maintenance bool
+		failing     bool
+		status      int
+	}{
+		{"ready", false, false, false, http.StatusOK},
+		{"shutting down", true, false, false, http.StatusServiceUnavailable},
+		{"maintenance", false, true, false, http.StatusServiceUnavailable},
+		{"failing check", false, false, true, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewState(0)
+			s.SetReady(true)
+			if tt.notReady {
+				s.SetReady(false)
+			}
+			s.SetMaintenance(tt.maintenance)
+			s.AddCheck("pool", passingCheck)
+			if tt.failing {
+				s.AddCheck("database", failingCheck)
+			}
+
+			ctx := &fasthttp.RequestCtx{}
+			s.Readiness(ctx)
+			if ctx.Response.StatusCode() != tt.status {
+				t.Errorf("status = %d, want %d", ctx.Response.StatusCode(), tt.status)
+			}
+
+			// The public body only tells the status.
+			var body map[string]interface{}
+			if err := json.Unmarshal(ctx.Response.Body(), &body); err != nil {
+				t.Fatal(err)
+			}
+			wantStatus := StatusReady
+			if tt.status != http.StatusOK {
+				wantStatus = StatusNotReady
+			}
+			if len(body) != 1 || body["status"] != wantStatus {
+				t.Errorf("body = %s, want only status %q", ctx.Response.Body(), wantStatus)
+			}
+
+			health := s.Check(context.Background())
+			if health.Status != wantStatus || health.Maintenance != tt.maintenance {
+				t.Errorf("Check() = %+v, want status %q and maintenance %v", health, wantStatus, tt.maintenance)
+			}
+			if database := health.Checks["database"]; tt.failing &&
+				(database == nil || database.Status != "failing" || !strings.Contains(database.Error, "connection refused")) {
+				t.Errorf("database check = %+v, want the failure", database)
+			}
+			if pool := health.Checks["pool"]; pool == nil || pool.Status != "ok" || pool.Details == nil {
+				t.Errorf("pool check = %+v, want its details", pool)
+			}
+		})
+	}
+}
+
+func TestLiveness(t *testing.T) {
+	s := NewState(0)
+	s.AddCheck("database", failingCheck)
+	s.SetMaintenance(true)
+
+	ctx := &fasthttp.RequestCtx{}
+	s.Liveness(ctx)
+	if ctx.Response.StatusCode() != http.StatusOK {
+		t.Errorf("status = %d, want %d", ctx.Response.StatusCode(), http.StatusOK)
+	}
+	if body := string(ctx.Response.Body()); body != `{"status":"alive","maintenance":true}` {
+		t.Errorf("body = %s", body)
+	}
+}
+
+type schemaVersion int
+
+func (v schemaVersion) Version(ctx context.Context) (int, error) {
+	if v < 0 {
+		return 0, errors.New("no version table")
+	}
+	return int(v), nil
+}
+
+func TestSchemaCheck(t *testing.T) {
+	latest := migrations.Latest()
+	tests := []struct {
+		name    string
+		version int
+		wantErr bool
+	}{
+		{"behind", latest - 1, true},
+		{"latest", latest, false},
+		{"ahead", latest + 1, false},
+		{"unreadable", -1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details, err := SchemaCheck(schemaVersion(tt.version))(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.version < 0 {
+				return
+			}
+			schema, ok := details.(*SchemaDetails)
+			if !ok || schema.Version != tt.version || schema.Latest != latest {
+				t.Errorf("details = %+v, want version %d of %d", details, tt.version, latest)
+			}
+		})
+	}
+}
@@ -1,6 +1,13 @@
+package migrations
+
+func init() {
+	register(&Migration{
+		Version: 1,
+		Name:    "init",
+		Up: `
 CREATE EXTENSION IF NOT EXISTS citext;
 
-CREATE UNLOGGED TABLE users (
+CREATE {{persistence}} TABLE users (
     nickname CITEXT NOT NULL PRIMARY KEY,
     fullname TEXT NOT NULL,
     about TEXT,
@@ -12,7 +19,7 @@ CREATE UNLOGGED TABLE users (
 CREATE UNIQUE INDEX ON users (nickname, email);
 
 
-CREATE UNLOGGED TABLE forums (
+CREATE {{persistence}} TABLE forums (
     slug CITEXT NOT NULL PRIMARY KEY,
     title TEXT NOT NULL,
     author_nickname CITEXT NOT NULL,
@@ -23,7 +30,7 @@ CREATE UNLOGGED TABLE forums (
 );
 
 
-CREATE UNLOGGED TABLE threads (
+CREATE {{persistence}} TABLE threads (
     id SERIAL NOT NULL PRIMARY KEY,
     slug CITEXT,
     title TEXT NOT NULL,
@@ -45,7 +52,7 @@ CREATE INDEX ON threads (forum_slug, date_created);
 CREATE INDEX ON threads (date_created);
 
 
-CREATE UNLOGGED TABLE authors (
+CREATE {{persistence}} TABLE authors (
     id SERIAL NOT NULL PRIMARY KEY,
     user_nickname CITEXT NOT NULL,
     forum_slug CITEXT NOT NULL,
@@ -59,7 +66,7 @@ CREATE UNLOGGED TABLE authors (
 CREATE UNIQUE INDEX ON authors (user_nickname, forum_slug);
 
 
-CREATE UNLOGGED TABLE posts (
+CREATE {{persistence}} TABLE posts (
     id SERIAL NOT NULL PRIMARY KEY,
     parent_message_id INTEGER NOT NULL,
     author_nickname CITEXT NOT NULL,
@@ -83,7 +90,7 @@ CREATE INDEX ON posts(thread_id, path_of_nesting, id);
 CREATE INDEX ON posts(thread_id, id);
 
 
-CREATE UNLOGGED TABLE votes (
+CREATE {{persistence}} TABLE votes (
     vote INTEGER NOT NULL,
     author_nickname CITEXT NOT NULL,
     thread_id INTEGER NOT NULL,
@@ -216,3 +223,27 @@ CREATE TRIGGER trigger_threads_update_user_author_status
     ON threads
     FOR EACH ROW
     EXECUTE PROCEDURE update_user_author_status_from_threads();
+`,
+		Down: `
+DROP TRIGGER trigger_threads_update_user_author_status ON threads;
+DROP FUNCTION update_user_author_status_from_threads();
+DROP TRIGGER trigger_posts_update_user_author_status ON posts;
+DROP FUNCTION update_user_author_status_from_posts();
+DROP TRIGGER trigger_update_path_of_nesting ON posts;
+DROP FUNCTION update_path_of_nesting();
+DROP TRIGGER trigger_update_threads_votes ON votes;
+DROP FUNCTION update_threads_votes();
+DROP TRIGGER trigger_inc_threads_counter ON threads;
+DROP FUNCTION inc_threads_counter();
+DROP TRIGGER trigger_inc_posts_counter ON posts;
+DROP FUNCTION inc_posts_counter();
+
+DROP TABLE votes;
+DROP TABLE posts;
+DROP TABLE authors;
+DROP TABLE threads;
+DROP TABLE forums;
+DROP TABLE users;
+`,
+	})
+}
@@ -0,0 +1,30 @@
+package migrations
+
+func init() {
+	register(&Migration{
+		Version: 2,
+		Name:    "search",
+		Up: `
+ALTER TABLE threads ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', title), 'A') ||
+        setweight(to_tsvector('english', message), 'B')
+    ) STORED;
+
+CREATE INDEX threads_search_vector_idx ON threads USING GIN (search_vector);
+
+
+ALTER TABLE posts ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('english', message)) STORED;
+
+CREATE INDEX posts_search_vector_idx ON posts USING GIN (search_vector);
+`,
+		Down: `
+DROP INDEX IF EXISTS posts_search_vector_idx;
+ALTER TABLE posts DROP COLUMN IF EXISTS search_vector;
+
+DROP INDEX IF EXISTS threads_search_vector_idx;
+ALTER TABLE threads DROP COLUMN IF EXISTS search_vector;
+`,
+	})
+}
@@ -0,0 +1,28 @@
+package migrations
+
+func init() {
+	register(&Migration{
+		Version: 3,
+		Name:    "auth",
+		Up: `
+ALTER TABLE users ADD COLUMN password_hash TEXT;
+
+
+CREATE {{persistence}} TABLE sessions (
+    id TEXT NOT NULL PRIMARY KEY,
+    user_nickname CITEXT NOT NULL,
+    date_created TIMESTAMP(3) WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP(3) WITH TIME ZONE NOT NULL,
+
+    FOREIGN KEY (user_nickname) REFERENCES users(nickname) ON DELETE CASCADE
+);
+
+CREATE INDEX ON sessions(user_nickname, expires_at);
+`,
+		Down: `
+DROP TABLE IF EXISTS sessions;
+
+ALTER TABLE users DROP COLUMN IF EXISTS password_hash;
+`,
+	})
+}
@@ -0,0 +1,22 @@
+package migrations
+
+func init() {
+	register(&Migration{
+		Version: 4,
+		Name:    "roles",
+		Up: `
+CREATE {{persistence}} TABLE moderators (
+    forum_slug CITEXT NOT NULL,
+    user_nickname CITEXT NOT NULL,
+    date_granted TIMESTAMP(3) WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (forum_slug, user_nickname),
+    FOREIGN KEY (forum_slug) REFERENCES forums(slug) ON DELETE CASCADE,
+    FOREIGN KEY (user_nickname) REFERENCES users(nickname) ON DELETE CASCADE
+);
+`,
+		Down: `
+DROP TABLE IF EXISTS moderators;
+`,
+	})
+}
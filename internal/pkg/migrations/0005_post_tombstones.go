@@ -0,0 +1,63 @@
+package migrations
+
+func init() {
+	register(&Migration{
+		Version: 5,
+		Name:    "post_tombstones",
+		Up: `
+ALTER TABLE posts ADD COLUMN is_deleted BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX ON posts(parent_message_id);
+CREATE INDEX ON posts(forum_slug, author_nickname) WHERE NOT is_deleted;
+
+
+CREATE FUNCTION remove_post() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' AND OLD.is_deleted THEN
+        RETURN NULL;
+    END IF;
+
+    UPDATE forums SET
+    count_posts = count_posts - 1
+    WHERE slug = OLD.forum_slug;
+
+    DELETE FROM authors
+    WHERE user_nickname = OLD.author_nickname AND forum_slug = OLD.forum_slug
+        AND NOT EXISTS (
+            SELECT 1 FROM threads
+            WHERE author_nickname = OLD.author_nickname AND forum_slug = OLD.forum_slug
+        )
+        AND NOT EXISTS (
+            SELECT 1 FROM posts
+            WHERE author_nickname = OLD.author_nickname AND forum_slug = OLD.forum_slug
+                AND NOT is_deleted
+        );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_remove_deleted_post
+    AFTER DELETE
+    ON posts
+    FOR EACH ROW
+    EXECUTE PROCEDURE remove_post();
+
+CREATE TRIGGER trigger_remove_tombstoned_post
+    AFTER UPDATE OF is_deleted
+    ON posts
+    FOR EACH ROW
+    WHEN (NEW.is_deleted AND NOT OLD.is_deleted)
+    EXECUTE PROCEDURE remove_post();
+`,
+		Down: `
+DROP TRIGGER trigger_remove_tombstoned_post ON posts;
+DROP TRIGGER trigger_remove_deleted_post ON posts;
+DROP FUNCTION remove_post();
+
+DROP INDEX IF EXISTS posts_forum_slug_author_nickname_idx;
+DROP INDEX IF EXISTS posts_parent_message_id_idx;
+
+ALTER TABLE posts DROP COLUMN IF EXISTS is_deleted;
+`,
+	})
+}
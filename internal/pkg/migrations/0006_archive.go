@@ -0,0 +1,94 @@
+package migrations
+
+func init() {
+	register(&Migration{
+		Version: 6,
+		Name:    "archive",
+		Up: `
+ALTER TABLE forums ADD COLUMN is_archived BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE threads ADD COLUMN is_archived BOOLEAN NOT NULL DEFAULT FALSE;
+
+
+ALTER TABLE threads
+    DROP CONSTRAINT threads_forum_slug_fkey,
+    ADD CONSTRAINT threads_forum_slug_fkey
+        FOREIGN KEY (forum_slug) REFERENCES forums(slug) ON DELETE CASCADE;
+
+ALTER TABLE authors
+    DROP CONSTRAINT authors_forum_slug_fkey,
+    ADD CONSTRAINT authors_forum_slug_fkey
+        FOREIGN KEY (forum_slug) REFERENCES forums(slug) ON DELETE CASCADE;
+
+ALTER TABLE posts
+    DROP CONSTRAINT posts_forum_slug_fkey,
+    ADD CONSTRAINT posts_forum_slug_fkey
+        FOREIGN KEY (forum_slug) REFERENCES forums(slug) ON DELETE CASCADE,
+    DROP CONSTRAINT posts_thread_id_fkey,
+    ADD CONSTRAINT posts_thread_id_fkey
+        FOREIGN KEY (thread_id) REFERENCES threads(id) ON DELETE CASCADE;
+
+ALTER TABLE votes
+    DROP CONSTRAINT votes_thread_id_fkey,
+    ADD CONSTRAINT votes_thread_id_fkey
+        FOREIGN KEY (thread_id) REFERENCES threads(id) ON DELETE CASCADE;
+
+
+CREATE FUNCTION remove_thread() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE forums SET
+    count_threads = count_threads - 1
+    WHERE slug = OLD.forum_slug;
+
+    DELETE FROM authors
+    WHERE user_nickname = OLD.author_nickname AND forum_slug = OLD.forum_slug
+        AND NOT EXISTS (
+            SELECT 1 FROM threads
+            WHERE author_nickname = OLD.author_nickname AND forum_slug = OLD.forum_slug
+        )
+        AND NOT EXISTS (
+            SELECT 1 FROM posts
+            WHERE author_nickname = OLD.author_nickname AND forum_slug = OLD.forum_slug
+                AND NOT is_deleted
+        );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_remove_thread
+    AFTER DELETE
+    ON threads
+    FOR EACH ROW
+    EXECUTE PROCEDURE remove_thread();
+`,
+		Down: `
+DROP TRIGGER trigger_remove_thread ON threads;
+DROP FUNCTION remove_thread();
+
+ALTER TABLE votes
+    DROP CONSTRAINT votes_thread_id_fkey,
+    ADD CONSTRAINT votes_thread_id_fkey
+        FOREIGN KEY (thread_id) REFERENCES threads(id);
+
+ALTER TABLE posts
+    DROP CONSTRAINT posts_thread_id_fkey,
+    ADD CONSTRAINT posts_thread_id_fkey
+        FOREIGN KEY (thread_id) REFERENCES threads(id),
+    DROP CONSTRAINT posts_forum_slug_fkey,
+    ADD CONSTRAINT posts_forum_slug_fkey
+        FOREIGN KEY (forum_slug) REFERENCES forums(slug);
+
+ALTER TABLE authors
+    DROP CONSTRAINT authors_forum_slug_fkey,
+    ADD CONSTRAINT authors_forum_slug_fkey
+        FOREIGN KEY (forum_slug) REFERENCES forums(slug);
+
+ALTER TABLE threads
+    DROP CONSTRAINT threads_forum_slug_fkey,
+    ADD CONSTRAINT threads_forum_slug_fkey
+        FOREIGN KEY (forum_slug) REFERENCES forums(slug);
+
+ALTER TABLE threads DROP COLUMN IF EXISTS is_archived;
+ALTER TABLE forums DROP COLUMN IF EXISTS is_archived;
+`,
+	})
+}
@@ -0,0 +1,24 @@
+package migrations
+
+func init() {
+	register(&Migration{
+		Version: 7,
+		Name:    "post_revisions",
+		Up: `
+CREATE {{persistence}} TABLE post_revisions (
+    post_id INTEGER NOT NULL,
+    revision INTEGER NOT NULL,
+    editor_nickname CITEXT NOT NULL,
+    message TEXT NOT NULL,
+    date_edited TIMESTAMP(3) WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (post_id, revision),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (editor_nickname) REFERENCES users(nickname)
+);
+`,
+		Down: `
+DROP TABLE IF EXISTS post_revisions;
+`,
+	})
+}
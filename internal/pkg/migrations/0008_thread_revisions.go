@@ -0,0 +1,29 @@
+package migrations
+
+func init() {
+	register(&Migration{
+		Version: 8,
+		Name:    "thread_revisions",
+		Up: `
+ALTER TABLE threads ADD COLUMN date_edited TIMESTAMP(3) WITH TIME ZONE;
+
+CREATE {{persistence}} TABLE thread_revisions (
+    thread_id INTEGER NOT NULL,
+    revision INTEGER NOT NULL,
+    editor_nickname CITEXT NOT NULL,
+    title TEXT NOT NULL,
+    message TEXT NOT NULL,
+    date_edited TIMESTAMP(3) WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (thread_id, revision),
+    FOREIGN KEY (thread_id) REFERENCES threads(id) ON DELETE CASCADE,
+    FOREIGN KEY (editor_nickname) REFERENCES users(nickname)
+);
+`,
+		Down: `
+DROP TABLE IF EXISTS thread_revisions;
+
+ALTER TABLE threads DROP COLUMN IF EXISTS date_edited;
+`,
+	})
+}
@@ -0,0 +1,46 @@
+package migrations
+
+func init() {
+	register(&Migration{
+		Version: 9,
+		Name:    "webhooks",
+		Up: `
+CREATE {{persistence}} TABLE webhooks (
+    id SERIAL NOT NULL PRIMARY KEY,
+    forum_slug CITEXT NOT NULL,
+    url TEXT NOT NULL,
+    events TEXT ARRAY DEFAULT '{}' NOT NULL,
+    secret TEXT NOT NULL,
+    date_created TIMESTAMP(3) WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    FOREIGN KEY (forum_slug) REFERENCES forums(slug) ON DELETE CASCADE
+);
+
+CREATE INDEX ON webhooks(forum_slug);
+
+
+CREATE {{persistence}} TABLE webhook_deliveries (
+    id SERIAL NOT NULL PRIMARY KEY,
+    webhook_id INTEGER NOT NULL,
+    event TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_status INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt TIMESTAMP(3) WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    date_created TIMESTAMP(3) WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    date_delivered TIMESTAMP(3) WITH TIME ZONE,
+
+    FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX ON webhook_deliveries(webhook_id, id);
+CREATE INDEX ON webhook_deliveries(next_attempt) WHERE status = 'pending';
+`,
+		Down: `
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+`,
+	})
+}
@@ -0,0 +1,32 @@
+package migrations
+
+func init() {
+	register(&Migration{
+		Version: 10,
+		Name:    "notifications",
+		Up: `
+CREATE {{persistence}} TABLE notifications (
+    id SERIAL NOT NULL PRIMARY KEY,
+    user_nickname CITEXT NOT NULL,
+    type TEXT NOT NULL,
+    actor_nickname CITEXT NOT NULL,
+    forum_slug CITEXT NOT NULL,
+    thread_id INTEGER NOT NULL,
+    post_id INTEGER NOT NULL DEFAULT 0,
+    voice INTEGER NOT NULL DEFAULT 0,
+    is_read BOOLEAN NOT NULL DEFAULT FALSE,
+    date_created TIMESTAMP(3) WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    FOREIGN KEY (user_nickname) REFERENCES users(nickname) ON DELETE CASCADE,
+    FOREIGN KEY (thread_id) REFERENCES threads(id) ON DELETE CASCADE
+);
+
+CREATE INDEX ON notifications (user_nickname, id);
+CREATE INDEX ON notifications (user_nickname, id) WHERE NOT is_read;
+CREATE INDEX ON notifications (thread_id);
+`,
+		Down: `
+DROP TABLE IF EXISTS notifications;
+`,
+	})
+}
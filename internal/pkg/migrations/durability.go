@@ -0,0 +1,164 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/forum-api-back/pkg/logger"
+
+	"github.com/lib/pq"
+)
+
+type Durability string
+
+const (
+	// Unlogged tables skip the write-ahead log: they are faster but are
+	// truncated after a crash of the PostgreSQL server.
+	Unlogged Durability = "unlogged"
+	Logged   Durability = "logged"
+)
+
+const persistencePlaceholder = "{{persistence}}"
+
+// render substitutes the table persistence into migration SQL, so tables
+// created by migrations follow the selected durability.
+func (d Durability) render(query string) string {
+	persistence := ""
+	if d == Unlogged {
+		persistence = "UNLOGGED"
+	}
+	return strings.Replace(query, persistencePlaceholder, persistence, -1)
+}
+
+// schemaDurability returns the durability new tables are created with.
+// Tables of another durability are reported but kept.
+func schemaDurability(ctx context.Context, conn *sql.Conn, configured Durability) (Durability, error) {
+	tables, err := selectSchemaTables(ctx, conn)
+	if err != nil {
+		return "", err
+	}
+
+	durability, mismatched, err := newTableDurability(tables, configured)
+	if err == nil && len(mismatched) != 0 {
+		logger.FromContext(ctx).Warn("tables don't have the configured durability, run migrate durability to convert them",
+			"durability", configured, "tables", strings.Join(mismatched, ","))
+	}
+	return durability, err
+}
+
+// newTableDurability returns the durability new tables are created with
+// and the tables not having the configured one. It is the configured one
+// unless every existing table has the other, in which case mixing them
+// would break foreign keys between permanent and unlogged tables. For the
+// same reason a schema already mixing both is refused.
+func newTableDurability(tables map[string]*schemaTable, configured Durability) (Durability, []string, error) {
+	mismatched := make([]string, 0)
+	for _, table := range tables {
+		if table.logged != (configured == Logged) {
+			mismatched = append(mismatched, table.name)
+		}
+	}
+	sort.Strings(mismatched)
+
+	switch {
+	case len(mismatched) == 0:
+		return configured, mismatched, nil
+	case len(mismatched) < len(tables):
+		return "", mismatched, fmt.Errorf("tables %s aren't %s while the others are, run migrate durability before migrating",
+			strings.Join(mismatched, ", "), configured)
+	case configured == Logged:
+		return Unlogged, mismatched, nil
+	default:
+		return Logged, mismatched, nil
+	}
+}
+
+type schemaTable struct {
+	name       string
+	logged     bool
+	references []string
+}
+
+// setDurability converts every table of the schema to the given
+// durability. Permanent tables may only reference permanent ones, so
+// referenced tables are made logged first and unlogged last.
+func setDurability(ctx context.Context, conn *sql.Conn, durability Durability) error {
+	tables, err := selectSchemaTables(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	order := make([]*schemaTable, 0, len(tables))
+	visited := make(map[string]bool, len(tables))
+	var visit func(table *schemaTable)
+	visit = func(table *schemaTable) {
+		if visited[table.name] {
+			return
+		}
+		visited[table.name] = true
+		for _, name := range table.references {
+			if referenced, ok := tables[name]; ok {
+				visit(referenced)
+			}
+		}
+		order = append(order, table)
+	}
+	for _, table := range tables {
+		visit(table)
+	}
+
+	logged := durability == Logged
+	for i := range order {
+		table := order[i]
+		if !logged {
+			table = order[len(order)-1-i]
+		}
+		if table.logged == logged {
+			continue
+		}
+
+		_, err := conn.ExecContext(ctx, fmt.Sprintf(
+			"ALTER TABLE %s SET %s",
+			pq.QuoteIdentifier(table.name),
+			strings.ToUpper(string(durability)),
+		))
+		if err != nil {
+			return fmt.Errorf("can't make %s %s: %v", table.name, durability, err)
+		}
+	}
+
+	return nil
+}
+
+func selectSchemaTables(ctx context.Context, conn *sql.Conn) (map[string]*schemaTable, error) {
+	rows, err := conn.QueryContext(ctx,
+		"SELECT c.relname, c.relpersistence = 'p', "+
+			"	ARRAY( "+
+			"		SELECT r.relname "+
+			"		FROM pg_constraint f "+
+			"		JOIN pg_class r ON (r.oid = f.confrelid) "+
+			"		WHERE f.conrelid = c.oid AND f.contype = 'f' AND f.confrelid <> c.oid "+
+			"	) "+
+			"FROM pg_class c "+
+			"JOIN pg_namespace n ON (n.oid = c.relnamespace) "+
+			"WHERE n.nspname = current_schema() AND c.relkind = 'r' AND c.relname <> 'schema_version'",
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := make(map[string]*schemaTable)
+	for rows.Next() {
+		table := &schemaTable{}
+		if err := rows.Scan(&table.name, &table.logged, pq.Array(&table.references)); err != nil {
+			return nil, err
+		}
+		tables[table.name] = table
+	}
+
+	return tables, rows.Err()
+}
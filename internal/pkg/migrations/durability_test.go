@@ -0,0 +1,58 @@
+package migrations
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewTableDurability(t *testing.T) {
+	tests := []struct {
+		name       string
+		logged     map[string]bool
+		configured Durability
+		want       Durability
+		mismatched []string
+		wantErr    bool
+	}{
+		{"empty schema", map[string]bool{}, Logged, Logged, nil, false},
+		{"all configured", map[string]bool{"users": true, "forums": true}, Logged, Logged, nil, false},
+		{"all other", map[string]bool{"users": false, "forums": false}, Logged, Unlogged, []string{"forums", "users"}, false},
+		{"all other logged", map[string]bool{"users": true}, Unlogged, Logged, []string{"users"}, false},
+		{"mixed", map[string]bool{"users": true, "forums": false, "threads": false}, Logged, "", []string{"forums", "threads"}, true},
+		{"mixed unlogged", map[string]bool{"users": true, "forums": false}, Unlogged, "", []string{"users"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables := make(map[string]*schemaTable, len(tt.logged))
+			for name, logged := range tt.logged {
+				tables[name] = &schemaTable{name: name, logged: logged}
+			}
+
+			durability, mismatched, err := newTableDurability(tables, tt.configured)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "migrate durability") {
+				t.Errorf("error %q doesn't tell to run migrate durability", err)
+			}
+			if durability != tt.want {
+				t.Errorf("durability = %q, want %q", durability, tt.want)
+			}
+			if strings.Join(mismatched, ",") != strings.Join(tt.mismatched, ",") {
+				t.Errorf("mismatched = %v, want %v", mismatched, tt.mismatched)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	const query = "CREATE {{persistence}} TABLE users ()"
+
+	if got := Unlogged.render(query); got != "CREATE UNLOGGED TABLE users ()" {
+		t.Errorf("Unlogged.render() = %q", got)
+	}
+	if got := Logged.render(query); got != "CREATE  TABLE users ()" {
+		t.Errorf("Logged.render() = %q", got)
+	}
+}
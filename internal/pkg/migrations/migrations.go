@@ -0,0 +1,363 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// lockKey identifies the advisory lock held by a running migrator, so
+// concurrent runners against the same database wait for each other.
+const lockKey int64 = 7239184215
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type AppliedMigration struct {
+	Version   int
+	Name      string
+	AppliedAt time.Time
+}
+
+var registered []*Migration
+
+func register(migration *Migration) {
+	registered = append(registered, migration)
+	sort.Slice(registered, func(i, j int) bool {
+		return registered[i].Version < registered[j].Version
+	})
+}
+
+// All returns the migrations compiled into the binary ordered by version.
+func All() []*Migration {
+	return registered
+}
+
+// Latest returns the version the schema has after applying every migration.
+func Latest() int {
+	if len(registered) == 0 {
+		return 0
+	}
+	return registered[len(registered)-1].Version
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []*Migration
+	durability Durability
+}
+
+func NewMigrator(db *sql.DB, durability Durability) *Migrator {
+	return &Migrator{
+		db:         db,
+		migrations: All(),
+		durability: durability,
+	}
+}
+
+// Up applies pending migrations up to the target version. A zero target
+// applies all of them, other targets have to be known versions. New tables
+// are created with the durability of the migrator, or with the one of the
+// existing tables when they all differ from it: only SetDurability converts
+// tables, and it has to before migrating a schema mixing both.
+func (m *Migrator) Up(ctx context.Context, target int) ([]*Migration, error) {
+	if target == 0 && len(m.migrations) > 0 {
+		target = m.migrations[len(m.migrations)-1].Version
+	}
+	if !isKnown(m.migrations, target) {
+		return nil, fmt.Errorf("unknown migration version %d", target)
+	}
+
+	applied := make([]*Migration, 0)
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		pending, err := pendingMigrations(m.migrations, current, target)
+		if err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		durability, err := schemaDurability(ctx, conn, m.durability)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range pending {
+			if err := m.apply(ctx, conn, migration, durability); err != nil {
+				return err
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// pendingMigrations returns the migrations after the current version up to
+// the target one, which has to be a known version.
+func pendingMigrations(migrations []*Migration, current, target int) ([]*Migration, error) {
+	if !isKnown(migrations, target) {
+		return nil, fmt.Errorf("unknown migration version %d", target)
+	}
+
+	pending := make([]*Migration, 0)
+	for _, migration := range migrations {
+		if migration.Version > current && migration.Version <= target {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// revertedMigrations returns the given number of migrations up to the
+// current version, newest first. A schema migrated by a newer build isn't
+// reverted, as its latest migrations are unknown to this one.
+func revertedMigrations(migrations []*Migration, current, steps int) ([]*Migration, error) {
+	if !isKnown(migrations, current) {
+		return nil, fmt.Errorf("schema version %d is unknown to this build", current)
+	}
+
+	reverted := make([]*Migration, 0, steps)
+	for i := len(migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+		if migrations[i].Version <= current {
+			reverted = append(reverted, migrations[i])
+		}
+	}
+	return reverted, nil
+}
+
+// isKnown tells whether version is the one of a migration or zero, the
+// version of an empty schema.
+func isKnown(migrations []*Migration, version int) bool {
+	if version == 0 {
+		return true
+	}
+	for _, migration := range migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// Down reverts the given number of the most recently applied migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]*Migration, error) {
+	reverted := make([]*Migration, 0)
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		planned, err := revertedMigrations(m.migrations, current, steps)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range planned {
+			if err := m.revert(ctx, conn, migration); err != nil {
+				return err
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Force marks the schema as migrated to the given version without running
+// any migration. It is meant for databases created before migrations
+// existed or left behind by a failed manual fix.
+func (m *Migrator) Force(ctx context.Context, version int) error {
+	if !isKnown(m.migrations, version) {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM schema_version"); err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			if _, err := tx.ExecContext(ctx,
+				"INSERT INTO schema_version (version, name) VALUES ($1, $2)",
+				migration.Version,
+				migration.Name,
+			); err != nil {
+				return err
+			}
+		}
+
+		return tx.Commit()
+	})
+}
+
+// SetDurability converts the existing tables without applying migrations.
+func (m *Migrator) SetDurability(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		return setDurability(ctx, conn, m.durability)
+	})
+}
+
+// Version returns the version of the most recently applied migration.
+func (m *Migrator) Version(ctx context.Context) (int, error) {
+	var version int
+	err := m.withConn(ctx, func(conn *sql.Conn) error {
+		exists, err := versionTableExists(ctx, conn)
+		if err != nil || !exists {
+			return err
+		}
+		version, err = currentVersion(ctx, conn)
+		return err
+	})
+
+	return version, err
+}
+
+// Applied lists migrations recorded in the schema_version table.
+func (m *Migrator) Applied(ctx context.Context) ([]*AppliedMigration, error) {
+	applied := make([]*AppliedMigration, 0)
+	err := m.withConn(ctx, func(conn *sql.Conn) error {
+		exists, err := versionTableExists(ctx, conn)
+		if err != nil || !exists {
+			return err
+		}
+
+		rows, err := conn.QueryContext(ctx,
+			"SELECT version, name, applied_at "+
+				"FROM schema_version "+
+				"ORDER BY version",
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			migration := &AppliedMigration{}
+			if err := rows.Scan(&migration.Version, &migration.Name, &migration.AppliedAt); err != nil {
+				return err
+			}
+			applied = append(applied, migration)
+		}
+		return rows.Err()
+	})
+
+	return applied, err
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration *Migration, durability Durability) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, durability.render(migration.Up)); err != nil {
+		return fmt.Errorf("migration %d_%s: %v", migration.Version, migration.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO schema_version (version, name) VALUES ($1, $2)",
+		migration.Version,
+		migration.Name,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m *Migrator) revert(ctx context.Context, conn *sql.Conn, migration *Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+		return fmt.Errorf("migration %d_%s: %v", migration.Version, migration.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		"DELETE FROM schema_version WHERE version = $1",
+		migration.Version,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m *Migrator) withConn(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return fn(conn)
+}
+
+// withLock runs fn holding the migrations advisory lock. The lock belongs
+// to the session, so everything runs on one dedicated connection.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	return m.withConn(ctx, func(conn *sql.Conn) error {
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+			return err
+		}
+		defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey)
+
+		if err := createVersionTable(ctx, conn); err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+func createVersionTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx,
+		"CREATE TABLE IF NOT EXISTS schema_version ( "+
+			"	version INTEGER NOT NULL PRIMARY KEY, "+
+			"	name TEXT NOT NULL, "+
+			"	applied_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL "+
+			")",
+	)
+
+	return err
+}
+
+func versionTableExists(ctx context.Context, conn *sql.Conn) (bool, error) {
+	var exists bool
+	err := conn.QueryRowContext(ctx,
+		"SELECT to_regclass('schema_version') IS NOT NULL",
+	).Scan(&exists)
+
+	return exists, err
+}
+
+func currentVersion(ctx context.Context, conn *sql.Conn) (int, error) {
+	var version int
+	err := conn.QueryRowContext(ctx,
+		"SELECT COALESCE(MAX(version), 0) FROM schema_version",
+	).Scan(&version)
+
+	return version, err
+}
@@ -0,0 +1,115 @@
+package migrations
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testMigrations has versions 1, 2, 3 and 5.
+var testMigrations = []*Migration{
+	{Version: 1, Name: "init"},
+	{Version: 2, Name: "search"},
+	{Version: 3, Name: "auth"},
+	{Version: 5, Name: "roles"},
+}
+
+func versions(migrations []*Migration) []int {
+	selected := make([]int, 0, len(migrations))
+	for _, migration := range migrations {
+		selected = append(selected, migration.Version)
+	}
+	return selected
+}
+
+func TestPendingMigrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		current int
+		target  int
+		want    []int
+		wantErr bool
+	}{
+		{"empty schema", 0, 5, []int{1, 2, 3, 5}, false},
+		{"up to a target", 0, 2, []int{1, 2}, false},
+		{"from the current version", 2, 5, []int{3, 5}, false},
+		{"up to date", 5, 5, []int{}, false},
+		{"target behind", 3, 2, []int{}, false},
+		{"schema of a newer build", 7, 5, []int{}, false},
+		{"unknown target", 0, 4, nil, true},
+		{"target after the latest", 0, 6, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pending, err := pendingMigrations(testMigrations, tt.current, tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(versions(pending), tt.want) {
+				t.Errorf("pending = %v, want %v", versions(pending), tt.want)
+			}
+		})
+	}
+}
+
+func TestRevertedMigrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		current int
+		steps   int
+		want    []int
+		wantErr bool
+	}{
+		{"latest", 5, 1, []int{5}, false},
+		{"several", 5, 3, []int{5, 3, 2}, false},
+		{"from the current version", 3, 2, []int{3, 2}, false},
+		{"more steps than applied", 2, 5, []int{2, 1}, false},
+		{"empty schema", 0, 1, []int{}, false},
+		{"schema of a newer build", 7, 1, nil, true},
+		{"unknown version", 4, 1, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reverted, err := revertedMigrations(testMigrations, tt.current, tt.steps)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(versions(reverted), tt.want) {
+				t.Errorf("reverted = %v, want %v", versions(reverted), tt.want)
+			}
+		})
+	}
+}
+
+func TestMigratorRejectsUnknownVersions(t *testing.T) {
+	// Versions are checked before the database is used.
+	m := &Migrator{migrations: testMigrations}
+
+	if _, err := m.Up(context.Background(), 4); err == nil || !strings.Contains(err.Error(), "unknown") {
+		t.Errorf("Up(4) error = %v, want an unknown version", err)
+	}
+	if err := m.Force(context.Background(), 6); err == nil || !strings.Contains(err.Error(), "unknown") {
+		t.Errorf("Force(6) error = %v, want an unknown version", err)
+	}
+}
+
+func TestRegisteredMigrations(t *testing.T) {
+	all := All()
+	if len(all) == 0 {
+		t.Fatal("no migrations are registered")
+	}
+	for i, migration := range all {
+		if migration.Version != i+1 {
+			t.Errorf("migration %d_%s is number %d, versions must follow each other", migration.Version, migration.Name, i+1)
+		}
+		if migration.Name == "" || strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			t.Errorf("migration %d lacks a name, an up or a down script", migration.Version)
+		}
+	}
+	if Latest() != all[len(all)-1].Version {
+		t.Errorf("Latest() = %d, want %d", Latest(), all[len(all)-1].Version)
+	}
+}
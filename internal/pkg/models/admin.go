@@ -5,4 +5,28 @@ type BaseDetails struct {
 	Forum  uint64 `json:"forum"`
 	Thread uint64 `json:"thread"`
 	Post   uint64 `json:"post"`
+	// Durability is the storage mode of the tables: logged, unlogged,
+	// mixed while a conversion is incomplete, or memory.
+	Durability string `json:"durability,omitempty"`
+}
+
+// Health is the readiness of an instance with the results of the checks
+// it depends on.
+type Health struct {
+	Status      string                  `json:"status"`
+	Maintenance bool                    `json:"maintenance"`
+	Checks      map[string]*HealthCheck `json:"checks,omitempty"`
+}
+
+type HealthCheck struct {
+	Status     string      `json:"status"`
+	Error      string      `json:"error,omitempty"`
+	Details    interface{} `json:"details,omitempty"`
+	DurationMs float64     `json:"durationMs"`
+}
+
+// Maintenance is the maintenance mode of an instance, in which it reports
+// it isn't ready so load balancers send traffic elsewhere.
+type Maintenance struct {
+	Enabled bool `json:"enabled"`
 }
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type Credentials struct {
+	NickName string `json:"nickname"`
+	Password string `json:"password"`
+}
+
+type Session struct {
+	Id          string    `json:"-"`
+	Token       string    `json:"token"`
+	NickName    string    `json:"nickname"`
+	DateCreated time.Time `json:"created"`
+	ExpiresAt   time.Time `json:"expires"`
+}
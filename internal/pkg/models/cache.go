@@ -0,0 +1,20 @@
+package models
+
+// CacheStats counts how the cache of hot reads is doing, in total and by
+// namespace ("forum", "thread", "user").
+type CacheStats struct {
+	Enabled    bool                      `json:"enabled"`
+	Capacity   int                       `json:"capacity"`
+	Entries    int                       `json:"entries"`
+	Hits       uint64                    `json:"hits"`
+	Misses     uint64                    `json:"misses"`
+	HitRatio   float64                   `json:"hitRatio"`
+	Evictions  uint64                    `json:"evictions"`
+	Expired    uint64                    `json:"expired"`
+	Namespaces map[string]*CacheCounters `json:"namespaces,omitempty"`
+}
+
+type CacheCounters struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
@@ -0,0 +1,17 @@
+package models
+
+const (
+	EventPostCreated  = "post_created"
+	EventPostEdited   = "post_edited"
+	EventThreadEdited = "thread_edited"
+	EventThreadVoted  = "thread_voted"
+)
+
+// Event is a change pushed to the subscribers of a thread and its forum.
+// Data is the post or the thread as it is after the change.
+type Event struct {
+	Type   string      `json:"type"`
+	Forum  string      `json:"forum"`
+	Thread uint64      `json:"thread"`
+	Data   interface{} `json:"data"`
+}
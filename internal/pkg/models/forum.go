@@ -6,6 +6,9 @@ type Forum struct {
 	Slug           string `json:"slug"`
 	Posts          uint64 `json:"posts"`
 	Threads        uint64 `json:"threads"`
+	// IsArchived forums take no new threads and their threads are read-only
+	// whatever their own IsArchived.
+	IsArchived bool `json:"isArchived,omitempty"`
 }
 
 type ForumCreate struct {
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+const (
+	NotificationReply   = "reply"
+	NotificationMention = "mention"
+	NotificationVote    = "vote"
+)
+
+// Notification tells a user that actor replied to one of their posts,
+// mentioned them as @nickname or voted for one of their threads. Post is
+// the reply or the post with the mention, unless the mention is in the
+// message of the thread; Voice is the vote.
+type Notification struct {
+	Id          uint64    `json:"id"`
+	Type        string    `json:"type"`
+	Actor       string    `json:"actor"`
+	Forum       string    `json:"forum"`
+	Thread      uint64    `json:"thread"`
+	Post        uint64    `json:"post,omitempty"`
+	Voice       int       `json:"voice,omitempty"`
+	IsRead      bool      `json:"isRead"`
+	DateCreated time.Time `json:"created"`
+	// Recipient is the user notified.
+	Recipient string `json:"-"`
+}
+
+// MaxNotificationsLimit bounds the pages of notifications.
+const MaxNotificationsLimit = 100
+
+// NotificationPaginator lists notifications from the newest one. Since
+// continues the list after the notification with that id.
+type NotificationPaginator struct {
+	Limit  uint64 `json:"limit"`
+	Since  uint64 `json:"since"`
+	Unread bool   `json:"unread"`
+}
+
+// NotificationsMark selects the notifications to mark, all of them when
+// Ids is empty.
+type NotificationsMark struct {
+	Ids []uint64 `json:"ids"`
+}
+
+type NotificationsStatus struct {
+	Unread uint64 `json:"unread"`
+}
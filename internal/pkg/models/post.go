@@ -15,6 +15,31 @@ type Post struct {
 	Forum       string    `json:"forum"`
 	Thread      uint64    `json:"thread"`
 	DateCreated time.Time `json:"created"`
+	// IsDeleted marks the tombstone of a deleted post that still has
+	// replies. Its message is gone but it keeps its place in the tree.
+	IsDeleted bool `json:"isDeleted,omitempty"`
+}
+
+// PostRevision is a version of the message of a post. The first revision
+// is the message the post was created with, every edit adds the next one.
+type PostRevision struct {
+	Revision   uint64    `json:"revision"`
+	Editor     string    `json:"editor"`
+	Message    string    `json:"message"`
+	DateEdited time.Time `json:"edited"`
+}
+
+type PostRevisionDiff struct {
+	From  uint64      `json:"from"`
+	To    uint64      `json:"to"`
+	Lines []*DiffLine `json:"lines"`
+}
+
+// DiffLine is a line of a message kept ("equal"), added ("insert") or
+// removed ("delete") between two revisions.
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
 }
 
 type PostCreate struct {
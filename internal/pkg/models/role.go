@@ -0,0 +1,28 @@
+package models
+
+import "encoding/json"
+
+// Role is what a user may do in a forum. Every role is allowed whatever
+// the roles below it are.
+type Role int
+
+const (
+	RoleGuest Role = iota
+	RoleMember
+	RoleModerator
+	RoleOwner
+	RoleAdmin
+)
+
+var roleNames = []string{"guest", "member", "moderator", "owner", "admin"}
+
+func (r Role) String() string {
+	if r < 0 || int(r) >= len(roleNames) {
+		return "unknown"
+	}
+	return roleNames[r]
+}
+
+func (r Role) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+const (
+	SearchTypePost   = "post"
+	SearchTypeThread = "thread"
+)
+
+// MaxSearchLimit and MaxSearchOffset bound the pages of hits: every hit
+// before the end of a page is ranked to find it.
+const (
+	MaxSearchLimit  = 100
+	MaxSearchOffset = 10000
+)
+
+type SearchQuery struct {
+	Query    string    `json:"query"`
+	Type     string    `json:"type"`
+	Forum    string    `json:"forum"`
+	Thread   string    `json:"thread"`
+	ThreadId uint64    `json:"-"`
+	Author   string    `json:"user"`
+	Since    time.Time `json:"since"`
+	Until    time.Time `json:"until"`
+	Limit    uint64    `json:"limit"`
+	Offset   uint64    `json:"offset"`
+}
+
+type SearchHit struct {
+	Type    string  `json:"type"`
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+	Post    *Post   `json:"post,omitempty"`
+	Thread  *Thread `json:"thread,omitempty"`
+}
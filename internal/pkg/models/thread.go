@@ -19,6 +19,23 @@ type Thread struct {
 	Votes          int       `json:"votes"`
 	Slug           string    `json:"slug"`
 	DateCreated    time.Time `json:"created"`
+	// IsArchived threads take no new posts or votes. It is the archival of
+	// the thread itself: threads of an archived forum are read-only too.
+	IsArchived bool `json:"isArchived,omitempty"`
+	// IsEdited and EditedAt tell whether and when the title or the
+	// message of a thread was last changed.
+	IsEdited bool       `json:"isEdited,omitempty"`
+	EditedAt *time.Time `json:"editedAt,omitempty"`
+}
+
+// ThreadRevision is a version of the title and message of a thread. The
+// first revision is the one the thread was created with.
+type ThreadRevision struct {
+	Revision   uint64    `json:"revision"`
+	Editor     string    `json:"editor"`
+	Title      string    `json:"title"`
+	Message    string    `json:"message"`
+	DateEdited time.Time `json:"edited"`
 }
 
 type ThreadUpdate struct {
@@ -5,6 +5,10 @@ type User struct {
 	FullName string `json:"fullname"`
 	About    string `json:"about"`
 	Email    string `json:"email"`
+	// Password is only read from requests; repositories store and return
+	// the bcrypt hash of it.
+	Password     string `json:"password,omitempty"`
+	PasswordHash string `json:"-"`
 }
 
 type UserUpdate struct {
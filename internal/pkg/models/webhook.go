@@ -0,0 +1,55 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// Webhook is a URL notified about the activity of a forum. Events lists
+// the names of the events it gets, all of them when it is empty.
+type Webhook struct {
+	Id          uint64    `json:"id"`
+	Forum       string    `json:"forum"`
+	URL         string    `json:"url"`
+	Events      []string  `json:"events"`
+	DateCreated time.Time `json:"created"`
+	// Secret signs the payloads. It is only shown when the webhook is
+	// created.
+	Secret string `json:"secret,omitempty"`
+}
+
+type WebhookCreate struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret"`
+}
+
+// WebhookDelivery is an event queued for a webhook with the outcome of
+// its last attempt.
+type WebhookDelivery struct {
+	Id             uint64          `json:"id"`
+	Webhook        uint64          `json:"webhook"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"responseStatus,omitempty"`
+	LastError      string          `json:"lastError,omitempty"`
+	NextAttempt    time.Time       `json:"nextAttempt"`
+	DateCreated    time.Time       `json:"created"`
+	DateDelivered  *time.Time      `json:"delivered,omitempty"`
+}
+
+// MaxDeliveriesLimit bounds the pages of deliveries.
+const MaxDeliveriesLimit = 100
+
+type DeliveryPaginator struct {
+	Limit uint64 `json:"limit"`
+	Since uint64 `json:"since"`
+}
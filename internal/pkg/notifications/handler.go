@@ -0,0 +1,10 @@
+package notifications
+
+import "github.com/valyala/fasthttp"
+
+type Handler interface {
+	GetNotifications(ctx *fasthttp.RequestCtx)
+	GetNotificationsStatus(ctx *fasthttp.RequestCtx)
+	MarkNotificationsRead(ctx *fasthttp.RequestCtx)
+	MarkNotificationsUnread(ctx *fasthttp.RequestCtx)
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/notifications"
+	"github.com/forum-api-back/pkg/errors"
+	"github.com/forum-api-back/pkg/tools/http_utils"
+
+	"github.com/valyala/fasthttp"
+)
+
+type NotificationsHandler struct {
+	NotificationsUCase notifications.UseCase
+}
+
+func NewHandler(notificationsUCase notifications.UseCase) notifications.Handler {
+	return &NotificationsHandler{
+		NotificationsUCase: notificationsUCase,
+	}
+}
+
+// GetNotifications lists the notifications of a user from the newest one.
+// The since query argument continues the list after the notification with
+// that id, unread=true leaves out the read ones.
+func (h *NotificationsHandler) GetNotifications(ctx *fasthttp.RequestCtx) {
+	nickname := ctx.UserValue("nickname").(string)
+
+	paginator := &models.NotificationPaginator{Limit: models.MaxNotificationsLimit}
+	details := make([]*errors.FieldError, 0)
+	if nickname == "" {
+		details = append(details, &errors.FieldError{Field: "nickname", Message: "must not be empty"})
+	}
+	parseUint := func(field string, value *uint64, max uint64) {
+		if arg := ctx.FormValue(field); len(arg) != 0 {
+			parsedValue, err := strconv.ParseUint(string(arg), 10, 64)
+			switch {
+			case err != nil:
+				details = append(details, &errors.FieldError{Field: field, Message: "must be a non-negative integer"})
+			case parsedValue > max:
+				details = append(details, &errors.FieldError{Field: field, Message: "must not be greater than " +
+					strconv.FormatUint(max, 10)})
+			default:
+				*value = parsedValue
+			}
+		}
+	}
+	parseUint("limit", &paginator.Limit, models.MaxNotificationsLimit)
+	// Ids are bigint, larger ones can't be sent to the database.
+	parseUint("since", &paginator.Since, math.MaxInt64)
+	if isUnread := string(ctx.FormValue("unread")); isUnread == "true" {
+		paginator.Unread = true
+	}
+	if len(details) != 0 {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(details...))
+		return
+	}
+
+	selectedNotifications, err := h.NotificationsUCase.GetNotifications(http_utils.Context(ctx), nickname, paginator)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, selectedNotifications, http.StatusOK)
+}
+
+func (h *NotificationsHandler) GetNotificationsStatus(ctx *fasthttp.RequestCtx) {
+	nickname := ctx.UserValue("nickname").(string)
+	if nickname == "" {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "nickname", Message: "must not be empty"}))
+		return
+	}
+
+	status, err := h.NotificationsUCase.GetNotificationsStatus(http_utils.Context(ctx), nickname)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, status, http.StatusOK)
+}
+
+func (h *NotificationsHandler) MarkNotificationsRead(ctx *fasthttp.RequestCtx) {
+	h.markNotifications(ctx, true)
+}
+
+func (h *NotificationsHandler) MarkNotificationsUnread(ctx *fasthttp.RequestCtx) {
+	h.markNotifications(ctx, false)
+}
+
+// markNotifications marks the notifications listed in the body, all of
+// them when the body or its list is empty.
+func (h *NotificationsHandler) markNotifications(ctx *fasthttp.RequestCtx, isRead bool) {
+	nickname := ctx.UserValue("nickname").(string)
+	if nickname == "" {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "nickname", Message: "must not be empty"}))
+		return
+	}
+
+	mark := &models.NotificationsMark{}
+	if body := ctx.PostBody(); len(body) != 0 {
+		if err := json.Unmarshal(body, mark); err != nil {
+			http_utils.SetErrorResponse(ctx, errors.ErrBadRequest.Wrap(err))
+			return
+		}
+	}
+
+	status, err := h.NotificationsUCase.MarkNotifications(http_utils.Context(ctx), nickname, mark, isRead)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, status, http.StatusOK)
+}
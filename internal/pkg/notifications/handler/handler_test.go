@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/forum-api-back/internal/pkg/events"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/pkg/errors"
+
+	"github.com/valyala/fasthttp"
+)
+
+// recordingUseCase keeps the paginator notifications are listed with.
+type recordingUseCase struct {
+	paginator *models.NotificationPaginator
+}
+
+func (u *recordingUseCase) GetNotifications(ctx context.Context, nickname string,
+	paginator *models.NotificationPaginator) ([]*models.Notification, error) {
+	u.paginator = paginator
+	return []*models.Notification{}, nil
+}
+
+func (u *recordingUseCase) GetNotificationsStatus(ctx context.Context, nickname string) (*models.NotificationsStatus, error) {
+	return &models.NotificationsStatus{}, nil
+}
+
+func (u *recordingUseCase) MarkNotifications(ctx context.Context, nickname string, mark *models.NotificationsMark,
+	isRead bool) (*models.NotificationsStatus, error) {
+	return &models.NotificationsStatus{}, nil
+}
+
+func (u *recordingUseCase) Notify(ctx context.Context, event events.Event) error {
+	return nil
+}
+
+func TestGetNotificationsBoundsPages(t *testing.T) {
+	tests := []struct {
+		args   string
+		status int
+		field  string
+		limit  uint64
+		since  uint64
+	}{
+		{"", http.StatusOK, "", 100, 0},
+		{"?limit=20&since=40", http.StatusOK, "", 20, 40},
+		{"?limit=100&since=9223372036854775807", http.StatusOK, "", 100, 9223372036854775807},
+		{"?limit=101", http.StatusBadRequest, "limit", 0, 0},
+		{"?limit=18446744073709551615", http.StatusBadRequest, "limit", 0, 0},
+		{"?since=9223372036854775808", http.StatusBadRequest, "since", 0, 0},
+		{"?limit=-1", http.StatusBadRequest, "limit", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.args, func(t *testing.T) {
+			notificationsUCase := &recordingUseCase{}
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.SetRequestURI("/api/user/alice/notifications" + tt.args)
+			ctx.SetUserValue("nickname", "alice")
+
+			NewHandler(notificationsUCase).GetNotifications(ctx)
+
+			if ctx.Response.StatusCode() != tt.status {
+				t.Fatalf("status = %d, want %d", ctx.Response.StatusCode(), tt.status)
+			}
+			if tt.status != http.StatusOK {
+				response := &errors.Error{}
+				if err := json.Unmarshal(ctx.Response.Body(), response); err != nil {
+					t.Fatal(err)
+				}
+				if len(response.Details) != 1 || response.Details[0].Field != tt.field {
+					t.Errorf("details = %s, want one about %s", ctx.Response.Body(), tt.field)
+				}
+				return
+			}
+			if notificationsUCase.paginator.Limit != tt.limit || notificationsUCase.paginator.Since != tt.since {
+				t.Errorf("paginator = %+v, want limit %d since %d", notificationsUCase.paginator, tt.limit, tt.since)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package notifications
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
+
+type Repository interface {
+	// InsertNotifications skips the notifications of users or threads that
+	// don't exist, such as mentions of unknown nicknames.
+	InsertNotifications(ctx context.Context, notifications []*models.Notification) error
+	SelectNotifications(ctx context.Context, nickname string,
+		paginator *models.NotificationPaginator) ([]*models.Notification, error)
+	CountUnread(ctx context.Context, nickname string) (uint64, error)
+	// UpdateNotificationsRead marks the notifications of a user with the
+	// given ids, all of them when ids is empty.
+	UpdateNotificationsRead(ctx context.Context, nickname string, ids []uint64, isRead bool) error
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/notifications"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+)
+
+type MemoryRepository struct {
+	storage *memory.Storage
+}
+
+func NewSessionMemoryRepository(storage *memory.Storage) notifications.Repository {
+	return &MemoryRepository{
+		storage: storage,
+	}
+}
+
+func (r *MemoryRepository) InsertNotifications(ctx context.Context, newNotifications []*models.Notification) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	now := memory.Now()
+	for _, notification := range newNotifications {
+		recipient, ok := r.storage.UserByNickName(notification.Recipient)
+		if !ok {
+			continue
+		}
+		if _, ok := r.storage.Threads[notification.Thread]; !ok {
+			continue
+		}
+
+		insertedNotification := *notification
+		insertedNotification.Id = r.storage.NextNotificationId()
+		insertedNotification.Recipient = recipient.NickName
+		insertedNotification.IsRead = false
+		insertedNotification.DateCreated = now
+		r.storage.Notifications[insertedNotification.Id] = &insertedNotification
+	}
+
+	return nil
+}
+
+func (r *MemoryRepository) SelectNotifications(ctx context.Context, nickname string,
+	paginator *models.NotificationPaginator) ([]*models.Notification, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	nicknameKey := memory.Key(nickname)
+	selectedNotifications := make([]*models.Notification, 0)
+	for _, notification := range r.storage.Notifications {
+		switch {
+		case memory.Key(notification.Recipient) != nicknameKey:
+		case paginator.Since != 0 && notification.Id >= paginator.Since:
+		case paginator.Unread && notification.IsRead:
+		default:
+			copiedNotification := *notification
+			selectedNotifications = append(selectedNotifications, &copiedNotification)
+		}
+	}
+	sort.Slice(selectedNotifications, func(i, j int) bool {
+		return selectedNotifications[i].Id > selectedNotifications[j].Id
+	})
+	if uint64(len(selectedNotifications)) > paginator.Limit {
+		selectedNotifications = selectedNotifications[:paginator.Limit]
+	}
+
+	return selectedNotifications, nil
+}
+
+func (r *MemoryRepository) CountUnread(ctx context.Context, nickname string) (uint64, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	nicknameKey := memory.Key(nickname)
+	var unread uint64
+	for _, notification := range r.storage.Notifications {
+		if memory.Key(notification.Recipient) == nicknameKey && !notification.IsRead {
+			unread++
+		}
+	}
+
+	return unread, nil
+}
+
+func (r *MemoryRepository) UpdateNotificationsRead(ctx context.Context, nickname string,
+	ids []uint64, isRead bool) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	nicknameKey := memory.Key(nickname)
+	if len(ids) == 0 {
+		for _, notification := range r.storage.Notifications {
+			if memory.Key(notification.Recipient) == nicknameKey {
+				notification.IsRead = isRead
+			}
+		}
+		return nil
+	}
+
+	for _, id := range ids {
+		if notification, ok := r.storage.Notifications[id]; ok && memory.Key(notification.Recipient) == nicknameKey {
+			notification.IsRead = isRead
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/notifications"
+	"github.com/forum-api-back/pkg/logger"
+	"github.com/forum-api-back/pkg/metrics"
+)
+
+// MeasuredRepository observes how long every method takes and logs the
+// calls taking slowQuery or longer.
+type MeasuredRepository struct {
+	repo      notifications.Repository
+	durations *metrics.HistogramVec
+	slowQuery time.Duration
+}
+
+// NewMeasuredRepository observes the durations of the methods of repo into
+// durations, labeled with the repository and the method. Calls taking
+// slowQuery or longer are logged as well, unless it is zero.
+func NewMeasuredRepository(repo notifications.Repository, durations *metrics.HistogramVec,
+	slowQuery time.Duration) notifications.Repository {
+	return &MeasuredRepository{
+		repo:      repo,
+		durations: durations,
+		slowQuery: slowQuery,
+	}
+}
+
+func (r *MeasuredRepository) InsertNotifications(ctx context.Context, notifications []*models.Notification) error {
+	defer r.observe(ctx, "InsertNotifications", time.Now())
+	return r.repo.InsertNotifications(ctx, notifications)
+}
+
+func (r *MeasuredRepository) SelectNotifications(ctx context.Context, nickname string,
+	paginator *models.NotificationPaginator) ([]*models.Notification, error) {
+	defer r.observe(ctx, "SelectNotifications", time.Now())
+	return r.repo.SelectNotifications(ctx, nickname, paginator)
+}
+
+func (r *MeasuredRepository) CountUnread(ctx context.Context, nickname string) (uint64, error) {
+	defer r.observe(ctx, "CountUnread", time.Now())
+	return r.repo.CountUnread(ctx, nickname)
+}
+
+func (r *MeasuredRepository) UpdateNotificationsRead(ctx context.Context, nickname string, ids []uint64,
+	isRead bool) error {
+	defer r.observe(ctx, "UpdateNotificationsRead", time.Now())
+	return r.repo.UpdateNotificationsRead(ctx, nickname, ids, isRead)
+}
+
+func (r *MeasuredRepository) observe(ctx context.Context, method string, start time.Time) {
+	elapsed := time.Since(start)
+	r.durations.With("notifications", method).Observe(elapsed.Seconds())
+	if r.slowQuery > 0 && elapsed >= r.slowQuery {
+		logger.FromContext(ctx).Warn("slow query", "repository", "notifications", "method", method, "duration", elapsed)
+	}
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/notifications"
+	"github.com/forum-api-back/pkg/errors"
+	"github.com/forum-api-back/pkg/tools/sql_utils"
+
+	"github.com/lib/pq"
+)
+
+type PostgresqlRepository struct {
+	db *sql.DB
+}
+
+func NewSessionPostgresqlRepository(db *sql.DB) notifications.Repository {
+	return &PostgresqlRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresqlRepository) InsertNotifications(ctx context.Context, newNotifications []*models.Notification) error {
+	if len(newNotifications) == 0 {
+		return nil
+	}
+
+	recipients := make([]string, len(newNotifications))
+	types := make([]string, len(newNotifications))
+	actors := make([]string, len(newNotifications))
+	forums := make([]string, len(newNotifications))
+	threadIds := make([]int64, len(newNotifications))
+	postIds := make([]int64, len(newNotifications))
+	voices := make([]int64, len(newNotifications))
+	for i, notification := range newNotifications {
+		recipients[i] = notification.Recipient
+		types[i] = notification.Type
+		actors[i] = notification.Actor
+		forums[i] = notification.Forum
+		threadIds[i] = int64(notification.Thread)
+		postIds[i] = int64(notification.Post)
+		voices[i] = int64(notification.Voice)
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		"INSERT INTO notifications "+
+			"(user_nickname, type, actor_nickname, forum_slug, thread_id, post_id, voice) "+
+			"SELECT users.nickname, new_notifications.type, new_notifications.actor, "+
+			"	new_notifications.forum, new_notifications.thread_id, new_notifications.post_id, "+
+			"	new_notifications.voice "+
+			"FROM unnest($1::CITEXT[], $2::TEXT[], $3::CITEXT[], $4::CITEXT[], $5::INTEGER[], "+
+			"	$6::INTEGER[], $7::INTEGER[]) "+
+			"	AS new_notifications(recipient, type, actor, forum, thread_id, post_id, voice) "+
+			"JOIN users ON (users.nickname = new_notifications.recipient) "+
+			"JOIN threads ON (threads.id = new_notifications.thread_id)",
+		pq.Array(recipients),
+		pq.Array(types),
+		pq.Array(actors),
+		pq.Array(forums),
+		pq.Array(threadIds),
+		pq.Array(postIds),
+		pq.Array(voices),
+	)
+
+	return sql_utils.Error(err, errors.ErrUserNotFound)
+}
+
+func (r *PostgresqlRepository) SelectNotifications(ctx context.Context, nickname string,
+	paginator *models.NotificationPaginator) ([]*models.Notification, error) {
+	query := "SELECT id, type, actor_nickname, forum_slug, thread_id, post_id, voice, is_read, date_created " +
+		"FROM notifications " +
+		"WHERE user_nickname = $1 AND ($2 = 0 OR id < $2) "
+	if paginator.Unread {
+		query += "AND NOT is_read "
+	}
+	query += "ORDER BY id DESC " +
+		"LIMIT $3"
+
+	rows, err := r.db.QueryContext(ctx, query, nickname, paginator.Since, paginator.Limit)
+	if err != nil {
+		return nil, sql_utils.Error(err, errors.ErrUserNotFound)
+	}
+	defer rows.Close()
+
+	selectedNotifications := make([]*models.Notification, 0)
+	for rows.Next() {
+		notification := &models.Notification{Recipient: nickname}
+		err = rows.Scan(
+			&notification.Id,
+			&notification.Type,
+			&notification.Actor,
+			&notification.Forum,
+			&notification.Thread,
+			&notification.Post,
+			&notification.Voice,
+			&notification.IsRead,
+			&notification.DateCreated,
+		)
+		if err != nil {
+			return nil, sql_utils.Error(err, errors.ErrUserNotFound)
+		}
+
+		selectedNotifications = append(selectedNotifications, notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, sql_utils.Error(err, errors.ErrUserNotFound)
+	}
+
+	return selectedNotifications, nil
+}
+
+func (r *PostgresqlRepository) CountUnread(ctx context.Context, nickname string) (uint64, error) {
+	row := r.db.QueryRowContext(ctx,
+		"SELECT count(*) "+
+			"FROM notifications "+
+			"WHERE user_nickname = $1 AND NOT is_read",
+		nickname,
+	)
+
+	var unread uint64
+	if err := row.Scan(&unread); err != nil {
+		return 0, sql_utils.Error(err, errors.ErrUserNotFound)
+	}
+
+	return unread, nil
+}
+
+func (r *PostgresqlRepository) UpdateNotificationsRead(ctx context.Context, nickname string,
+	ids []uint64, isRead bool) error {
+	var err error
+	if len(ids) == 0 {
+		_, err = r.db.ExecContext(ctx,
+			"UPDATE notifications SET "+
+				"is_read = $2 "+
+				"WHERE user_nickname = $1 AND is_read <> $2",
+			nickname,
+			isRead,
+		)
+	} else {
+		notificationIds := make([]int64, len(ids))
+		for i, id := range ids {
+			notificationIds[i] = int64(id)
+		}
+		_, err = r.db.ExecContext(ctx,
+			"UPDATE notifications SET "+
+				"is_read = $2 "+
+				"WHERE user_nickname = $1 AND id = ANY($3::INTEGER[])",
+			nickname,
+			isRead,
+			pq.Array(notificationIds),
+		)
+	}
+
+	return sql_utils.Error(err, errors.ErrUserNotFound)
+}
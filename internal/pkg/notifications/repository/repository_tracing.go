@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/notifications"
+	"github.com/forum-api-back/pkg/tracing"
+)
+
+// TracedRepository records a span for every method called within a traced request.
+type TracedRepository struct {
+	repo notifications.Repository
+}
+
+func NewTracedRepository(repo notifications.Repository) notifications.Repository {
+	return &TracedRepository{
+		repo: repo,
+	}
+}
+
+func (r *TracedRepository) InsertNotifications(ctx context.Context,
+	notifications []*models.Notification) (err error) {
+	ctx, span := tracing.Start(ctx, "notifications.repository.InsertNotifications")
+	defer func() { span.End(err) }()
+	return r.repo.InsertNotifications(ctx, notifications)
+}
+
+func (r *TracedRepository) SelectNotifications(ctx context.Context, nickname string,
+	paginator *models.NotificationPaginator) (_ []*models.Notification, err error) {
+	ctx, span := tracing.Start(ctx, "notifications.repository.SelectNotifications")
+	defer func() { span.End(err) }()
+	return r.repo.SelectNotifications(ctx, nickname, paginator)
+}
+
+func (r *TracedRepository) CountUnread(ctx context.Context, nickname string) (_ uint64, err error) {
+	ctx, span := tracing.Start(ctx, "notifications.repository.CountUnread")
+	defer func() { span.End(err) }()
+	return r.repo.CountUnread(ctx, nickname)
+}
+
+func (r *TracedRepository) UpdateNotificationsRead(ctx context.Context, nickname string, ids []uint64,
+	isRead bool) (err error) {
+	ctx, span := tracing.Start(ctx, "notifications.repository.UpdateNotificationsRead")
+	defer func() { span.End(err) }()
+	return r.repo.UpdateNotificationsRead(ctx, nickname, ids, isRead)
+}
@@ -0,0 +1,19 @@
+package notifications
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/events"
+	"github.com/forum-api-back/internal/pkg/models"
+)
+
+type UseCase interface {
+	GetNotifications(ctx context.Context, nickname string,
+		paginator *models.NotificationPaginator) ([]*models.Notification, error)
+	GetNotificationsStatus(ctx context.Context, nickname string) (*models.NotificationsStatus, error)
+	MarkNotifications(ctx context.Context, nickname string, mark *models.NotificationsMark,
+		isRead bool) (*models.NotificationsStatus, error)
+
+	// Notify records the notifications caused by an event.
+	Notify(ctx context.Context, event events.Event) error
+}
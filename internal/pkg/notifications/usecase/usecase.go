@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/forum-api-back/internal/pkg/auth"
+	"github.com/forum-api-back/internal/pkg/events"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/notifications"
+	"github.com/forum-api-back/internal/pkg/post"
+	"github.com/forum-api-back/internal/pkg/user"
+	"github.com/forum-api-back/pkg/errors"
+)
+
+// maxMentions bounds the users a single message notifies.
+const maxMentions = 10
+
+// mentionPattern matches @nickname unless it is a part of a word, like in
+// an email address.
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_.@])@([A-Za-z0-9_.]+)`)
+
+type NotificationsUseCase struct {
+	NotificationsRepo notifications.Repository
+	PostRepo          post.Repository
+	UserRepo          user.Repository
+}
+
+func NewUseCase(notificationsRepo notifications.Repository, postRepo post.Repository,
+	userRepo user.Repository) notifications.UseCase {
+	return &NotificationsUseCase{
+		NotificationsRepo: notificationsRepo,
+		PostRepo:          postRepo,
+		UserRepo:          userRepo,
+	}
+}
+
+func (u *NotificationsUseCase) GetNotifications(ctx context.Context, nickname string,
+	paginator *models.NotificationPaginator) ([]*models.Notification, error) {
+	recipient, err := u.authorize(ctx, nickname)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.NotificationsRepo.SelectNotifications(ctx, recipient, paginator)
+}
+
+func (u *NotificationsUseCase) GetNotificationsStatus(ctx context.Context,
+	nickname string) (*models.NotificationsStatus, error) {
+	recipient, err := u.authorize(ctx, nickname)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.status(ctx, recipient)
+}
+
+func (u *NotificationsUseCase) MarkNotifications(ctx context.Context, nickname string,
+	mark *models.NotificationsMark, isRead bool) (*models.NotificationsStatus, error) {
+	recipient, err := u.authorize(ctx, nickname)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.NotificationsRepo.UpdateNotificationsRead(ctx, recipient, mark.Ids, isRead); err != nil {
+		return nil, err
+	}
+
+	return u.status(ctx, recipient)
+}
+
+func (u *NotificationsUseCase) Notify(ctx context.Context, event events.Event) error {
+	var newNotifications []*models.Notification
+	switch event := event.(type) {
+	case *events.ThreadCreated:
+		newNotifications = mentions(event.Thread.Message, event.Thread.AuthorNickName, &models.Notification{
+			Forum:  event.Thread.Forum,
+			Thread: event.Thread.Id,
+		}, nil)
+	case *events.PostsCreated:
+		var err error
+		if newNotifications, err = u.postNotifications(ctx, event.Posts); err != nil {
+			return err
+		}
+	case *events.VoteCast:
+		if strings.EqualFold(event.Vote.NickName, event.Thread.AuthorNickName) {
+			return nil
+		}
+		newNotifications = []*models.Notification{{
+			Type:      models.NotificationVote,
+			Recipient: event.Thread.AuthorNickName,
+			Actor:     event.Vote.NickName,
+			Forum:     event.Thread.Forum,
+			Thread:    event.Thread.Id,
+			Voice:     event.Vote.Voice,
+		}}
+	default:
+		return nil
+	}
+
+	return u.NotificationsRepo.InsertNotifications(ctx, newNotifications)
+}
+
+// postNotifications notifies the authors of the posts replied to and the
+// users mentioned in the new posts. A user replied to and mentioned in
+// the same post is only notified about the reply.
+func (u *NotificationsUseCase) postNotifications(ctx context.Context,
+	posts []*models.Post) ([]*models.Notification, error) {
+	parentAuthors := make(map[uint64]string)
+	newNotifications := make([]*models.Notification, 0)
+	for _, newPost := range posts {
+		notified := make(map[string]bool)
+		if newPost.Parent != 0 {
+			parentAuthor, ok := parentAuthors[newPost.Parent]
+			if !ok {
+				parentPost, err := u.PostRepo.SelectPostById(ctx, newPost.Parent)
+				switch {
+				case errors.Is(err, errors.ErrPostNotFound):
+				case err != nil:
+					return nil, err
+				case !parentPost.IsDeleted:
+					parentAuthor = parentPost.Author
+				}
+				parentAuthors[newPost.Parent] = parentAuthor
+			}
+
+			if parentAuthor != "" && !strings.EqualFold(parentAuthor, newPost.Author) {
+				notified[strings.ToLower(parentAuthor)] = true
+				newNotifications = append(newNotifications, &models.Notification{
+					Type:      models.NotificationReply,
+					Recipient: parentAuthor,
+					Actor:     newPost.Author,
+					Forum:     newPost.Forum,
+					Thread:    newPost.Thread,
+					Post:      newPost.Id,
+				})
+			}
+		}
+
+		newNotifications = append(newNotifications, mentions(newPost.Message, newPost.Author, &models.Notification{
+			Forum:  newPost.Forum,
+			Thread: newPost.Thread,
+			Post:   newPost.Id,
+		}, notified)...)
+	}
+
+	return newNotifications, nil
+}
+
+// authorize lets users only see their own notifications and returns the
+// nickname they are stored under.
+func (u *NotificationsUseCase) authorize(ctx context.Context, nickname string) (string, error) {
+	caller, ok := auth.Caller(ctx)
+	if !ok {
+		return "", errors.ErrUnauthorized
+	}
+
+	selectedUser, err := u.UserRepo.SelectUserByNickName(ctx, nickname)
+	if err != nil {
+		return "", errors.Translate(err, errors.ErrUserNotFound.WithMessage("Can't find user by nickname: %s", nickname))
+	}
+	if !strings.EqualFold(caller, selectedUser.NickName) {
+		return "", errors.ErrForbidden.WithMessage("Can't see notifications of user: %s", nickname)
+	}
+
+	return selectedUser.NickName, nil
+}
+
+func (u *NotificationsUseCase) status(ctx context.Context, nickname string) (*models.NotificationsStatus, error) {
+	unread, err := u.NotificationsRepo.CountUnread(ctx, nickname)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.NotificationsStatus{Unread: unread}, nil
+}
+
+// mentions makes a mention notification like source for every user named
+// in message, except the author and the users already notified.
+func mentions(message, author string, source *models.Notification,
+	notified map[string]bool) []*models.Notification {
+	seen := map[string]bool{strings.ToLower(author): true}
+	mentionNotifications := make([]*models.Notification, 0)
+	for _, match := range mentionPattern.FindAllStringSubmatch(message, -1) {
+		// A dot ending the nickname most likely ends the sentence.
+		nickname := strings.TrimRight(match[1], ".")
+		key := strings.ToLower(nickname)
+		if nickname == "" || seen[key] || notified[key] {
+			continue
+		}
+		seen[key] = true
+
+		notification := *source
+		notification.Type = models.NotificationMention
+		notification.Recipient = nickname
+		notification.Actor = author
+		mentionNotifications = append(mentionNotifications, &notification)
+
+		if len(mentionNotifications) == maxMentions {
+			break
+		}
+	}
+
+	return mentionNotifications
+}
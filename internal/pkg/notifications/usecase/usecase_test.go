@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/forum-api-back/internal/pkg/auth"
+	"github.com/forum-api-back/internal/pkg/events"
+	"github.com/forum-api-back/internal/pkg/models"
+	notifications_repo "github.com/forum-api-back/internal/pkg/notifications/repository"
+	post_repo "github.com/forum-api-back/internal/pkg/post/repository"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+	user_repo "github.com/forum-api-back/internal/pkg/user/repository"
+	"github.com/forum-api-back/pkg/errors"
+)
+
+// newTestUseCase returns a usecase over users "alice" and "bob", thread 1
+// of forum "f" and post 1 of alice in it.
+func newTestUseCase(t *testing.T) (*NotificationsUseCase, *memory.Storage) {
+	t.Helper()
+
+	storage := memory.NewStorage()
+	for _, nickname := range []string{"alice", "bob"} {
+		storage.Users[nickname] = &models.User{NickName: nickname, Email: nickname + "@example.com"}
+	}
+	storage.Threads[1] = &models.Thread{Id: 1, Forum: "f", AuthorNickName: "alice"}
+	storage.Posts[1] = &memory.Post{Post: models.Post{Id: 1, Author: "alice", Forum: "f", Thread: 1}}
+
+	return NewUseCase(notifications_repo.NewSessionMemoryRepository(storage), post_repo.NewSessionMemoryRepository(storage),
+		user_repo.NewSessionMemoryRepository(storage)).(*NotificationsUseCase), storage
+}
+
+// formatNotifications writes notifications as "type:recipient".
+func formatNotifications(notifications []*models.Notification) string {
+	formatted := make([]string, 0, len(notifications))
+	for _, notification := range notifications {
+		formatted = append(formatted, notification.Type+":"+notification.Recipient)
+	}
+	return strings.Join(formatted, ",")
+}
+
+func TestMentions(t *testing.T) {
+	many := make([]string, 0, maxMentions+2)
+	want := make([]string, 0, maxMentions)
+	for i := 0; i < maxMentions+2; i++ {
+		many = append(many, fmt.Sprintf("@user%d", i))
+		if i < maxMentions {
+			want = append(want, fmt.Sprintf("mention:user%d", i))
+		}
+	}
+
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{"mention", "hi @bob", "mention:bob"},
+		{"start of message", "@bob hi", "mention:bob"},
+		{"email", "write to bob@example.com", ""},
+		{"in a word", "x@bob", ""},
+		{"trailing dot", "thanks @bob.", "mention:bob"},
+		{"dotted nickname", "thanks @bob.smith.", "mention:bob.smith"},
+		{"repeated", "@bob and @BOB", "mention:bob"},
+		{"yourself", "I am @Alice", ""},
+		{"several", "@bob, @carol", "mention:bob,mention:carol"},
+		{"max mentions", strings.Join(many, " "), strings.Join(want, ",")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mentions(tt.message, "alice", &models.Notification{Forum: "f", Thread: 1}, nil)
+			if formatted := formatNotifications(got); formatted != tt.want {
+				t.Errorf("mentions(%q) = %s, want %s", tt.message, formatted, tt.want)
+			}
+			for _, notification := range got {
+				if notification.Actor != "alice" || notification.Forum != "f" || notification.Thread != 1 {
+					t.Errorf("notification = %+v, want one by alice in thread 1 of f", notification)
+				}
+			}
+		})
+	}
+}
+
+func TestPostNotifications(t *testing.T) {
+	tests := []struct {
+		name    string
+		author  string
+		parent  uint64
+		message string
+		want    string
+	}{
+		{"reply", "bob", 1, "agreed", "reply:alice"},
+		{"reply to yourself", "alice", 1, "and also", ""},
+		{"replied to and mentioned", "bob", 1, "right, @alice", "reply:alice"},
+		{"reply and mention", "bob", 1, "@carol look", "reply:alice,mention:carol"},
+		{"mention", "bob", 0, "@alice look", "mention:alice"},
+		{"unknown parent", "bob", 7, "lost", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := newTestUseCase(t)
+
+			got, err := u.postNotifications(context.Background(), []*models.Post{
+				{Id: 2, Author: tt.author, Parent: tt.parent, Message: tt.message, Forum: "f", Thread: 1},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if formatted := formatNotifications(got); formatted != tt.want {
+				t.Errorf("notifications = %s, want %s", formatted, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostNotificationsSkipDeletedParent(t *testing.T) {
+	u, storage := newTestUseCase(t)
+	storage.Posts[1].IsDeleted = true
+
+	got, err := u.postNotifications(context.Background(), []*models.Post{
+		{Id: 2, Author: "bob", Parent: 1, Message: "agreed", Forum: "f", Thread: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("notifications = %s, want none", formatNotifications(got))
+	}
+}
+
+func TestNotifyVote(t *testing.T) {
+	tests := []struct {
+		voter string
+		want  int
+	}{
+		{"bob", 1},
+		{"ALICE", 0},
+	}
+
+	for _, tt := range tests {
+		u, storage := newTestUseCase(t)
+		err := u.Notify(context.Background(), &events.VoteCast{
+			Thread: storage.Threads[1],
+			Vote:   &models.ThreadVote{NickName: tt.voter, Voice: 1},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(storage.Notifications) != tt.want {
+			t.Errorf("vote of %s made %d notifications, want %d", tt.voter, len(storage.Notifications), tt.want)
+		}
+	}
+}
+
+func TestGetNotificationsOfOthers(t *testing.T) {
+	tests := []struct {
+		name   string
+		caller string
+		code   errors.Code
+	}{
+		{"no session", "", errors.CodeUnauthorized},
+		{"own", "ALICE", ""},
+		{"other user", "bob", errors.CodeForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, storage := newTestUseCase(t)
+			ctx := context.Background()
+			if err := u.Notify(ctx, &events.PostsCreated{Thread: storage.Threads[1], Posts: []*models.Post{
+				{Id: 2, Author: "bob", Parent: 1, Message: "agreed", Forum: "f", Thread: 1},
+			}}); err != nil {
+				t.Fatal(err)
+			}
+			if tt.caller != "" {
+				ctx = auth.WithCaller(ctx, tt.caller)
+			}
+
+			got, err := u.GetNotifications(ctx, "alice", &models.NotificationPaginator{Limit: models.MaxNotificationsLimit})
+			if tt.code != "" {
+				if errors.From(err).Code != tt.code {
+					t.Fatalf("error = %v, want code %v", err, tt.code)
+				}
+				if _, err := u.MarkNotifications(ctx, "alice", &models.NotificationsMark{}, true); errors.From(err).Code != tt.code {
+					t.Errorf("marking error = %v, want code %v", err, tt.code)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if formatNotifications(got) != "reply:alice" {
+				t.Errorf("notifications = %s, want reply:alice", formatNotifications(got))
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/events"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/notifications"
+	"github.com/forum-api-back/pkg/tracing"
+)
+
+// TracedUseCase records a span for every method called within a traced request.
+type TracedUseCase struct {
+	ucase notifications.UseCase
+}
+
+func NewTracedUseCase(ucase notifications.UseCase) notifications.UseCase {
+	return &TracedUseCase{
+		ucase: ucase,
+	}
+}
+
+func (u *TracedUseCase) GetNotifications(ctx context.Context, nickname string,
+	paginator *models.NotificationPaginator) (_ []*models.Notification, err error) {
+	ctx, span := tracing.Start(ctx, "notifications.usecase.GetNotifications")
+	defer func() { span.End(err) }()
+	return u.ucase.GetNotifications(ctx, nickname, paginator)
+}
+
+func (u *TracedUseCase) GetNotificationsStatus(ctx context.Context,
+	nickname string) (_ *models.NotificationsStatus, err error) {
+	ctx, span := tracing.Start(ctx, "notifications.usecase.GetNotificationsStatus")
+	defer func() { span.End(err) }()
+	return u.ucase.GetNotificationsStatus(ctx, nickname)
+}
+
+func (u *TracedUseCase) MarkNotifications(ctx context.Context, nickname string,
+	mark *models.NotificationsMark, isRead bool) (_ *models.NotificationsStatus, err error) {
+	ctx, span := tracing.Start(ctx, "notifications.usecase.MarkNotifications")
+	defer func() { span.End(err) }()
+	return u.ucase.MarkNotifications(ctx, nickname, mark, isRead)
+}
+
+func (u *TracedUseCase) Notify(ctx context.Context, event events.Event) (err error) {
+	ctx, span := tracing.Start(ctx, "notifications.usecase.Notify")
+	defer func() { span.End(err) }()
+	return u.ucase.Notify(ctx, event)
+}
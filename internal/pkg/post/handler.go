@@ -7,4 +7,8 @@ type Handler interface {
 	GetPostDetails(ctx *fasthttp.RequestCtx)
 	GetPostsByThread(ctx *fasthttp.RequestCtx)
 	UpdatePostDetails(ctx *fasthttp.RequestCtx)
+	DeletePost(ctx *fasthttp.RequestCtx)
+	GetPostHistory(ctx *fasthttp.RequestCtx)
+	DiffPostRevisions(ctx *fasthttp.RequestCtx)
+	RestorePostRevision(ctx *fasthttp.RequestCtx)
 }
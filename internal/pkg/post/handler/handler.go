@@ -3,7 +3,6 @@ package handler
 import (
 	"bytes"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"strconv"
 
@@ -28,36 +27,31 @@ func NewHandler(postUCase post.UseCase) post.Handler {
 func (h *PostHandler) CreateNewPosts(ctx *fasthttp.RequestCtx) {
 	var postsInfo []*models.PostCreate
 	if err := json.Unmarshal(ctx.PostBody(), &postsInfo); err != nil {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadRequest, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadRequest.Wrap(err))
 		return
 	}
 
 	threadSlugOrId := ctx.UserValue("slug_or_id").(string)
 	if threadSlugOrId == "" {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadArguments, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "slug_or_id", Message: "must not be empty"}))
 		return
 	}
 
-	newThreads, err := h.PostUCase.CreateNewPosts(threadSlugOrId, postsInfo)
-	switch err {
-	case nil:
-		http_utils.SetJSONResponse(ctx, newThreads, http.StatusCreated)
-	case errors.ErrPostNotFound:
-		http_utils.SetJSONResponse(ctx, errors.ErrDataConflict, http.StatusConflict)
-	case errors.ErrUserNotFound:
-		http_utils.SetJSONResponse(ctx, errors.ErrUserNotFound, http.StatusNotFound)
-	case errors.ErrThreadNotFound:
-		http_utils.SetJSONResponse(ctx,
-			errors.Error{Message: fmt.Sprintf("Can't find post thread by id: %s", threadSlugOrId)}, http.StatusNotFound)
-	default:
-		http_utils.SetJSONResponse(ctx, errors.ErrInternalError, http.StatusInternalServerError)
+	newThreads, err := h.PostUCase.CreateNewPosts(http_utils.Context(ctx), threadSlugOrId, postsInfo)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
 	}
+
+	http_utils.SetJSONResponse(ctx, newThreads, http.StatusCreated)
 }
 
 func (h *PostHandler) GetPostDetails(ctx *fasthttp.RequestCtx) {
 	forumSlug, err := strconv.Atoi(ctx.UserValue("id").(string))
 	if err != nil || forumSlug < 1 {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadArguments, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "id", Message: "must be a positive integer"}))
 		return
 	}
 
@@ -69,21 +63,20 @@ func (h *PostHandler) GetPostDetails(ctx *fasthttp.RequestCtx) {
 		}
 	}
 
-	postDetails, err := h.PostUCase.GetPostDetail(uint64(forumSlug), related)
-	switch err {
-	case nil:
-		http_utils.SetJSONResponse(ctx, postDetails, http.StatusOK)
-	case errors.ErrThreadNotFound:
-		http_utils.SetJSONResponse(ctx, errors.ErrThreadNotFound, http.StatusNotFound)
-	default:
-		http_utils.SetJSONResponse(ctx, errors.ErrInternalError, http.StatusInternalServerError)
+	postDetails, err := h.PostUCase.GetPostDetail(http_utils.Context(ctx), uint64(forumSlug), related)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
 	}
+
+	http_utils.SetJSONResponse(ctx, postDetails, http.StatusOK)
 }
 
 func (h *PostHandler) GetPostsByThread(ctx *fasthttp.RequestCtx) {
 	threadSlugOrId := ctx.UserValue("slug_or_id").(string)
 	if threadSlugOrId == "" {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadArguments, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "slug_or_id", Message: "must not be empty"}))
 		return
 	}
 
@@ -102,41 +95,142 @@ func (h *PostHandler) GetPostsByThread(ctx *fasthttp.RequestCtx) {
 		postPaginator.Limit = uint64(parseLimit)
 	}
 
-	if sort := string(ctx.FormValue("sort")); sort != "" {
+	switch sort := string(ctx.FormValue("sort")); sort {
+	case "":
+	case "flat", "tree", "parent_tree":
 		postPaginator.Sort = sort
+	default:
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "sort", Message: "must be one of flat, tree, parent_tree"}))
+		return
 	}
 
-	selectedPosts, err := h.PostUCase.GetPostsByThread(threadSlugOrId, postPaginator)
-	switch err {
-	case nil:
-		http_utils.SetJSONResponse(ctx, selectedPosts, http.StatusOK)
-	case errors.ErrThreadNotFound:
-		http_utils.SetJSONResponse(ctx, errors.ErrThreadNotFound, http.StatusNotFound)
-	default:
-		http_utils.SetJSONResponse(ctx, errors.ErrInternalError, http.StatusInternalServerError)
+	selectedPosts, err := h.PostUCase.GetPostsByThread(http_utils.Context(ctx), threadSlugOrId, postPaginator)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
 	}
+
+	http_utils.SetJSONResponse(ctx, selectedPosts, http.StatusOK)
 }
 
 func (h *PostHandler) UpdatePostDetails(ctx *fasthttp.RequestCtx) {
 	var postsInfo *models.PostUpdate
 	if err := json.Unmarshal(ctx.PostBody(), &postsInfo); err != nil {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadRequest, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadRequest.Wrap(err))
 		return
 	}
 
 	forumId, err := strconv.Atoi(ctx.UserValue("id").(string))
 	if err != nil || forumId < 1 {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadArguments, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "id", Message: "must be a positive integer"}))
 		return
 	}
 
-	updatedPost, err := h.PostUCase.UpdatePostDetails(uint64(forumId), postsInfo)
-	switch err {
-	case nil:
-		http_utils.SetJSONResponse(ctx, updatedPost, http.StatusOK)
-	case errors.ErrPostNotFound:
-		http_utils.SetJSONResponse(ctx, errors.ErrPostNotFound, http.StatusNotFound)
-	default:
-		http_utils.SetJSONResponse(ctx, errors.ErrInternalError, http.StatusInternalServerError)
+	updatedPost, err := h.PostUCase.UpdatePostDetails(http_utils.Context(ctx), uint64(forumId), postsInfo)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, updatedPost, http.StatusOK)
+}
+
+func (h *PostHandler) DeletePost(ctx *fasthttp.RequestCtx) {
+	postId, err := strconv.Atoi(ctx.UserValue("id").(string))
+	if err != nil || postId < 1 {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "id", Message: "must be a positive integer"}))
+		return
+	}
+
+	deletedPost, err := h.PostUCase.DeletePost(http_utils.Context(ctx), uint64(postId))
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
 	}
+
+	http_utils.SetJSONResponse(ctx, deletedPost, http.StatusOK)
+}
+
+func (h *PostHandler) GetPostHistory(ctx *fasthttp.RequestCtx) {
+	postId, err := strconv.Atoi(ctx.UserValue("id").(string))
+	if err != nil || postId < 1 {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "id", Message: "must be a positive integer"}))
+		return
+	}
+
+	revisions, err := h.PostUCase.GetPostHistory(http_utils.Context(ctx), uint64(postId))
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, revisions, http.StatusOK)
+}
+
+// DiffPostRevisions compares the revisions given by the from and to query
+// arguments. Without them the latest revision is compared to the one
+// before it.
+func (h *PostHandler) DiffPostRevisions(ctx *fasthttp.RequestCtx) {
+	postId, err := strconv.Atoi(ctx.UserValue("id").(string))
+	if err != nil || postId < 1 {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "id", Message: "must be a positive integer"}))
+		return
+	}
+
+	var fieldErrors []*errors.FieldError
+	revisions := map[string]uint64{"from": 0, "to": 0}
+	for _, field := range []string{"from", "to"} {
+		value := string(ctx.QueryArgs().Peek(field))
+		if value == "" {
+			continue
+		}
+		revision, err := strconv.Atoi(value)
+		if err != nil || revision < 1 {
+			fieldErrors = append(fieldErrors, &errors.FieldError{Field: field, Message: "must be a positive integer"})
+			continue
+		}
+		revisions[field] = uint64(revision)
+	}
+	if len(fieldErrors) != 0 {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(fieldErrors...))
+		return
+	}
+
+	diff, err := h.PostUCase.DiffPostRevisions(http_utils.Context(ctx), uint64(postId),
+		revisions["from"], revisions["to"])
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, diff, http.StatusOK)
+}
+
+func (h *PostHandler) RestorePostRevision(ctx *fasthttp.RequestCtx) {
+	postId, err := strconv.Atoi(ctx.UserValue("id").(string))
+	if err != nil || postId < 1 {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "id", Message: "must be a positive integer"}))
+		return
+	}
+
+	revision, err := strconv.Atoi(ctx.UserValue("revision").(string))
+	if err != nil || revision < 1 {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "revision", Message: "must be a positive integer"}))
+		return
+	}
+
+	restoredPost, err := h.PostUCase.RestorePostRevision(http_utils.Context(ctx), uint64(postId), uint64(revision))
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, restoredPost, http.StatusOK)
 }
@@ -1,11 +1,23 @@
 package post
 
-import "github.com/forum-api-back/internal/pkg/models"
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
 
 type Repository interface {
-	CreateNewPostsById(threadId uint64, forumSlug string,
+	CreateNewPostsById(ctx context.Context, threadId uint64, forumSlug string,
 		posts []*models.PostCreate) ([]*models.Post, error)
-	SelectPostById(postId uint64) (*models.Post, error)
-	SelectPostsById(threadId uint64, paginator *models.PostPaginator) ([]*models.Post, error)
-	UpdatePostById(postId uint64, postInfo *models.PostUpdate) error
+	SelectPostById(ctx context.Context, postId uint64) (*models.Post, error)
+	SelectPostsById(ctx context.Context, threadId uint64, paginator *models.PostPaginator) ([]*models.Post, error)
+	// UpdatePostById changes the message of a post and records it as a new
+	// revision made by editor.
+	UpdatePostById(ctx context.Context, postId uint64, postInfo *models.PostUpdate, editor string) error
+	// SelectRevisionsById returns the revisions of an edited post ordered
+	// by number. Posts that were never edited have none.
+	SelectRevisionsById(ctx context.Context, postId uint64) ([]*models.PostRevision, error)
+	// DeletePostById removes a post, or turns it into a tombstone when it
+	// has replies. Tombstones left without replies are removed as well.
+	DeletePostById(ctx context.Context, postId uint64) error
 }
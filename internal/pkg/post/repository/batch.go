@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/pkg/errors"
+)
+
+// checkPostsBatch validates every post of a batch against the thread it is
+// created in. parentThreads maps existing parent ids to their threads and
+// authors holds lower-cased nicknames of existing users.
+func checkPostsBatch(threadId uint64, posts []*models.PostCreate,
+	parentThreads map[uint64]uint64, authors map[string]bool) error {
+	items := make([]*errors.ItemError, 0)
+	for i, postInfo := range posts {
+		if postInfo.Parent != 0 {
+			parentThread, ok := parentThreads[postInfo.Parent]
+			switch {
+			case !ok:
+				items = append(items, &errors.ItemError{
+					Index:   i,
+					Field:   "parent",
+					Message: fmt.Sprintf("parent post %d not found", postInfo.Parent),
+					Err:     errors.ErrParentPostNotFound,
+				})
+			case parentThread != threadId:
+				items = append(items, &errors.ItemError{
+					Index:   i,
+					Field:   "parent",
+					Message: fmt.Sprintf("parent post %d was created in thread %d", postInfo.Parent, parentThread),
+					Err:     errors.ErrParentPostInAnotherThread,
+				})
+			}
+		}
+
+		if !authors[strings.ToLower(postInfo.Author)] {
+			items = append(items, &errors.ItemError{
+				Index:   i,
+				Field:   "author",
+				Message: fmt.Sprintf("user %s not found", postInfo.Author),
+				Err:     errors.ErrUserNotFound,
+			})
+		}
+	}
+
+	if len(items) != 0 {
+		return errors.NewBatchError(items)
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/cache"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/post"
+)
+
+// CachedRepository drops the forums whose post counters change from the
+// cache. Posts themselves are not cached.
+type CachedRepository struct {
+	post.Repository
+	cache cache.Cache
+}
+
+func NewCachedRepository(repo post.Repository, c cache.Cache) post.Repository {
+	return &CachedRepository{
+		Repository: repo,
+		cache:      c,
+	}
+}
+
+func (r *CachedRepository) CreateNewPostsById(ctx context.Context, threadId uint64, forumSlug string,
+	posts []*models.PostCreate) ([]*models.Post, error) {
+	newPosts, err := r.Repository.CreateNewPostsById(ctx, threadId, forumSlug, posts)
+	r.cache.Delete(ctx, cache.ForumKey(forumSlug))
+	return newPosts, err
+}
+
+func (r *CachedRepository) DeletePostById(ctx context.Context, postId uint64) error {
+	selectedPost, err := r.Repository.SelectPostById(ctx, postId)
+	if err != nil {
+		return r.Repository.DeletePostById(ctx, postId)
+	}
+
+	err = r.Repository.DeletePostById(ctx, postId)
+	r.cache.Delete(ctx, cache.ForumKey(selectedPost.Forum))
+	return err
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/cache"
+	"github.com/forum-api-back/internal/pkg/cache/lru"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/post"
+)
+
+func TestCachedRepositoryDropsForum(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(ctx context.Context, r post.Repository) error
+	}{
+		{"create", func(ctx context.Context, r post.Repository) error {
+			_, err := r.CreateNewPostsById(ctx, 2, "f", []*models.PostCreate{{Author: "bob"}})
+			return err
+		}},
+		{"delete", func(ctx context.Context, r post.Repository) error {
+			return r.DeletePostById(ctx, 3)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memoryRepo, _ := newTestRepository(t)
+			hotCache := lru.NewCache(10, time.Hour)
+			r := NewCachedRepository(memoryRepo, hotCache)
+			ctx := context.Background()
+			hotCache.Set(ctx, cache.ForumKey("f"), models.Forum{Slug: "f"})
+
+			if err := tt.change(ctx, r); err != nil {
+				t.Fatal(err)
+			}
+			var cachedForum models.Forum
+			if hotCache.Get(ctx, cache.ForumKey("f"), &cachedForum) {
+				t.Errorf("forum %+v with stale counters is still cached", cachedForum)
+			}
+		})
+	}
+}
@@ -0,0 +1,368 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/post"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+	"github.com/forum-api-back/pkg/errors"
+)
+
+type MemoryRepository struct {
+	storage *memory.Storage
+}
+
+func NewSessionMemoryRepository(storage *memory.Storage) post.Repository {
+	return &MemoryRepository{
+		storage: storage,
+	}
+}
+
+func (r *MemoryRepository) CreateNewPostsById(ctx context.Context, threadId uint64, forumSlug string,
+	posts []*models.PostCreate) ([]*models.Post, error) {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	selectedForum, ok := r.storage.Forums[memory.Key(forumSlug)]
+	if !ok {
+		return nil, errors.ErrForumNotFound
+	}
+	selectedThread, ok := r.storage.Threads[threadId]
+	if !ok {
+		return nil, errors.ErrThreadNotFound
+	}
+	switch {
+	case selectedThread.IsArchived:
+		return nil, errors.ErrThreadArchived
+	case selectedForum.IsArchived:
+		return nil, errors.ErrForumArchived
+	}
+
+	parentThreads := make(map[uint64]uint64)
+	existingAuthors := make(map[string]bool)
+	for _, postInfo := range posts {
+		if parentPost, ok := r.storage.Posts[postInfo.Parent]; ok {
+			parentThreads[parentPost.Id] = parentPost.Thread
+		}
+		if _, ok := r.storage.UserByNickName(postInfo.Author); ok {
+			existingAuthors[memory.Key(postInfo.Author)] = true
+		}
+	}
+	if err := checkPostsBatch(threadId, posts, parentThreads, existingAuthors); err != nil {
+		return nil, err
+	}
+
+	dateCreated := memory.Now()
+	newPosts := make([]*models.Post, len(posts))
+	for i, postInfo := range posts {
+		newPost := &memory.Post{
+			Post: models.Post{
+				Id:          r.storage.NextPostId(),
+				Parent:      postInfo.Parent,
+				Author:      postInfo.Author,
+				Message:     postInfo.Message,
+				Forum:       forumSlug,
+				Thread:      threadId,
+				DateCreated: dateCreated,
+			},
+		}
+		if postInfo.Parent == 0 {
+			newPost.PathOfNesting = []uint64{newPost.Id}
+		} else {
+			parentPath := r.storage.Posts[postInfo.Parent].PathOfNesting
+			newPost.PathOfNesting = make([]uint64, len(parentPath), len(parentPath)+1)
+			copy(newPost.PathOfNesting, parentPath)
+			newPost.PathOfNesting = append(newPost.PathOfNesting, newPost.Id)
+		}
+
+		r.storage.Posts[newPost.Id] = newPost
+		r.storage.ThreadPosts[threadId] = append(r.storage.ThreadPosts[threadId], newPost.Id)
+		selectedForum.Posts++
+		r.storage.AddAuthor(postInfo.Author, forumSlug)
+
+		copiedPost := newPost.Post
+		newPosts[i] = &copiedPost
+	}
+
+	return newPosts, nil
+}
+
+func (r *MemoryRepository) SelectPostById(ctx context.Context, postId uint64) (*models.Post, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	selectedPost, ok := r.storage.Posts[postId]
+	if !ok {
+		return nil, errors.ErrPostNotFound
+	}
+
+	copiedPost := selectedPost.Post
+	return &copiedPost, nil
+}
+
+func (r *MemoryRepository) SelectPostsById(ctx context.Context, threadId uint64, paginator *models.PostPaginator) ([]*models.Post, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	threadPosts := make([]*memory.Post, 0, len(r.storage.ThreadPosts[threadId]))
+	for _, postId := range r.storage.ThreadPosts[threadId] {
+		threadPosts = append(threadPosts, r.storage.Posts[postId])
+	}
+
+	var selectedPosts []*memory.Post
+	switch paginator.Sort {
+	case "flat":
+		selectedPosts = r.selectFlat(threadPosts, paginator)
+	case "tree":
+		selectedPosts = r.selectTree(threadPosts, paginator)
+	case "parent_tree":
+		selectedPosts = r.selectParentTree(threadPosts, paginator)
+	default:
+		return nil, errors.ErrBadArguments
+	}
+
+	posts := make([]*models.Post, 0, len(selectedPosts))
+	for _, selectedPost := range selectedPosts {
+		copiedPost := selectedPost.Post
+		posts = append(posts, &copiedPost)
+	}
+
+	return posts, nil
+}
+
+func (r *MemoryRepository) selectFlat(threadPosts []*memory.Post,
+	paginator *models.PostPaginator) []*memory.Post {
+	selectedPosts := make([]*memory.Post, 0)
+	for _, threadPost := range threadPosts {
+		if paginator.Since != 0 {
+			if paginator.SortOrder && threadPost.Id >= paginator.Since ||
+				!paginator.SortOrder && threadPost.Id <= paginator.Since {
+				continue
+			}
+		}
+		selectedPosts = append(selectedPosts, threadPost)
+	}
+
+	sort.Slice(selectedPosts, func(i, j int) bool {
+		if paginator.SortOrder {
+			return selectedPosts[i].Id > selectedPosts[j].Id
+		}
+		return selectedPosts[i].Id < selectedPosts[j].Id
+	})
+
+	return limitPosts(selectedPosts, paginator.Limit)
+}
+
+func (r *MemoryRepository) selectTree(threadPosts []*memory.Post,
+	paginator *models.PostPaginator) []*memory.Post {
+	var sincePath []uint64
+	if paginator.Since != 0 {
+		sincePost, ok := r.storage.Posts[paginator.Since]
+		if !ok {
+			return []*memory.Post{}
+		}
+		sincePath = sincePost.PathOfNesting
+	}
+
+	selectedPosts := make([]*memory.Post, 0)
+	for _, threadPost := range threadPosts {
+		if sincePath != nil {
+			compare := memory.ComparePaths(threadPost.PathOfNesting, sincePath)
+			if paginator.SortOrder && compare >= 0 || !paginator.SortOrder && compare <= 0 {
+				continue
+			}
+		}
+		selectedPosts = append(selectedPosts, threadPost)
+	}
+
+	sort.Slice(selectedPosts, func(i, j int) bool {
+		compare := memory.ComparePaths(selectedPosts[i].PathOfNesting, selectedPosts[j].PathOfNesting)
+		if paginator.SortOrder {
+			return compare > 0
+		}
+		return compare < 0
+	})
+
+	return limitPosts(selectedPosts, paginator.Limit)
+}
+
+func (r *MemoryRepository) selectParentTree(threadPosts []*memory.Post,
+	paginator *models.PostPaginator) []*memory.Post {
+	var sinceRoot uint64
+	if paginator.Since != 0 {
+		sincePost, ok := r.storage.Posts[paginator.Since]
+		if !ok {
+			return []*memory.Post{}
+		}
+		sinceRoot = sincePost.PathOfNesting[0]
+	}
+
+	roots := make([]*memory.Post, 0)
+	for _, threadPost := range threadPosts {
+		if threadPost.Parent != 0 {
+			continue
+		}
+		if paginator.Since != 0 {
+			if paginator.SortOrder && threadPost.Id >= sinceRoot ||
+				!paginator.SortOrder && threadPost.Id <= sinceRoot {
+				continue
+			}
+		}
+		roots = append(roots, threadPost)
+	}
+
+	sort.Slice(roots, func(i, j int) bool {
+		if paginator.SortOrder {
+			return roots[i].Id > roots[j].Id
+		}
+		return roots[i].Id < roots[j].Id
+	})
+	roots = limitPosts(roots, paginator.Limit)
+
+	rootOrder := make(map[uint64]int, len(roots))
+	for i, root := range roots {
+		rootOrder[root.Id] = i
+	}
+
+	selectedPosts := make([]*memory.Post, 0)
+	for _, threadPost := range threadPosts {
+		if _, ok := rootOrder[threadPost.PathOfNesting[0]]; ok {
+			selectedPosts = append(selectedPosts, threadPost)
+		}
+	}
+
+	// Root branches follow the requested order, posts inside a branch are
+	// always ascending by path.
+	sort.Slice(selectedPosts, func(i, j int) bool {
+		iRoot := rootOrder[selectedPosts[i].PathOfNesting[0]]
+		jRoot := rootOrder[selectedPosts[j].PathOfNesting[0]]
+		if iRoot != jRoot {
+			return iRoot < jRoot
+		}
+		return memory.ComparePaths(selectedPosts[i].PathOfNesting, selectedPosts[j].PathOfNesting) < 0
+	})
+
+	return selectedPosts
+}
+
+func limitPosts(posts []*memory.Post, limit uint64) []*memory.Post {
+	if uint64(len(posts)) > limit {
+		return posts[:limit]
+	}
+	return posts
+}
+
+func (r *MemoryRepository) UpdatePostById(ctx context.Context, postId uint64, postInfo *models.PostUpdate,
+	editor string) error {
+	if postInfo.Message == "" {
+		return nil
+	}
+
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	selectedPost, ok := r.storage.Posts[postId]
+	if !ok {
+		return errors.ErrPostNotFound
+	}
+	if selectedPost.IsDeleted {
+		return errors.ErrPostDeleted
+	}
+
+	revisions := r.storage.PostRevisions[postId]
+	if len(revisions) == 0 {
+		revisions = append(revisions, &models.PostRevision{
+			Revision:   1,
+			Editor:     selectedPost.Author,
+			Message:    selectedPost.Message,
+			DateEdited: selectedPost.DateCreated,
+		})
+	}
+	r.storage.PostRevisions[postId] = append(revisions, &models.PostRevision{
+		Revision:   uint64(len(revisions)) + 1,
+		Editor:     editor,
+		Message:    postInfo.Message,
+		DateEdited: memory.Now(),
+	})
+
+	selectedPost.Message = postInfo.Message
+	selectedPost.IsEdited = true
+
+	return nil
+}
+
+func (r *MemoryRepository) SelectRevisionsById(ctx context.Context, postId uint64) ([]*models.PostRevision, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	revisions := make([]*models.PostRevision, 0, len(r.storage.PostRevisions[postId]))
+	for _, revision := range r.storage.PostRevisions[postId] {
+		copiedRevision := *revision
+		revisions = append(revisions, &copiedRevision)
+	}
+
+	return revisions, nil
+}
+
+func (r *MemoryRepository) DeletePostById(ctx context.Context, postId uint64) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	selectedPost, ok := r.storage.Posts[postId]
+	if !ok {
+		return errors.ErrPostNotFound
+	}
+	if selectedPost.IsDeleted {
+		return errors.ErrPostDeleted
+	}
+
+	if r.hasReplies(selectedPost) {
+		selectedPost.Message = ""
+		selectedPost.IsDeleted = true
+		delete(r.storage.PostRevisions, selectedPost.Id)
+		r.storage.Forums[memory.Key(selectedPost.Forum)].Posts--
+		r.storage.RemoveAuthor(selectedPost.Author, selectedPost.Forum)
+		return nil
+	}
+
+	r.removePost(selectedPost)
+	for parentId := selectedPost.Parent; parentId != 0; {
+		parentPost := r.storage.Posts[parentId]
+		if !parentPost.IsDeleted || r.hasReplies(parentPost) {
+			break
+		}
+		r.removePost(parentPost)
+		parentId = parentPost.Parent
+	}
+
+	return nil
+}
+
+func (r *MemoryRepository) hasReplies(selectedPost *memory.Post) bool {
+	for _, postId := range r.storage.ThreadPosts[selectedPost.Thread] {
+		if r.storage.Posts[postId].Parent == selectedPost.Id {
+			return true
+		}
+	}
+	return false
+}
+
+// removePost deletes a post like the DELETE statement and its triggers do.
+func (r *MemoryRepository) removePost(selectedPost *memory.Post) {
+	delete(r.storage.Posts, selectedPost.Id)
+	delete(r.storage.PostRevisions, selectedPost.Id)
+	threadPosts := r.storage.ThreadPosts[selectedPost.Thread]
+	for i, postId := range threadPosts {
+		if postId == selectedPost.Id {
+			r.storage.ThreadPosts[selectedPost.Thread] = append(threadPosts[:i], threadPosts[i+1:]...)
+			break
+		}
+	}
+
+	if !selectedPost.IsDeleted {
+		r.storage.Forums[memory.Key(selectedPost.Forum)].Posts--
+		r.storage.RemoveAuthor(selectedPost.Author, selectedPost.Forum)
+	}
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/post"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+	"github.com/forum-api-back/pkg/errors"
+)
+
+// newTestRepository returns a repository over forum "f" with threads 1 and
+// 2 of "alice" and, in thread 1, the posts
+//
+//	1        2        3
+//	├── 4    └── 7
+//	│   └── 6
+//	└── 5
+func newTestRepository(t *testing.T) (post.Repository, *memory.Storage) {
+	t.Helper()
+
+	storage := memory.NewStorage()
+	for _, nickname := range []string{"alice", "bob"} {
+		storage.Users[nickname] = &models.User{NickName: nickname, Email: nickname + "@example.com"}
+	}
+	storage.Forums["f"] = &models.Forum{Title: "Forum", AuthorNickName: "alice", Slug: "f"}
+	for i := 0; i < 2; i++ {
+		threadId := storage.NextThreadId()
+		storage.Threads[threadId] = &models.Thread{Id: threadId, AuthorNickName: "alice", Forum: "f"}
+		storage.Forums["f"].Threads++
+		storage.AddAuthor("alice", "f")
+	}
+
+	r := NewSessionMemoryRepository(storage)
+	batches := [][]*models.PostCreate{
+		{{Author: "alice"}, {Author: "bob"}, {Author: "alice"}},
+		{{Parent: 1, Author: "bob"}, {Parent: 1, Author: "alice"}},
+		{{Parent: 4, Author: "bob"}, {Parent: 2, Author: "alice"}},
+	}
+	for _, batch := range batches {
+		if _, err := r.CreateNewPostsById(context.Background(), 1, "f", batch); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return r, storage
+}
+
+func postIds(posts []*models.Post) []uint64 {
+	ids := make([]uint64, len(posts))
+	for i, selectedPost := range posts {
+		ids[i] = selectedPost.Id
+	}
+	return ids
+}
+
+func TestCreateNewPostsPathOfNesting(t *testing.T) {
+	_, storage := newTestRepository(t)
+
+	want := map[uint64][]uint64{
+		1: {1},
+		2: {2},
+		3: {3},
+		4: {1, 4},
+		5: {1, 5},
+		6: {1, 4, 6},
+		7: {2, 7},
+	}
+	for postId, path := range want {
+		if got := storage.Posts[postId].PathOfNesting; !reflect.DeepEqual(got, path) {
+			t.Errorf("path of post %d = %v, want %v", postId, got, path)
+		}
+	}
+}
+
+func TestCreateNewPostsCounters(t *testing.T) {
+	r, storage := newTestRepository(t)
+
+	if got := storage.Forums["f"].Posts; got != 7 {
+		t.Errorf("forum posts = %d, want 7", got)
+	}
+	if got := storage.Authors["f"]; !reflect.DeepEqual(got, map[string]bool{"alice": true, "bob": true}) {
+		t.Errorf("forum authors = %v, want alice and bob", got)
+	}
+
+	_, err := r.CreateNewPostsById(context.Background(), 2, "f", []*models.PostCreate{{Author: "Bob"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := storage.Forums["f"].Posts; got != 8 {
+		t.Errorf("forum posts = %d, want 8", got)
+	}
+	if got := len(storage.Authors["f"]); got != 2 {
+		t.Errorf("forum authors = %v, want bob counted once", storage.Authors["f"])
+	}
+}
+
+func TestCreateNewPostsRejectsBatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		posts []*models.PostCreate
+		err   *errors.Error
+	}{
+		{"unknown parent", []*models.PostCreate{{Author: "alice"}, {Parent: 42, Author: "alice"}}, errors.ErrParentPostNotFound},
+		{"parent in another thread", []*models.PostCreate{{Parent: 8, Author: "alice"}}, errors.ErrParentPostInAnotherThread},
+		{"unknown author", []*models.PostCreate{{Author: "carol"}}, errors.ErrUserNotFound},
+		{"unknown author and parent", []*models.PostCreate{{Author: "carol"}, {Parent: 42, Author: "alice"}}, errors.ErrParentPostNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, storage := newTestRepository(t)
+			ctx := context.Background()
+			if _, err := r.CreateNewPostsById(ctx, 2, "f", []*models.PostCreate{{Author: "alice"}}); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := r.CreateNewPostsById(ctx, 1, "f", tt.posts)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("CreateNewPostsById() error = %v, want %v", err, tt.err)
+			}
+			if got := storage.Forums["f"].Posts; got != 8 {
+				t.Errorf("forum posts = %d, want 8 after a rejected batch", got)
+			}
+		})
+	}
+}
+
+func TestSelectPostsById(t *testing.T) {
+	tests := []struct {
+		name      string
+		paginator models.PostPaginator
+		want      []uint64
+	}{
+		{"flat", models.PostPaginator{Sort: "flat", Limit: 100}, []uint64{1, 2, 3, 4, 5, 6, 7}},
+		{"flat desc", models.PostPaginator{Sort: "flat", Limit: 3, SortOrder: true}, []uint64{7, 6, 5}},
+		{"flat since", models.PostPaginator{Sort: "flat", Limit: 100, Since: 4}, []uint64{5, 6, 7}},
+		{"flat since desc", models.PostPaginator{Sort: "flat", Limit: 100, Since: 4, SortOrder: true}, []uint64{3, 2, 1}},
+		{"tree", models.PostPaginator{Sort: "tree", Limit: 100}, []uint64{1, 4, 6, 5, 2, 7, 3}},
+		{"tree desc", models.PostPaginator{Sort: "tree", Limit: 100, SortOrder: true}, []uint64{3, 7, 2, 5, 6, 4, 1}},
+		{"tree since", models.PostPaginator{Sort: "tree", Limit: 2, Since: 6}, []uint64{5, 2}},
+		{"tree since desc", models.PostPaginator{Sort: "tree", Limit: 100, Since: 5, SortOrder: true}, []uint64{6, 4, 1}},
+		{"tree unknown since", models.PostPaginator{Sort: "tree", Limit: 100, Since: 42}, []uint64{}},
+		{"parent tree", models.PostPaginator{Sort: "parent_tree", Limit: 2}, []uint64{1, 4, 6, 5, 2, 7}},
+		{"parent tree desc", models.PostPaginator{Sort: "parent_tree", Limit: 2, SortOrder: true}, []uint64{3, 2, 7}},
+		{"parent tree since", models.PostPaginator{Sort: "parent_tree", Limit: 1, Since: 4}, []uint64{2, 7}},
+		{"parent tree since desc", models.PostPaginator{Sort: "parent_tree", Limit: 5, Since: 7, SortOrder: true}, []uint64{1, 4, 6, 5}},
+	}
+
+	r, _ := newTestRepository(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts, err := r.SelectPostsById(context.Background(), 1, &tt.paginator)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := postIds(posts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectPostsById() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectPostsByIdRejectsSort(t *testing.T) {
+	r, _ := newTestRepository(t)
+
+	_, err := r.SelectPostsById(context.Background(), 1, &models.PostPaginator{Sort: "random", Limit: 100})
+	if !errors.Is(err, errors.ErrBadArguments) {
+		t.Errorf("SelectPostsById() error = %v, want %v", err, errors.ErrBadArguments)
+	}
+}
+
+func TestDeletePostById(t *testing.T) {
+	tests := []struct {
+		name      string
+		deleted   []uint64
+		remaining []uint64
+		posts     uint64
+		authors   map[string]bool
+	}{
+		{"leaf", []uint64{6}, []uint64{1, 2, 3, 4, 5, 7}, 6, map[string]bool{"alice": true, "bob": true}},
+		{"post with replies is kept", []uint64{1}, []uint64{1, 2, 3, 4, 5, 6, 7}, 6, map[string]bool{"alice": true, "bob": true}},
+		{"deleted parents are removed with their last reply", []uint64{1, 4, 5, 6}, []uint64{2, 3, 7}, 3, map[string]bool{"alice": true, "bob": true}},
+		{"last posts of an author", []uint64{2, 4, 6}, []uint64{1, 2, 3, 5, 7}, 4, map[string]bool{"alice": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, storage := newTestRepository(t)
+			ctx := context.Background()
+			for _, postId := range tt.deleted {
+				if err := r.DeletePostById(ctx, postId); err != nil {
+					t.Fatalf("DeletePostById(%d) error = %v", postId, err)
+				}
+			}
+
+			posts, err := r.SelectPostsById(ctx, 1, &models.PostPaginator{Sort: "flat", Limit: 100})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := postIds(posts); !reflect.DeepEqual(got, tt.remaining) {
+				t.Errorf("remaining posts = %v, want %v", got, tt.remaining)
+			}
+			if got := storage.Forums["f"].Posts; got != tt.posts {
+				t.Errorf("forum posts = %d, want %d", got, tt.posts)
+			}
+			if got := storage.Authors["f"]; !reflect.DeepEqual(got, tt.authors) {
+				t.Errorf("forum authors = %v, want %v", got, tt.authors)
+			}
+		})
+	}
+}
+
+func TestDeletePostByIdTwice(t *testing.T) {
+	r, _ := newTestRepository(t)
+	ctx := context.Background()
+
+	if err := r.DeletePostById(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.DeletePostById(ctx, 1); !errors.Is(err, errors.ErrPostDeleted) {
+		t.Errorf("DeletePostById() error = %v, want %v", err, errors.ErrPostDeleted)
+	}
+	if err := r.DeletePostById(ctx, 42); !errors.Is(err, errors.ErrPostNotFound) {
+		t.Errorf("DeletePostById() error = %v, want %v", err, errors.ErrPostNotFound)
+	}
+}
+
+func TestCreateNewPostsRejectsArchived(t *testing.T) {
+	tests := []struct {
+		name           string
+		threadArchived bool
+		forumArchived  bool
+		err            *errors.Error
+	}{
+		{"archived thread", true, false, errors.ErrThreadArchived},
+		{"thread of an archived forum", false, true, errors.ErrForumArchived},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, storage := newTestRepository(t)
+			storage.Threads[1].IsArchived = tt.threadArchived
+			storage.Forums["f"].IsArchived = tt.forumArchived
+
+			_, err := r.CreateNewPostsById(context.Background(), 1, "f", []*models.PostCreate{{Author: "alice"}})
+			if !errors.Is(err, tt.err) {
+				t.Errorf("CreateNewPostsById() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/post"
+	"github.com/forum-api-back/pkg/logger"
+	"github.com/forum-api-back/pkg/metrics"
+)
+
+// MeasuredRepository observes how long every method takes and logs the
+// calls taking slowQuery or longer.
+type MeasuredRepository struct {
+	repo      post.Repository
+	durations *metrics.HistogramVec
+	slowQuery time.Duration
+}
+
+// NewMeasuredRepository observes the durations of the methods of repo into
+// durations, labeled with the repository and the method. Calls taking
+// slowQuery or longer are logged as well, unless it is zero.
+func NewMeasuredRepository(repo post.Repository, durations *metrics.HistogramVec,
+	slowQuery time.Duration) post.Repository {
+	return &MeasuredRepository{
+		repo:      repo,
+		durations: durations,
+		slowQuery: slowQuery,
+	}
+}
+
+func (r *MeasuredRepository) CreateNewPostsById(ctx context.Context, threadId uint64, forumSlug string,
+	posts []*models.PostCreate) ([]*models.Post, error) {
+	defer r.observe(ctx, "CreateNewPostsById", time.Now())
+	return r.repo.CreateNewPostsById(ctx, threadId, forumSlug, posts)
+}
+
+func (r *MeasuredRepository) SelectPostById(ctx context.Context, postId uint64) (*models.Post, error) {
+	defer r.observe(ctx, "SelectPostById", time.Now())
+	return r.repo.SelectPostById(ctx, postId)
+}
+
+func (r *MeasuredRepository) SelectPostsById(ctx context.Context, threadId uint64,
+	paginator *models.PostPaginator) ([]*models.Post, error) {
+	defer r.observe(ctx, "SelectPostsById", time.Now())
+	return r.repo.SelectPostsById(ctx, threadId, paginator)
+}
+
+func (r *MeasuredRepository) UpdatePostById(ctx context.Context, postId uint64, postInfo *models.PostUpdate,
+	editor string) error {
+	defer r.observe(ctx, "UpdatePostById", time.Now())
+	return r.repo.UpdatePostById(ctx, postId, postInfo, editor)
+}
+
+func (r *MeasuredRepository) SelectRevisionsById(ctx context.Context, postId uint64) ([]*models.PostRevision, error) {
+	defer r.observe(ctx, "SelectRevisionsById", time.Now())
+	return r.repo.SelectRevisionsById(ctx, postId)
+}
+
+func (r *MeasuredRepository) DeletePostById(ctx context.Context, postId uint64) error {
+	defer r.observe(ctx, "DeletePostById", time.Now())
+	return r.repo.DeletePostById(ctx, postId)
+}
+
+func (r *MeasuredRepository) observe(ctx context.Context, method string, start time.Time) {
+	elapsed := time.Since(start)
+	r.durations.With("post", method).Observe(elapsed.Seconds())
+	if r.slowQuery > 0 && elapsed >= r.slowQuery {
+		logger.FromContext(ctx).Warn("slow query", "repository", "post", "method", method, "duration", elapsed)
+	}
+}
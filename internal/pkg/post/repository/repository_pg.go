@@ -1,13 +1,16 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
 	"strings"
 
 	"github.com/forum-api-back/internal/pkg/models"
 	"github.com/forum-api-back/internal/pkg/post"
 	"github.com/forum-api-back/pkg/errors"
+	"github.com/forum-api-back/pkg/tools/sql_utils"
+
+	"github.com/lib/pq"
 )
 
 type PostgresqlRepository struct {
@@ -20,36 +23,78 @@ func NewSessionPostgresqlRepository(db *sql.DB) post.Repository {
 	}
 }
 
-func (r *PostgresqlRepository) CreateNewPostsById(threadId uint64, forumSlug string,
+func (r *PostgresqlRepository) CreateNewPostsById(ctx context.Context, threadId uint64, forumSlug string,
 	posts []*models.PostCreate) ([]*models.Post, error) {
 	countPosts := len(posts)
-	insertData := make([]string, countPosts)
-	for i := 0; i < countPosts; i++ {
-		insertData[i] = fmt.Sprintf(
-			"(%d, '%s', '%s', '%s', %d)",
-			posts[i].Parent,
-			posts[i].Author,
-			posts[i].Message,
-			forumSlug,
-			threadId,
-		)
+	parents := make([]int64, countPosts)
+	authors := make([]string, countPosts)
+	messages := make([]string, countPosts)
+	for i, postInfo := range posts {
+		parents[i] = int64(postInfo.Parent)
+		authors[i] = postInfo.Author
+		messages[i] = postInfo.Message
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, sql_utils.Error(err, errors.ErrPostNotFound)
 	}
+	defer tx.Rollback()
 
-	rows, err := r.db.Query(
-		"INSERT INTO posts (parent_message_id, author_nickname, message, " +
-			"forum_slug, thread_id) VALUES " +
-			strings.Join(insertData, ", ") +
-			" RETURNING id, date_created",
+	// The thread, its forum and the parents are locked, so the batch is
+	// checked and inserted against the same state of the tree.
+	var isThreadArchived, isForumArchived bool
+	row := tx.QueryRowContext(ctx,
+		"SELECT t.id, t.is_archived, f.is_archived "+
+			"FROM threads t "+
+			"JOIN forums f ON (f.slug = t.forum_slug) "+
+			"WHERE t.id = $1 "+
+			"FOR SHARE",
+		threadId,
 	)
+	if err := row.Scan(&threadId, &isThreadArchived, &isForumArchived); err != nil {
+		return nil, sql_utils.Error(err, errors.ErrThreadNotFound)
+	}
+	switch {
+	case isThreadArchived:
+		return nil, errors.ErrThreadArchived
+	case isForumArchived:
+		return nil, errors.ErrForumArchived
+	}
 
+	parentThreads, err := selectParentThreads(ctx, tx, parents)
 	if err != nil {
-		if strings.Contains(err.Error(), "posts_author_nickname") {
-			return nil, errors.ErrUserNotFound
-		}
+		return nil, sql_utils.Error(err, errors.ErrPostNotFound)
+	}
+	existingAuthors, err := selectExistingAuthors(ctx, tx, authors)
+	if err != nil {
+		return nil, sql_utils.Error(err, errors.ErrUserNotFound)
+	}
+	if err := checkPostsBatch(threadId, posts, parentThreads, existingAuthors); err != nil {
+		return nil, err
+	}
 
-		return nil, errors.ErrInternalError
+	// The whole batch is sent as three array parameters, so its size
+	// doesn't depend on the bind parameters limit and it still takes
+	// a single round-trip.
+	rows, err := tx.QueryContext(ctx,
+		"INSERT INTO posts (parent_message_id, author_nickname, message, "+
+			"	forum_slug, thread_id) "+
+			"SELECT new_posts.parent_message_id, new_posts.author_nickname, new_posts.message, $4, $5 "+
+			"FROM unnest($1::INTEGER[], $2::CITEXT[], $3::TEXT[]) "+
+			"	WITH ORDINALITY AS new_posts(parent_message_id, author_nickname, message, position) "+
+			"ORDER BY new_posts.position "+
+			"RETURNING id, date_created",
+		pq.Array(parents),
+		pq.Array(authors),
+		pq.Array(messages),
+		forumSlug,
+		threadId,
+	)
+
+	if err != nil {
+		return nil, insertPostsError(err)
 	}
-	defer rows.Close()
 
 	newPosts := make([]*models.Post, countPosts, countPosts)
 	for i := 0; i < countPosts && rows.Next(); i++ {
@@ -66,18 +111,106 @@ func (r *PostgresqlRepository) CreateNewPostsById(threadId uint64, forumSlug str
 		)
 
 		if err != nil {
-			return nil, errors.ErrInternalError
+			rows.Close()
+			return nil, insertPostsError(err)
 		}
 
 	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return nil, insertPostsError(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, insertPostsError(err)
+	}
 
 	return newPosts, nil
 }
 
-func (r *PostgresqlRepository) SelectPostById(postId uint64) (*models.Post, error) {
-	row := r.db.QueryRow(
+func selectParentThreads(ctx context.Context, tx *sql.Tx, parents []int64) (map[uint64]uint64, error) {
+	parentThreads := make(map[uint64]uint64)
+	rows, err := tx.QueryContext(ctx,
+		"SELECT id, thread_id "+
+			"FROM posts "+
+			"WHERE id = ANY($1::INTEGER[]) "+
+			"FOR SHARE",
+		pq.Array(parents),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postId, threadId uint64
+		if err := rows.Scan(&postId, &threadId); err != nil {
+			return nil, err
+		}
+		parentThreads[postId] = threadId
+	}
+
+	return parentThreads, rows.Err()
+}
+
+func selectExistingAuthors(ctx context.Context, tx *sql.Tx, authors []string) (map[string]bool, error) {
+	existingAuthors := make(map[string]bool)
+	rows, err := tx.QueryContext(ctx,
+		"SELECT nickname "+
+			"FROM users "+
+			"WHERE nickname = ANY($1::CITEXT[])",
+		pq.Array(authors),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var nickname string
+		if err := rows.Scan(&nickname); err != nil {
+			return nil, err
+		}
+		existingAuthors[strings.ToLower(nickname)] = true
+	}
+
+	return existingAuthors, rows.Err()
+}
+
+// insertPostsError maps the failures raised by the posts triggers and
+// foreign keys to domain errors.
+func insertPostsError(err error) error {
+	pqErr, ok := err.(*pq.Error)
+	if !ok {
+		return sql_utils.Error(err, errors.ErrPostNotFound)
+	}
+
+	switch pqErr.Code {
+	case sql_utils.RaiseException:
+		switch {
+		case strings.HasPrefix(pqErr.Message, "Parent post not found"):
+			return errors.ErrParentPostNotFound.Wrap(err)
+		case strings.HasPrefix(pqErr.Message, "Thread not found"):
+			return errors.ErrParentPostInAnotherThread.Wrap(err)
+		}
+	case sql_utils.ForeignKeyViolation:
+		switch pqErr.Constraint {
+		case "posts_author_nickname_fkey", "authors_user_nickname_fkey":
+			return errors.ErrUserNotFound.Wrap(err)
+		case "posts_thread_id_fkey":
+			return errors.ErrThreadNotFound.Wrap(err)
+		case "posts_forum_slug_fkey", "authors_forum_slug_fkey":
+			return errors.ErrForumNotFound.Wrap(err)
+		}
+	}
+
+	return sql_utils.Error(err, errors.ErrPostNotFound)
+}
+
+func (r *PostgresqlRepository) SelectPostById(ctx context.Context, postId uint64) (*models.Post, error) {
+	row := r.db.QueryRowContext(ctx,
 		"SELECT id, parent_message_id, author_nickname, message, "+
-			"is_edited, forum_slug, thread_id, date_created "+
+			"is_edited, forum_slug, thread_id, date_created, is_deleted "+
 			"FROM posts "+
 			"WHERE id = $1",
 		postId,
@@ -93,16 +226,17 @@ func (r *PostgresqlRepository) SelectPostById(postId uint64) (*models.Post, erro
 		&selectedPost.Forum,
 		&selectedPost.Thread,
 		&selectedPost.DateCreated,
+		&selectedPost.IsDeleted,
 	)
 
 	if err != nil {
-		return nil, errors.ErrPostNotFound
+		return nil, sql_utils.Error(err, errors.ErrPostNotFound)
 	}
 
 	return selectedPost, nil
 }
 
-func (r *PostgresqlRepository) SelectPostsById(threadId uint64, paginator *models.PostPaginator) ([]*models.Post, error) {
+func (r *PostgresqlRepository) SelectPostsById(ctx context.Context, threadId uint64, paginator *models.PostPaginator) ([]*models.Post, error) {
 	var orderSort, orderCompare string
 	if paginator.SortOrder {
 		orderSort = " DESC "
@@ -117,9 +251,9 @@ func (r *PostgresqlRepository) SelectPostsById(threadId uint64, paginator *model
 	switch paginator.Sort {
 	case "flat":
 		if paginator.Since != 0 {
-			rows, err = r.db.Query(
+			rows, err = r.db.QueryContext(ctx,
 				"SELECT id, parent_message_id, author_nickname, message, "+
-					"is_edited, forum_slug, thread_id, date_created "+
+					"is_edited, forum_slug, thread_id, date_created, is_deleted "+
 					"FROM posts "+
 					"WHERE (thread_id = $1 AND id"+orderCompare+"$2) "+
 					"ORDER BY id "+orderSort+
@@ -129,11 +263,11 @@ func (r *PostgresqlRepository) SelectPostsById(threadId uint64, paginator *model
 				paginator.Limit,
 			)
 		} else {
-			rows, err = r.db.Query(
+			rows, err = r.db.QueryContext(ctx,
 				"SELECT id, parent_message_id, author_nickname, message, "+
-					"is_edited, forum_slug, thread_id, date_created "+
+					"is_edited, forum_slug, thread_id, date_created, is_deleted "+
 					"FROM posts "+
-					"WHERE thread_id = $1"+
+					"WHERE thread_id = $1 "+
 					"ORDER BY id "+orderSort+
 					"LIMIT $2",
 				threadId,
@@ -142,9 +276,9 @@ func (r *PostgresqlRepository) SelectPostsById(threadId uint64, paginator *model
 		}
 	case "tree":
 		if paginator.Since != 0 {
-			rows, err = r.db.Query(
+			rows, err = r.db.QueryContext(ctx,
 				"SELECT p1.id, p1.parent_message_id, p1.author_nickname, p1.message, "+
-					"p1.is_edited, p1.forum_slug, p1.thread_id, p1.date_created "+
+					"p1.is_edited, p1.forum_slug, p1.thread_id, p1.date_created, p1.is_deleted "+
 					"FROM posts p1 "+
 					"JOIN posts p2 ON (p2.id = $2) "+
 					"WHERE (p1.thread_id = $1 AND p1.path_of_nesting"+orderCompare+"p2.path_of_nesting) "+
@@ -156,9 +290,9 @@ func (r *PostgresqlRepository) SelectPostsById(threadId uint64, paginator *model
 				paginator.Limit,
 			)
 		} else {
-			rows, err = r.db.Query(
+			rows, err = r.db.QueryContext(ctx,
 				"SELECT p1.id, p1.parent_message_id, p1.author_nickname, p1.message, "+
-					"p1.is_edited, p1.forum_slug, p1.thread_id, p1.date_created "+
+					"p1.is_edited, p1.forum_slug, p1.thread_id, p1.date_created, p1.is_deleted "+
 					"FROM posts p1 "+
 					"WHERE (p1.thread_id = $1) "+
 					"ORDER BY p1.path_of_nesting[1]"+orderSort+", "+
@@ -170,9 +304,9 @@ func (r *PostgresqlRepository) SelectPostsById(threadId uint64, paginator *model
 		}
 	case "parent_tree":
 		if paginator.Since != 0 {
-			rows, err = r.db.Query(
+			rows, err = r.db.QueryContext(ctx,
 				"SELECT p1.id, p1.parent_message_id, p1.author_nickname, p1.message, "+
-					"p1.is_edited, p1.forum_slug, p1.thread_id, p1.date_created "+
+					"p1.is_edited, p1.forum_slug, p1.thread_id, p1.date_created, p1.is_deleted "+
 					"FROM posts p1 "+
 					"WHERE p1.path_of_nesting[1] IN ( "+
 					"	SELECT id "+
@@ -193,9 +327,9 @@ func (r *PostgresqlRepository) SelectPostsById(threadId uint64, paginator *model
 				paginator.Limit,
 			)
 		} else {
-			rows, err = r.db.Query(
+			rows, err = r.db.QueryContext(ctx,
 				"SELECT p1.id, p1.parent_message_id, p1.author_nickname, p1.message, "+
-					"p1.is_edited, p1.forum_slug, p1.thread_id, p1.date_created "+
+					"p1.is_edited, p1.forum_slug, p1.thread_id, p1.date_created, p1.is_deleted "+
 					"FROM posts p1 "+
 					"WHERE p1.path_of_nesting[1] IN ( "+
 					"	SELECT id "+
@@ -210,11 +344,11 @@ func (r *PostgresqlRepository) SelectPostsById(threadId uint64, paginator *model
 			)
 		}
 	default:
-		return nil, errors.ErrPostNotFound
+		return nil, errors.ErrBadArguments
 	}
 
 	if err != nil {
-		return nil, errors.ErrPostNotFound
+		return nil, sql_utils.Error(err, errors.ErrPostNotFound)
 	}
 	defer rows.Close()
 
@@ -230,23 +364,77 @@ func (r *PostgresqlRepository) SelectPostsById(threadId uint64, paginator *model
 			&selectedPost.Forum,
 			&selectedPost.Thread,
 			&selectedPost.DateCreated,
+			&selectedPost.IsDeleted,
 		)
 		if err != nil {
-			return nil, errors.ErrPostNotFound
+			return nil, sql_utils.Error(err, errors.ErrPostNotFound)
 		}
 
 		posts = append(posts, selectedPost)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, sql_utils.Error(err, errors.ErrPostNotFound)
+	}
+
 	return posts, nil
 }
 
-func (r *PostgresqlRepository) UpdatePostById(postId uint64, postInfo *models.PostUpdate) error {
+func (r *PostgresqlRepository) UpdatePostById(ctx context.Context, postId uint64, postInfo *models.PostUpdate,
+	editor string) error {
 	if postInfo.Message == "" {
 		return nil
 	}
 
-	_, err := r.db.Exec(
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return sql_utils.Error(err, errors.ErrPostNotFound)
+	}
+	defer tx.Rollback()
+
+	var isDeleted bool
+	row := tx.QueryRowContext(ctx,
+		"SELECT is_deleted "+
+			"FROM posts "+
+			"WHERE id = $1 "+
+			"FOR UPDATE",
+		postId,
+	)
+	if err := row.Scan(&isDeleted); err != nil {
+		return sql_utils.Error(err, errors.ErrPostNotFound)
+	}
+	if isDeleted {
+		return errors.ErrPostDeleted
+	}
+
+	// The original message becomes the first revision on the first edit,
+	// so posts that are never edited don't cost a revision row.
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO post_revisions (post_id, revision, editor_nickname, message, date_edited) "+
+			"SELECT id, 1, author_nickname, message, date_created "+
+			"FROM posts "+
+			"WHERE id = $1 "+
+			"ON CONFLICT DO NOTHING",
+		postId,
+	)
+	if err != nil {
+		return sql_utils.Error(err, errors.ErrPostNotFound)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO post_revisions (post_id, revision, editor_nickname, message) "+
+			"SELECT $1, MAX(revision) + 1, $2, $3 "+
+			"FROM post_revisions "+
+			"WHERE post_id = $1",
+		postId,
+		editor,
+		postInfo.Message,
+	)
+	if err != nil {
+		return sql_utils.Error(err, errors.ErrPostNotFound)
+	}
+
+	_, err = tx.ExecContext(ctx,
 		"UPDATE posts SET "+
 			"message = $1, "+
 			"is_edited = true "+
@@ -254,9 +442,131 @@ func (r *PostgresqlRepository) UpdatePostById(postId uint64, postInfo *models.Po
 		postInfo.Message,
 		postId,
 	)
+	if err != nil {
+		return sql_utils.Error(err, errors.ErrPostNotFound)
+	}
+
+	return sql_utils.Error(tx.Commit(), errors.ErrPostNotFound)
+}
 
+func (r *PostgresqlRepository) SelectRevisionsById(ctx context.Context, postId uint64) ([]*models.PostRevision, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT revision, editor_nickname, message, date_edited "+
+			"FROM post_revisions "+
+			"WHERE post_id = $1 "+
+			"ORDER BY revision",
+		postId,
+	)
 	if err != nil {
-		return errors.ErrDataConflict
+		return nil, sql_utils.Error(err, errors.ErrPostNotFound)
+	}
+	defer rows.Close()
+
+	revisions := make([]*models.PostRevision, 0)
+	for rows.Next() {
+		revision := &models.PostRevision{}
+		err = rows.Scan(
+			&revision.Revision,
+			&revision.Editor,
+			&revision.Message,
+			&revision.DateEdited,
+		)
+		if err != nil {
+			return nil, sql_utils.Error(err, errors.ErrPostNotFound)
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, sql_utils.Error(err, errors.ErrPostNotFound)
+	}
+
+	return revisions, nil
+}
+
+func (r *PostgresqlRepository) DeletePostById(ctx context.Context, postId uint64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return sql_utils.Error(err, errors.ErrPostNotFound)
+	}
+	defer tx.Rollback()
+
+	// Replies lock their parents FOR SHARE, so none can be added to the
+	// post while it is being deleted.
+	var parentId uint64
+	var isDeleted bool
+	row := tx.QueryRowContext(ctx,
+		"SELECT parent_message_id, is_deleted "+
+			"FROM posts "+
+			"WHERE id = $1 "+
+			"FOR UPDATE",
+		postId,
+	)
+	if err := row.Scan(&parentId, &isDeleted); err != nil {
+		return sql_utils.Error(err, errors.ErrPostNotFound)
+	}
+	if isDeleted {
+		return errors.ErrPostDeleted
+	}
+
+	result, err := tx.ExecContext(ctx,
+		"UPDATE posts SET "+
+			"message = '', "+
+			"is_deleted = true "+
+			"WHERE id = $1 AND EXISTS ( "+
+			"	SELECT 1 FROM posts "+
+			"	WHERE parent_message_id = $1 "+
+			")",
+		postId,
+	)
+	if err != nil {
+		return sql_utils.Error(err, errors.ErrPostNotFound)
+	}
+	tombstoned, err := result.RowsAffected()
+	if err != nil {
+		return sql_utils.Error(err, errors.ErrPostNotFound)
+	}
+
+	// A tombstone keeps no trace of the deleted message.
+	if tombstoned != 0 {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM post_revisions WHERE post_id = $1", postId); err != nil {
+			return sql_utils.Error(err, errors.ErrPostNotFound)
+		}
+	}
+
+	if tombstoned == 0 {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM posts WHERE id = $1", postId); err != nil {
+			return sql_utils.Error(err, errors.ErrPostNotFound)
+		}
+		if err := pruneTombstones(ctx, tx, parentId); err != nil {
+			return sql_utils.Error(err, errors.ErrPostNotFound)
+		}
+	}
+
+	return sql_utils.Error(tx.Commit(), errors.ErrPostNotFound)
+}
+
+// pruneTombstones removes the tombstones up the tree from postId that
+// were only kept for the reply just removed.
+func pruneTombstones(ctx context.Context, tx *sql.Tx, postId uint64) error {
+	for postId != 0 {
+		row := tx.QueryRowContext(ctx,
+			"DELETE FROM posts "+
+				"WHERE id = $1 AND is_deleted AND NOT EXISTS ( "+
+				"	SELECT 1 FROM posts "+
+				"	WHERE parent_message_id = $1 "+
+				") "+
+				"RETURNING parent_message_id",
+			postId,
+		)
+		err := row.Scan(&postId)
+		switch {
+		case err == sql.ErrNoRows:
+			return nil
+		case err != nil:
+			return err
+		}
 	}
 
 	return nil
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/post"
+	"github.com/forum-api-back/pkg/tracing"
+)
+
+// TracedRepository records a span for every method called within a traced request.
+type TracedRepository struct {
+	repo post.Repository
+}
+
+func NewTracedRepository(repo post.Repository) post.Repository {
+	return &TracedRepository{
+		repo: repo,
+	}
+}
+
+func (r *TracedRepository) CreateNewPostsById(ctx context.Context, threadId uint64, forumSlug string,
+	posts []*models.PostCreate) (_ []*models.Post, err error) {
+	ctx, span := tracing.Start(ctx, "post.repository.CreateNewPostsById")
+	defer func() { span.End(err) }()
+	return r.repo.CreateNewPostsById(ctx, threadId, forumSlug, posts)
+}
+
+func (r *TracedRepository) SelectPostById(ctx context.Context, postId uint64) (_ *models.Post, err error) {
+	ctx, span := tracing.Start(ctx, "post.repository.SelectPostById")
+	defer func() { span.End(err) }()
+	return r.repo.SelectPostById(ctx, postId)
+}
+
+func (r *TracedRepository) SelectPostsById(ctx context.Context, threadId uint64,
+	paginator *models.PostPaginator) (_ []*models.Post, err error) {
+	ctx, span := tracing.Start(ctx, "post.repository.SelectPostsById")
+	defer func() { span.End(err) }()
+	return r.repo.SelectPostsById(ctx, threadId, paginator)
+}
+
+func (r *TracedRepository) UpdatePostById(ctx context.Context, postId uint64, postInfo *models.PostUpdate,
+	editor string) (err error) {
+	ctx, span := tracing.Start(ctx, "post.repository.UpdatePostById")
+	defer func() { span.End(err) }()
+	return r.repo.UpdatePostById(ctx, postId, postInfo, editor)
+}
+
+func (r *TracedRepository) SelectRevisionsById(ctx context.Context,
+	postId uint64) (_ []*models.PostRevision, err error) {
+	ctx, span := tracing.Start(ctx, "post.repository.SelectRevisionsById")
+	defer func() { span.End(err) }()
+	return r.repo.SelectRevisionsById(ctx, postId)
+}
+
+func (r *TracedRepository) DeletePostById(ctx context.Context, postId uint64) (err error) {
+	ctx, span := tracing.Start(ctx, "post.repository.DeletePostById")
+	defer func() { span.End(err) }()
+	return r.repo.DeletePostById(ctx, postId)
+}
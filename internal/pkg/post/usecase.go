@@ -1,10 +1,18 @@
 package post
 
-import "github.com/forum-api-back/internal/pkg/models"
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
 
 type UseCase interface {
-	CreateNewPosts(threadSlugOrId string, posts []*models.PostCreate) ([]*models.Post, error)
-	GetPostDetail(postId uint64, related map[string]bool) (*models.PostDetails, error)
-	GetPostsByThread(threadSlugOrId string, paginator *models.PostPaginator) ([]*models.Post, error)
-	UpdatePostDetails(postId uint64, postInfo *models.PostUpdate) (*models.Post, error)
+	CreateNewPosts(ctx context.Context, threadSlugOrId string, posts []*models.PostCreate) ([]*models.Post, error)
+	GetPostDetail(ctx context.Context, postId uint64, related map[string]bool) (*models.PostDetails, error)
+	GetPostsByThread(ctx context.Context, threadSlugOrId string, paginator *models.PostPaginator) ([]*models.Post, error)
+	UpdatePostDetails(ctx context.Context, postId uint64, postInfo *models.PostUpdate) (*models.Post, error)
+	DeletePost(ctx context.Context, postId uint64) (*models.Post, error)
+	GetPostHistory(ctx context.Context, postId uint64) ([]*models.PostRevision, error)
+	DiffPostRevisions(ctx context.Context, postId, from, to uint64) (*models.PostRevisionDiff, error)
+	RestorePostRevision(ctx context.Context, postId, revision uint64) (*models.Post, error)
 }
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"strings"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
+
+const (
+	diffEqual  = "equal"
+	diffInsert = "insert"
+	diffDelete = "delete"
+)
+
+// diffLines compares two messages line by line. The lines both messages
+// keep are their longest common subsequence, the rest are deleted from
+// the first message or inserted by the second one. It takes memory linear
+// in the number of lines, since messages have no size limit.
+func diffLines(from, to string) []*models.DiffLine {
+	fromLines := strings.Split(from, "\n")
+	toLines := strings.Split(to, "\n")
+
+	return appendDiff(make([]*models.DiffLine, 0, len(fromLines)+len(toLines)), fromLines, toLines)
+}
+
+// appendDiff appends the diff of from and to to lines. Past their common
+// prefix and suffix it splits from in halves and to where the longest
+// common subsequence crosses from one half to the other (Hirschberg's
+// algorithm), then diffs each half on its own.
+func appendDiff(lines []*models.DiffLine, from, to []string) []*models.DiffLine {
+	prefix := 0
+	for prefix < len(from) && prefix < len(to) && from[prefix] == to[prefix] {
+		lines = append(lines, &models.DiffLine{Op: diffEqual, Text: from[prefix]})
+		prefix++
+	}
+	from, to = from[prefix:], to[prefix:]
+
+	suffix := 0
+	for suffix < len(from) && suffix < len(to) && from[len(from)-1-suffix] == to[len(to)-1-suffix] {
+		suffix++
+	}
+	kept := from[len(from)-suffix:]
+	from, to = from[:len(from)-suffix], to[:len(to)-suffix]
+
+	switch {
+	case len(from) == 0:
+		lines = appendLines(lines, diffInsert, to)
+	case len(to) == 0:
+		lines = appendLines(lines, diffDelete, from)
+	case len(from) == 1:
+		lines = appendLine(lines, from[0], to)
+	default:
+		middle := len(from) / 2
+		forward := prefixLengths(from[:middle], to)
+		backward := suffixLengths(from[middle:], to)
+		split := 0
+		for j := range forward {
+			if forward[j]+backward[j] > forward[split]+backward[split] {
+				split = j
+			}
+		}
+		lines = appendDiff(lines, from[:middle], to[:split])
+		lines = appendDiff(lines, from[middle:], to[split:])
+	}
+
+	return appendLines(lines, diffEqual, kept)
+}
+
+// appendLine appends the diff of the single line from and to.
+func appendLine(lines []*models.DiffLine, from string, to []string) []*models.DiffLine {
+	for j, text := range to {
+		if text == from {
+			lines = appendLines(lines, diffInsert, to[:j])
+			lines = append(lines, &models.DiffLine{Op: diffEqual, Text: from})
+			return appendLines(lines, diffInsert, to[j+1:])
+		}
+	}
+
+	lines = append(lines, &models.DiffLine{Op: diffDelete, Text: from})
+	return appendLines(lines, diffInsert, to)
+}
+
+func appendLines(lines []*models.DiffLine, op string, texts []string) []*models.DiffLine {
+	for _, text := range texts {
+		lines = append(lines, &models.DiffLine{Op: op, Text: text})
+	}
+	return lines
+}
+
+// prefixLengths returns, for every j, the length of the longest common
+// subsequence of from and to[:j].
+func prefixLengths(from, to []string) []int {
+	previous := make([]int, len(to)+1)
+	current := make([]int, len(to)+1)
+	for i := range from {
+		for j := range to {
+			switch {
+			case from[i] == to[j]:
+				current[j+1] = previous[j] + 1
+			case previous[j+1] >= current[j]:
+				current[j+1] = previous[j+1]
+			default:
+				current[j+1] = current[j]
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous
+}
+
+// suffixLengths returns, for every j, the length of the longest common
+// subsequence of from and to[j:].
+func suffixLengths(from, to []string) []int {
+	previous := make([]int, len(to)+1)
+	current := make([]int, len(to)+1)
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			switch {
+			case from[i] == to[j]:
+				current[j] = previous[j+1] + 1
+			case previous[j] >= current[j+1]:
+				current[j] = previous[j]
+			default:
+				current[j] = current[j+1]
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous
+}
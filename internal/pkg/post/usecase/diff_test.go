@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"math/rand"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
+
+// formatDiff writes lines as "=kept", "-deleted" and "+inserted".
+func formatDiff(lines []*models.DiffLine) []string {
+	formatted := make([]string, 0, len(lines))
+	for _, line := range lines {
+		op := map[string]string{diffEqual: "=", diffDelete: "-", diffInsert: "+"}[line.Op]
+		formatted = append(formatted, op+line.Text)
+	}
+	return formatted
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     []string
+	}{
+		{"same", "a\nb", "a\nb", []string{"=a", "=b"}},
+		{"empty", "", "", []string{"="}},
+		{"appended", "a", "a\nb", []string{"=a", "+b"}},
+		{"removed", "a\nb\nc", "a\nc", []string{"=a", "-b", "=c"}},
+		{"replaced", "a\nb\nc", "a\nx\nc", []string{"=a", "-b", "+x", "=c"}},
+		{"all replaced", "a\nb", "x\ny", []string{"-a", "-b", "+x", "+y"}},
+		{"moved", "a\nb\nc", "b\nc\na", []string{"-a", "=b", "=c", "+a"}},
+		{"repeated", "a\na\nb", "a\nb\nb", []string{"=a", "-a", "+b", "=b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatDiff(diffLines(tt.from, tt.to))
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("diffLines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// commonLength is the quadratic longest common subsequence length the
+// diff is checked against.
+func commonLength(from, to []string) int {
+	common := make([][]int, len(from)+1)
+	for i := range common {
+		common[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			switch {
+			case from[i] == to[j]:
+				common[i][j] = common[i+1][j+1] + 1
+			case common[i+1][j] >= common[i][j+1]:
+				common[i][j] = common[i+1][j]
+			default:
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+	return common[0][0]
+}
+
+// checkDiff fails unless lines turn from into to keeping as many lines as
+// possible.
+func checkDiff(t *testing.T, from, to []string, lines []*models.DiffLine, wantEqual int) {
+	t.Helper()
+
+	var gotFrom, gotTo []string
+	equal := 0
+	for _, line := range lines {
+		switch line.Op {
+		case diffEqual:
+			gotFrom, gotTo = append(gotFrom, line.Text), append(gotTo, line.Text)
+			equal++
+		case diffDelete:
+			gotFrom = append(gotFrom, line.Text)
+		case diffInsert:
+			gotTo = append(gotTo, line.Text)
+		}
+	}
+	if strings.Join(gotFrom, "\n") != strings.Join(from, "\n") || strings.Join(gotTo, "\n") != strings.Join(to, "\n") {
+		t.Fatalf("diff of %q and %q doesn't rebuild them: %v", from, to, formatDiff(lines))
+	}
+	if equal != wantEqual {
+		t.Fatalf("diff of %q and %q keeps %d lines, want %d", from, to, equal, wantEqual)
+	}
+}
+
+func randomLines(random *rand.Rand, count, alphabet int) []string {
+	lines := make([]string, count)
+	for i := range lines {
+		lines[i] = strconv.Itoa(random.Intn(alphabet))
+	}
+	return lines
+}
+
+func TestDiffLinesKeepsLongestCommonSubsequence(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		from := randomLines(random, 1+random.Intn(12), 1+random.Intn(4))
+		to := randomLines(random, 1+random.Intn(12), 1+random.Intn(4))
+
+		lines := diffLines(strings.Join(from, "\n"), strings.Join(to, "\n"))
+		checkDiff(t, from, to, lines, commonLength(from, to))
+	}
+}
+
+func TestDiffLinesLargeInput(t *testing.T) {
+	const count = 5000
+
+	// Every tenth line is edited, so the messages share nine lines of ten.
+	from := make([]string, count)
+	to := make([]string, count)
+	for i := range from {
+		from[i] = "line " + strconv.Itoa(i)
+		to[i] = from[i]
+		if i%10 == 0 {
+			to[i] = "edited " + strconv.Itoa(i)
+		}
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	lines := diffLines(strings.Join(from, "\n"), strings.Join(to, "\n"))
+	runtime.ReadMemStats(&after)
+
+	checkDiff(t, from, to, lines, count-count/10)
+	// A table of every pair of lines would take count*count*8 = 200MB.
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 64<<20 {
+		t.Errorf("diffLines() allocated %d bytes", allocated)
+	}
+}
@@ -1,11 +1,15 @@
 package usecase
 
 import (
+	"context"
 	"strconv"
 
+	"github.com/forum-api-back/internal/pkg/auth"
+	"github.com/forum-api-back/internal/pkg/events"
 	"github.com/forum-api-back/internal/pkg/forum"
 	"github.com/forum-api-back/internal/pkg/models"
 	"github.com/forum-api-back/internal/pkg/post"
+	"github.com/forum-api-back/internal/pkg/roles"
 	"github.com/forum-api-back/internal/pkg/thread"
 	"github.com/forum-api-back/internal/pkg/user"
 	"github.com/forum-api-back/pkg/errors"
@@ -16,83 +20,107 @@ type PostUseCase struct {
 	ThreadRepo thread.Repository
 	ForumRepo  forum.Repository
 	UserRepo   user.Repository
+	RolesUCase roles.UseCase
+	Events     events.Publisher
 }
 
-func NewUseCase(postRepo post.Repository, threadRepo thread.Repository,
-	forumRepo forum.Repository, userRepo user.Repository) post.UseCase {
+func NewUseCase(postRepo post.Repository, threadRepo thread.Repository, forumRepo forum.Repository,
+	userRepo user.Repository, rolesUCase roles.UseCase, publisher events.Publisher) post.UseCase {
 	return &PostUseCase{
 		PostRepo:   postRepo,
 		ThreadRepo: threadRepo,
 		ForumRepo:  forumRepo,
 		UserRepo:   userRepo,
+		RolesUCase: rolesUCase,
+		Events:     publisher,
 	}
 }
 
-func (u *PostUseCase) CreateNewPosts(threadSlugOrId string, posts []*models.PostCreate) ([]*models.Post, error) {
+func (u *PostUseCase) CreateNewPosts(ctx context.Context, threadSlugOrId string, posts []*models.PostCreate) ([]*models.Post, error) {
 	threadId, err := strconv.Atoi(threadSlugOrId)
 
-	var newPosts []*models.Post
+	var selectedThread *models.Thread
 	if err != nil {
-		selectedThread, errr := u.ThreadRepo.SelectThreadBySlug(threadSlugOrId)
-		if errr != nil {
-			return nil, errors.ErrThreadNotFound
-		}
-		if len(posts) == 0 {
-			return []*models.Post{}, nil
-		}
-		newPosts, err = u.PostRepo.CreateNewPostsById(selectedThread.Id, selectedThread.Forum, posts)
+		selectedThread, err = u.ThreadRepo.SelectThreadBySlug(ctx, threadSlugOrId)
 	} else if threadId >= 1 {
-		selectedThread, errr := u.ThreadRepo.SelectThreadById(uint64(threadId))
-		if errr != nil {
-			return nil, errors.ErrThreadNotFound
-		}
-		if len(posts) == 0 {
-			return []*models.Post{}, nil
-		}
-		newPosts, err = u.PostRepo.CreateNewPostsById(uint64(threadId), selectedThread.Forum, posts)
+		selectedThread, err = u.ThreadRepo.SelectThreadById(ctx, uint64(threadId))
 	} else {
-		return nil, errors.ErrPostNotFound
+		return nil, threadNotFound(threadSlugOrId)
 	}
 
-	switch err {
-	case nil:
-		return newPosts, nil
-	case errors.ErrUserNotFound:
-		return nil, errors.ErrUserNotFound
-	default:
-		return nil, errors.ErrPostNotFound
+	if err != nil {
+		return nil, errors.Translate(err, threadNotFound(threadSlugOrId))
+	}
+	if selectedThread.IsArchived {
+		return nil, errors.ErrThreadArchived.WithMessage("Thread %s is archived", threadSlugOrId)
+	}
+	// Threads of an archived forum keep their own flag but are read-only.
+	selectedForum, err := u.ForumRepo.SelectForumBySlug(ctx, selectedThread.Forum)
+	if err != nil {
+		return nil, errors.Translate(err, errors.ErrForumNotFound)
+	}
+	if selectedForum.IsArchived {
+		return nil, errors.ErrForumArchived.WithMessage("Forum %s is archived", selectedForum.Slug)
+	}
+	if len(posts) == 0 {
+		return []*models.Post{}, nil
+	}
+	actors := make(map[string]string, 1)
+	for _, newPost := range posts {
+		actor, ok := actors[newPost.Author]
+		if !ok {
+			if actor, err = u.RolesUCase.Actor(ctx, newPost.Author); err != nil {
+				return nil, err
+			}
+			actors[newPost.Author] = actor
+		}
+		newPost.Author = actor
 	}
+
+	newPosts, err := u.PostRepo.CreateNewPostsById(ctx, selectedThread.Id, selectedThread.Forum, posts)
+	if err != nil {
+		return nil, errors.Translate(err, threadNotFound(threadSlugOrId))
+	}
+	copiedThread := *selectedThread
+	copiedPosts := make([]*models.Post, len(newPosts))
+	for i, newPost := range newPosts {
+		copiedPost := *newPost
+		copiedPosts[i] = &copiedPost
+	}
+	u.Events.Publish(ctx, &events.PostsCreated{Thread: &copiedThread, Posts: copiedPosts})
+
+	return newPosts, nil
 }
 
-func (u *PostUseCase) GetPostDetail(postId uint64, related map[string]bool) (*models.PostDetails, error) {
+func (u *PostUseCase) GetPostDetail(ctx context.Context, postId uint64, related map[string]bool) (*models.PostDetails, error) {
 	postDetails := &models.PostDetails{}
 
-	selectedPost, err := u.PostRepo.SelectPostById(postId)
+	selectedPost, err := u.PostRepo.SelectPostById(ctx, postId)
 	if err != nil {
-		return nil, errors.ErrThreadNotFound
+		return nil, errors.Translate(err, postNotFound(postId))
 	}
 	postDetails.Post = selectedPost
 
 	if related["user"] {
-		selectedUser, err := u.UserRepo.SelectUserByNickName(selectedPost.Author)
+		selectedUser, err := u.UserRepo.SelectUserByNickName(ctx, selectedPost.Author)
 		if err != nil {
-			return nil, errors.ErrThreadNotFound
+			return nil, errors.Translate(err, errors.ErrUserNotFound)
 		}
 		postDetails.Author = selectedUser
 	}
 
 	if related["thread"] {
-		selectedThread, err := u.ThreadRepo.SelectThreadById(selectedPost.Thread)
+		selectedThread, err := u.ThreadRepo.SelectThreadById(ctx, selectedPost.Thread)
 		if err != nil {
-			return nil, errors.ErrThreadNotFound
+			return nil, err
 		}
 		postDetails.Thread = selectedThread
 	}
 
 	if related["forum"] {
-		selectedForum, err := u.ForumRepo.SelectForumBySlug(selectedPost.Forum)
+		selectedForum, err := u.ForumRepo.SelectForumBySlug(ctx, selectedPost.Forum)
 		if err != nil {
-			return nil, errors.ErrThreadNotFound
+			return nil, errors.Translate(err, errors.ErrForumNotFound)
 		}
 		postDetails.Forum = selectedForum
 	}
@@ -100,48 +128,193 @@ func (u *PostUseCase) GetPostDetail(postId uint64, related map[string]bool) (*mo
 	return postDetails, nil
 }
 
-func (u *PostUseCase) GetPostsByThread(threadSlugOrId string, paginator *models.PostPaginator) ([]*models.Post, error) {
+func (u *PostUseCase) GetPostsByThread(ctx context.Context, threadSlugOrId string, paginator *models.PostPaginator) ([]*models.Post, error) {
 	threadId, err := strconv.Atoi(threadSlugOrId)
 
-	var newPosts []*models.Post
+	var selectedThread *models.Thread
 	if err != nil {
-		selectedThread, errr := u.ThreadRepo.SelectThreadBySlug(threadSlugOrId)
-		if errr != nil {
-			return nil, errors.ErrThreadNotFound
-		}
-		newPosts, err = u.PostRepo.SelectPostsById(selectedThread.Id, paginator)
+		selectedThread, err = u.ThreadRepo.SelectThreadBySlug(ctx, threadSlugOrId)
 	} else if threadId >= 1 {
-		if _, errr := u.ThreadRepo.SelectThreadById(uint64(threadId)); errr != nil {
-			return nil, errors.ErrThreadNotFound
-		}
-		newPosts, err = u.PostRepo.SelectPostsById(uint64(threadId), paginator)
+		selectedThread, err = u.ThreadRepo.SelectThreadById(ctx, uint64(threadId))
 	} else {
-		return nil, errors.ErrThreadNotFound
+		return nil, threadNotFound(threadSlugOrId)
 	}
 
 	if err != nil {
-		return nil, errors.ErrThreadNotFound
+		return nil, errors.Translate(err, threadNotFound(threadSlugOrId))
 	}
 
-	return newPosts, nil
+	return u.PostRepo.SelectPostsById(ctx, selectedThread.Id, paginator)
 }
 
-func (u *PostUseCase) UpdatePostDetails(postId uint64, postInfo *models.PostUpdate) (*models.Post, error) {
-	selectedPost, err := u.PostRepo.SelectPostById(postId)
+func (u *PostUseCase) UpdatePostDetails(ctx context.Context, postId uint64, postInfo *models.PostUpdate) (*models.Post, error) {
+	selectedPost, err := u.PostRepo.SelectPostById(ctx, postId)
+	if err != nil {
+		return nil, errors.Translate(err, postNotFound(postId))
+	}
+	if selectedPost.IsDeleted {
+		return nil, postDeleted(postId)
+	}
+
+	err = u.RolesUCase.AuthorizeAuthor(ctx, selectedPost.Forum, selectedPost.Author, models.RoleModerator)
 	if err != nil {
-		return nil, errors.ErrPostNotFound
+		return nil, err
 	}
 
 	if postInfo.Message == "" || postInfo.Message == selectedPost.Message {
 		return selectedPost, nil
 	}
 
-	err = u.PostRepo.UpdatePostById(postId, postInfo)
+	return u.updatePost(ctx, selectedPost, postInfo.Message)
+}
+
+// updatePost stores a new message of a post as a revision made by the
+// caller, or by the author for requests without a session.
+func (u *PostUseCase) updatePost(ctx context.Context, selectedPost *models.Post, message string) (*models.Post, error) {
+	editor := selectedPost.Author
+	if caller, ok := auth.Caller(ctx); ok {
+		editor = caller
+	}
+
+	err := u.PostRepo.UpdatePostById(ctx, selectedPost.Id, &models.PostUpdate{Message: message}, editor)
 	if err != nil {
-		return nil, errors.ErrPostNotFound
+		return nil, errors.Translate(err, postNotFound(selectedPost.Id))
 	}
-	selectedPost.Message = postInfo.Message
+	selectedPost.Message = message
 	selectedPost.IsEdited = true
+	copiedPost := *selectedPost
+	u.Events.Publish(ctx, &events.PostEdited{Post: &copiedPost, Editor: editor})
 
 	return selectedPost, nil
 }
+
+func (u *PostUseCase) DeletePost(ctx context.Context, postId uint64) (*models.Post, error) {
+	selectedPost, err := u.PostRepo.SelectPostById(ctx, postId)
+	if err != nil {
+		return nil, errors.Translate(err, postNotFound(postId))
+	}
+	if selectedPost.IsDeleted {
+		return nil, postDeleted(postId)
+	}
+
+	err = u.RolesUCase.AuthorizeAuthor(ctx, selectedPost.Forum, selectedPost.Author, models.RoleModerator)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.PostRepo.DeletePostById(ctx, postId)
+	if err != nil {
+		return nil, errors.Translate(err, postNotFound(postId))
+	}
+	selectedPost.Message = ""
+	selectedPost.IsDeleted = true
+
+	return selectedPost, nil
+}
+
+func (u *PostUseCase) GetPostHistory(ctx context.Context, postId uint64) ([]*models.PostRevision, error) {
+	_, revisions, err := u.selectHistory(ctx, postId)
+	return revisions, err
+}
+
+func (u *PostUseCase) DiffPostRevisions(ctx context.Context, postId, from, to uint64) (*models.PostRevisionDiff, error) {
+	_, revisions, err := u.selectHistory(ctx, postId)
+	if err != nil {
+		return nil, err
+	}
+
+	if to == 0 {
+		to = revisions[len(revisions)-1].Revision
+	}
+	if from == 0 {
+		from = to
+		if to > 1 {
+			from = to - 1
+		}
+	}
+
+	fromRevision, err := findRevision(revisions, postId, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := findRevision(revisions, postId, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PostRevisionDiff{
+		From:  from,
+		To:    to,
+		Lines: diffLines(fromRevision.Message, toRevision.Message),
+	}, nil
+}
+
+func (u *PostUseCase) RestorePostRevision(ctx context.Context, postId, revision uint64) (*models.Post, error) {
+	selectedPost, revisions, err := u.selectHistory(ctx, postId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.RolesUCase.Authorize(ctx, selectedPost.Forum, models.RoleModerator)
+	if err != nil {
+		return nil, err
+	}
+
+	restoredRevision, err := findRevision(revisions, postId, revision)
+	if err != nil {
+		return nil, err
+	}
+	if restoredRevision.Message == selectedPost.Message {
+		return selectedPost, nil
+	}
+
+	return u.updatePost(ctx, selectedPost, restoredRevision.Message)
+}
+
+// selectHistory returns a post with its revisions. A post that was never
+// edited has a single revision: the message it was created with.
+func (u *PostUseCase) selectHistory(ctx context.Context, postId uint64) (*models.Post, []*models.PostRevision, error) {
+	selectedPost, err := u.PostRepo.SelectPostById(ctx, postId)
+	if err != nil {
+		return nil, nil, errors.Translate(err, postNotFound(postId))
+	}
+	if selectedPost.IsDeleted {
+		return nil, nil, postDeleted(postId)
+	}
+
+	revisions, err := u.PostRepo.SelectRevisionsById(ctx, postId)
+	if err != nil {
+		return nil, nil, errors.Translate(err, postNotFound(postId))
+	}
+	if len(revisions) == 0 {
+		revisions = []*models.PostRevision{{
+			Revision:   1,
+			Editor:     selectedPost.Author,
+			Message:    selectedPost.Message,
+			DateEdited: selectedPost.DateCreated,
+		}}
+	}
+
+	return selectedPost, revisions, nil
+}
+
+func findRevision(revisions []*models.PostRevision, postId, revision uint64) (*models.PostRevision, error) {
+	for _, postRevision := range revisions {
+		if postRevision.Revision == revision {
+			return postRevision, nil
+		}
+	}
+	return nil, errors.ErrRevisionNotFound.WithMessage("Can't find revision %d of post with id: %d", revision, postId)
+}
+
+func threadNotFound(threadSlugOrId string) *errors.Error {
+	return errors.ErrThreadNotFound.WithMessage("Can't find post thread by id: %s", threadSlugOrId)
+}
+
+func postNotFound(postId uint64) *errors.Error {
+	return errors.ErrPostNotFound.WithMessage("Can't find post with id: %d", postId)
+}
+
+func postDeleted(postId uint64) *errors.Error {
+	return errors.ErrPostDeleted.WithMessage("Post with id %d was deleted", postId)
+}
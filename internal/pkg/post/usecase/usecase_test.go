@@ -0,0 +1,255 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/forum-api-back/internal/pkg/auth"
+	events_dispatcher "github.com/forum-api-back/internal/pkg/events/dispatcher"
+	forum_repo "github.com/forum-api-back/internal/pkg/forum/repository"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/post"
+	post_repo "github.com/forum-api-back/internal/pkg/post/repository"
+	roles_repo "github.com/forum-api-back/internal/pkg/roles/repository"
+	roles_usecase "github.com/forum-api-back/internal/pkg/roles/usecase"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+	thread_repo "github.com/forum-api-back/internal/pkg/thread/repository"
+	user_repo "github.com/forum-api-back/internal/pkg/user/repository"
+	"github.com/forum-api-back/pkg/errors"
+)
+
+// newTestUseCase returns a usecase over thread 1 of forum "f" of "owner".
+// "guest" has no password, "member" and "owner" do.
+func newTestUseCase(t *testing.T) post.UseCase {
+	t.Helper()
+	ctx := context.Background()
+
+	storage := memory.NewStorage()
+	userRepo := user_repo.NewSessionMemoryRepository(storage)
+	forumRepo := forum_repo.NewSessionMemoryRepository(storage)
+	threadRepo := thread_repo.NewSessionMemoryRepository(storage)
+
+	for _, nickname := range []string{"owner", "member", "guest"} {
+		newUser := &models.User{NickName: nickname, Email: nickname + "@example.com"}
+		if nickname != "guest" {
+			newUser.PasswordHash = "hash"
+		}
+		if err := userRepo.InsertUser(ctx, newUser); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := forumRepo.InsertForum(ctx, &models.ForumCreate{Title: "Forum", AuthorNickName: "owner", Slug: "f"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := threadRepo.InsertThread(ctx, "f", &models.ThreadCreate{Title: "Thread", AuthorNickName: "owner", Slug: "t"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rolesUCase := roles_usecase.NewUseCase(roles_repo.NewSessionMemoryRepository(storage), forumRepo, userRepo, nil)
+	return NewUseCase(post_repo.NewSessionMemoryRepository(storage), threadRepo, forumRepo, userRepo,
+		rolesUCase, events_dispatcher.NewDispatcher(0))
+}
+
+func callerContext(caller string) context.Context {
+	if caller == "" {
+		return context.Background()
+	}
+	return auth.WithCaller(context.Background(), caller)
+}
+
+func checkCode(t *testing.T, err error, code errors.Code) {
+	t.Helper()
+	switch {
+	case code == "" && err != nil:
+		t.Fatalf("error = %v", err)
+	case code != "" && errors.From(err).Code != code:
+		t.Fatalf("error = %v, want code %v", err, code)
+	}
+}
+
+func TestCreateNewPostsAuthors(t *testing.T) {
+	tests := []struct {
+		name    string
+		caller  string
+		authors []string
+		want    []string
+		code    errors.Code
+	}{
+		{"session replaces the body", "member", []string{"guest", "owner"}, []string{"member", "member"}, ""},
+		{"no session, authors without password", "", []string{"guest", "GUEST"}, []string{"guest", "GUEST"}, ""},
+		{"no session, author with password", "", []string{"guest", "member"}, nil, errors.CodeUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestUseCase(t)
+			posts := make([]*models.PostCreate, len(tt.authors))
+			for i, author := range tt.authors {
+				posts[i] = &models.PostCreate{Author: author, Message: "message"}
+			}
+
+			newPosts, err := u.CreateNewPosts(callerContext(tt.caller), "t", posts)
+			checkCode(t, err, tt.code)
+			for i, newPost := range newPosts {
+				if newPost.Author != tt.want[i] {
+					t.Errorf("author of post %d = %q, want %q", i, newPost.Author, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestUpdatePostEditor(t *testing.T) {
+	tests := []struct {
+		name   string
+		caller string
+		author string
+		code   errors.Code
+	}{
+		{"no session, author without password", "", "guest", ""},
+		{"no session, author with password", "", "member", errors.CodeUnauthorized},
+		{"author", "member", "member", ""},
+		{"other member", "guest", "member", errors.CodeForbidden},
+		{"forum owner", "owner", "member", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestUseCase(t)
+			newPosts, err := u.CreateNewPosts(callerContext(tt.author), "t",
+				[]*models.PostCreate{{Author: tt.author, Message: "message"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = u.UpdatePostDetails(callerContext(tt.caller), newPosts[0].Id, &models.PostUpdate{Message: "edited"})
+			checkCode(t, err, tt.code)
+		})
+	}
+}
+
+func TestCreateNewPosts(t *testing.T) {
+	tests := []struct {
+		name   string
+		thread string
+		posts  []*models.PostCreate
+		code   errors.Code
+	}{
+		{"by slug", "T", []*models.PostCreate{{Author: "guest", Message: "message"}}, ""},
+		{"by id", "1", []*models.PostCreate{{Author: "guest", Message: "message"}}, ""},
+		{"empty batch", "1", []*models.PostCreate{}, ""},
+		{"unknown thread", "2", []*models.PostCreate{{Author: "guest", Message: "message"}}, errors.CodeNotFound},
+		{"unknown parent", "1", []*models.PostCreate{{Parent: 42, Author: "guest", Message: "message"}}, errors.CodeConflict},
+		{"unknown author", "1", []*models.PostCreate{{Author: "nobody", Message: "message"}}, errors.CodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestUseCase(t)
+
+			newPosts, err := u.CreateNewPosts(context.Background(), tt.thread, tt.posts)
+			checkCode(t, err, tt.code)
+			if err != nil {
+				return
+			}
+			if len(newPosts) != len(tt.posts) {
+				t.Fatalf("created %d posts, want %d", len(newPosts), len(tt.posts))
+			}
+			for _, newPost := range newPosts {
+				if newPost.Thread != 1 || newPost.Forum != "f" {
+					t.Errorf("post = %+v, want one of thread 1 in forum f", newPost)
+				}
+			}
+		})
+	}
+}
+
+func TestGetPostsByThread(t *testing.T) {
+	u := newTestUseCase(t)
+	ctx := context.Background()
+	roots, err := u.CreateNewPosts(ctx, "t", []*models.PostCreate{
+		{Author: "guest", Message: "first"},
+		{Author: "guest", Message: "second"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = u.CreateNewPosts(ctx, "t", []*models.PostCreate{{Parent: roots[0].Id, Author: "guest", Message: "reply"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		sort string
+		want []string
+	}{
+		{"flat", []string{"first", "second", "reply"}},
+		{"tree", []string{"first", "reply", "second"}},
+		{"parent_tree", []string{"first", "reply", "second"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			posts, err := u.GetPostsByThread(ctx, "1", &models.PostPaginator{Sort: tt.sort, Limit: 100})
+			if err != nil {
+				t.Fatal(err)
+			}
+			messages := make([]string, len(posts))
+			for i, selectedPost := range posts {
+				messages[i] = selectedPost.Message
+			}
+			if !reflect.DeepEqual(messages, tt.want) {
+				t.Errorf("posts = %v, want %v", messages, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPostDetail(t *testing.T) {
+	u := newTestUseCase(t)
+	ctx := context.Background()
+	newPosts, err := u.CreateNewPosts(ctx, "t", []*models.PostCreate{{Author: "guest", Message: "message"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	details, err := u.GetPostDetail(ctx, newPosts[0].Id, map[string]bool{"user": true, "forum": true, "thread": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details.Author.NickName != "guest" || details.Thread.Slug != "t" || details.Forum.Slug != "f" {
+		t.Errorf("details = %+v, want guest, thread t and forum f", details)
+	}
+	if details.Forum.Posts != 1 {
+		t.Errorf("forum posts = %d, want 1", details.Forum.Posts)
+	}
+
+	_, err = u.GetPostDetail(ctx, 42, nil)
+	checkCode(t, err, errors.CodeNotFound)
+}
+
+func TestDeletePost(t *testing.T) {
+	u := newTestUseCase(t)
+	ctx := context.Background()
+	newPosts, err := u.CreateNewPosts(ctx, "t", []*models.PostCreate{{Author: "guest", Message: "message"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = u.CreateNewPosts(ctx, "t", []*models.PostCreate{{Parent: newPosts[0].Id, Author: "guest", Message: "reply"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deletedPost, err := u.DeletePost(ctx, newPosts[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !deletedPost.IsDeleted || deletedPost.Message != "" {
+		t.Errorf("deleted post = %+v, want a tombstone", deletedPost)
+	}
+
+	_, err = u.DeletePost(ctx, newPosts[0].Id)
+	checkCode(t, err, errors.CodeConflict)
+	_, err = u.UpdatePostDetails(ctx, newPosts[0].Id, &models.PostUpdate{Message: "edited"})
+	checkCode(t, err, errors.CodeConflict)
+}
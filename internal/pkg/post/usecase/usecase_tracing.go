@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/post"
+	"github.com/forum-api-back/pkg/tracing"
+)
+
+// TracedUseCase records a span for every method called within a traced request.
+type TracedUseCase struct {
+	ucase post.UseCase
+}
+
+func NewTracedUseCase(ucase post.UseCase) post.UseCase {
+	return &TracedUseCase{
+		ucase: ucase,
+	}
+}
+
+func (u *TracedUseCase) CreateNewPosts(ctx context.Context, threadSlugOrId string,
+	posts []*models.PostCreate) (_ []*models.Post, err error) {
+	ctx, span := tracing.Start(ctx, "post.usecase.CreateNewPosts")
+	defer func() { span.End(err) }()
+	return u.ucase.CreateNewPosts(ctx, threadSlugOrId, posts)
+}
+
+func (u *TracedUseCase) GetPostDetail(ctx context.Context, postId uint64,
+	related map[string]bool) (_ *models.PostDetails, err error) {
+	ctx, span := tracing.Start(ctx, "post.usecase.GetPostDetail")
+	defer func() { span.End(err) }()
+	return u.ucase.GetPostDetail(ctx, postId, related)
+}
+
+func (u *TracedUseCase) GetPostsByThread(ctx context.Context, threadSlugOrId string,
+	paginator *models.PostPaginator) (_ []*models.Post, err error) {
+	ctx, span := tracing.Start(ctx, "post.usecase.GetPostsByThread")
+	defer func() { span.End(err) }()
+	return u.ucase.GetPostsByThread(ctx, threadSlugOrId, paginator)
+}
+
+func (u *TracedUseCase) UpdatePostDetails(ctx context.Context, postId uint64,
+	postInfo *models.PostUpdate) (_ *models.Post, err error) {
+	ctx, span := tracing.Start(ctx, "post.usecase.UpdatePostDetails")
+	defer func() { span.End(err) }()
+	return u.ucase.UpdatePostDetails(ctx, postId, postInfo)
+}
+
+func (u *TracedUseCase) DeletePost(ctx context.Context, postId uint64) (_ *models.Post, err error) {
+	ctx, span := tracing.Start(ctx, "post.usecase.DeletePost")
+	defer func() { span.End(err) }()
+	return u.ucase.DeletePost(ctx, postId)
+}
+
+func (u *TracedUseCase) GetPostHistory(ctx context.Context,
+	postId uint64) (_ []*models.PostRevision, err error) {
+	ctx, span := tracing.Start(ctx, "post.usecase.GetPostHistory")
+	defer func() { span.End(err) }()
+	return u.ucase.GetPostHistory(ctx, postId)
+}
+
+func (u *TracedUseCase) DiffPostRevisions(ctx context.Context, postId, from,
+	to uint64) (_ *models.PostRevisionDiff, err error) {
+	ctx, span := tracing.Start(ctx, "post.usecase.DiffPostRevisions")
+	defer func() { span.End(err) }()
+	return u.ucase.DiffPostRevisions(ctx, postId, from, to)
+}
+
+func (u *TracedUseCase) RestorePostRevision(ctx context.Context, postId,
+	revision uint64) (_ *models.Post, err error) {
+	ctx, span := tracing.Start(ctx, "post.usecase.RestorePostRevision")
+	defer func() { span.End(err) }()
+	return u.ucase.RestorePostRevision(ctx, postId, revision)
+}
@@ -0,0 +1,9 @@
+package roles
+
+import "github.com/valyala/fasthttp"
+
+type Handler interface {
+	GetModerators(ctx *fasthttp.RequestCtx)
+	AddModerator(ctx *fasthttp.RequestCtx)
+	RemoveModerator(ctx *fasthttp.RequestCtx)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/forum-api-back/internal/pkg/roles"
+	"github.com/forum-api-back/pkg/errors"
+	"github.com/forum-api-back/pkg/tools/http_utils"
+
+	"github.com/valyala/fasthttp"
+)
+
+type RolesHandler struct {
+	RolesUCase roles.UseCase
+}
+
+func NewHandler(rolesUCase roles.UseCase) roles.Handler {
+	return &RolesHandler{
+		RolesUCase: rolesUCase,
+	}
+}
+
+func (h *RolesHandler) GetModerators(ctx *fasthttp.RequestCtx) {
+	forumSlug := ctx.UserValue("slug").(string)
+	if forumSlug == "" {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "slug", Message: "must not be empty"}))
+		return
+	}
+
+	moderators, err := h.RolesUCase.GetModerators(http_utils.Context(ctx), forumSlug)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, moderators, http.StatusOK)
+}
+
+func (h *RolesHandler) AddModerator(ctx *fasthttp.RequestCtx) {
+	forumSlug, nickname, ok := moderatorArguments(ctx)
+	if !ok {
+		return
+	}
+
+	moderator, err := h.RolesUCase.AddModerator(http_utils.Context(ctx), forumSlug, nickname)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, moderator, http.StatusOK)
+}
+
+func (h *RolesHandler) RemoveModerator(ctx *fasthttp.RequestCtx) {
+	forumSlug, nickname, ok := moderatorArguments(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.RolesUCase.RemoveModerator(http_utils.Context(ctx), forumSlug, nickname); err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, struct{}{}, http.StatusOK)
+}
+
+func moderatorArguments(ctx *fasthttp.RequestCtx) (string, string, bool) {
+	forumSlug := ctx.UserValue("slug").(string)
+	nickname := ctx.UserValue("nickname").(string)
+
+	details := make([]*errors.FieldError, 0)
+	if forumSlug == "" {
+		details = append(details, &errors.FieldError{Field: "slug", Message: "must not be empty"})
+	}
+	if nickname == "" {
+		details = append(details, &errors.FieldError{Field: "nickname", Message: "must not be empty"})
+	}
+	if len(details) != 0 {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(details...))
+		return "", "", false
+	}
+
+	return forumSlug, nickname, true
+}
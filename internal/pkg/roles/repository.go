@@ -0,0 +1,14 @@
+package roles
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
+
+type Repository interface {
+	InsertModerator(ctx context.Context, forumSlug, nickname string) error
+	DeleteModerator(ctx context.Context, forumSlug, nickname string) error
+	SelectModerators(ctx context.Context, forumSlug string) ([]*models.User, error)
+	IsModerator(ctx context.Context, forumSlug, nickname string) (bool, error)
+}
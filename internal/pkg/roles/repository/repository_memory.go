@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/roles"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+	"github.com/forum-api-back/pkg/errors"
+)
+
+type MemoryRepository struct {
+	storage *memory.Storage
+}
+
+func NewSessionMemoryRepository(storage *memory.Storage) roles.Repository {
+	return &MemoryRepository{
+		storage: storage,
+	}
+}
+
+func (r *MemoryRepository) InsertModerator(ctx context.Context, forumSlug, nickname string) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	forumKey, nicknameKey := memory.Key(forumSlug), memory.Key(nickname)
+	if _, ok := r.storage.Forums[forumKey]; !ok {
+		return errors.ErrDataConflict
+	}
+	if _, ok := r.storage.Users[nicknameKey]; !ok {
+		return errors.ErrDataConflict
+	}
+
+	if r.storage.Moderators[forumKey] == nil {
+		r.storage.Moderators[forumKey] = make(map[string]bool)
+	}
+	r.storage.Moderators[forumKey][nicknameKey] = true
+
+	return nil
+}
+
+func (r *MemoryRepository) DeleteModerator(ctx context.Context, forumSlug, nickname string) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	forumKey, nicknameKey := memory.Key(forumSlug), memory.Key(nickname)
+	if !r.storage.Moderators[forumKey][nicknameKey] {
+		return errors.ErrNotFoundInDB
+	}
+	delete(r.storage.Moderators[forumKey], nicknameKey)
+
+	return nil
+}
+
+func (r *MemoryRepository) SelectModerators(ctx context.Context, forumSlug string) ([]*models.User, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	users := make([]*models.User, 0)
+	for nicknameKey := range r.storage.Moderators[memory.Key(forumSlug)] {
+		copiedUser := *r.storage.Users[nicknameKey]
+		users = append(users, &copiedUser)
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return memory.Key(users[i].NickName) < memory.Key(users[j].NickName)
+	})
+
+	return users, nil
+}
+
+func (r *MemoryRepository) IsModerator(ctx context.Context, forumSlug, nickname string) (bool, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	return r.storage.Moderators[memory.Key(forumSlug)][memory.Key(nickname)], nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/roles"
+	"github.com/forum-api-back/pkg/logger"
+	"github.com/forum-api-back/pkg/metrics"
+)
+
+// MeasuredRepository observes how long every method takes and logs the
+// calls taking slowQuery or longer.
+type MeasuredRepository struct {
+	repo      roles.Repository
+	durations *metrics.HistogramVec
+	slowQuery time.Duration
+}
+
+// NewMeasuredRepository observes the durations of the methods of repo into
+// durations, labeled with the repository and the method. Calls taking
+// slowQuery or longer are logged as well, unless it is zero.
+func NewMeasuredRepository(repo roles.Repository, durations *metrics.HistogramVec,
+	slowQuery time.Duration) roles.Repository {
+	return &MeasuredRepository{
+		repo:      repo,
+		durations: durations,
+		slowQuery: slowQuery,
+	}
+}
+
+func (r *MeasuredRepository) InsertModerator(ctx context.Context, forumSlug, nickname string) error {
+	defer r.observe(ctx, "InsertModerator", time.Now())
+	return r.repo.InsertModerator(ctx, forumSlug, nickname)
+}
+
+func (r *MeasuredRepository) DeleteModerator(ctx context.Context, forumSlug, nickname string) error {
+	defer r.observe(ctx, "DeleteModerator", time.Now())
+	return r.repo.DeleteModerator(ctx, forumSlug, nickname)
+}
+
+func (r *MeasuredRepository) SelectModerators(ctx context.Context, forumSlug string) ([]*models.User, error) {
+	defer r.observe(ctx, "SelectModerators", time.Now())
+	return r.repo.SelectModerators(ctx, forumSlug)
+}
+
+func (r *MeasuredRepository) IsModerator(ctx context.Context, forumSlug, nickname string) (bool, error) {
+	defer r.observe(ctx, "IsModerator", time.Now())
+	return r.repo.IsModerator(ctx, forumSlug, nickname)
+}
+
+func (r *MeasuredRepository) observe(ctx context.Context, method string, start time.Time) {
+	elapsed := time.Since(start)
+	r.durations.With("roles", method).Observe(elapsed.Seconds())
+	if r.slowQuery > 0 && elapsed >= r.slowQuery {
+		logger.FromContext(ctx).Warn("slow query", "repository", "roles", "method", method, "duration", elapsed)
+	}
+}
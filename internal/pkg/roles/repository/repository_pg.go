@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/roles"
+	"github.com/forum-api-back/pkg/errors"
+	"github.com/forum-api-back/pkg/tools/sql_utils"
+)
+
+type PostgresqlRepository struct {
+	db *sql.DB
+}
+
+func NewSessionPostgresqlRepository(db *sql.DB) roles.Repository {
+	return &PostgresqlRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresqlRepository) InsertModerator(ctx context.Context, forumSlug, nickname string) error {
+	_, err := r.db.ExecContext(ctx,
+		"INSERT INTO moderators(forum_slug, user_nickname) "+
+			"VALUES ($1, $2) "+
+			"ON CONFLICT DO NOTHING",
+		forumSlug,
+		nickname,
+	)
+
+	return sql_utils.Error(err, errors.ErrNotFoundInDB)
+}
+
+func (r *PostgresqlRepository) DeleteModerator(ctx context.Context, forumSlug, nickname string) error {
+	result, err := r.db.ExecContext(ctx,
+		"DELETE FROM moderators "+
+			"WHERE forum_slug = $1 AND user_nickname = $2",
+		forumSlug,
+		nickname,
+	)
+	if err != nil {
+		return sql_utils.Error(err, errors.ErrNotFoundInDB)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return sql_utils.Error(err, errors.ErrNotFoundInDB)
+	}
+	if deleted == 0 {
+		return errors.ErrNotFoundInDB
+	}
+
+	return nil
+}
+
+func (r *PostgresqlRepository) SelectModerators(ctx context.Context, forumSlug string) ([]*models.User, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT u.nickname, u.fullname, u.about, u.email "+
+			"FROM users u "+
+			"JOIN moderators m ON (u.nickname = m.user_nickname AND m.forum_slug = $1) "+
+			"ORDER BY u.nickname",
+		forumSlug,
+	)
+	if err != nil {
+		return nil, sql_utils.Error(err, errors.ErrNotFoundInDB)
+	}
+	defer rows.Close()
+
+	users := make([]*models.User, 0)
+	for rows.Next() {
+		about := sql.NullString{}
+		selectedUser := &models.User{}
+		err := rows.Scan(
+			&selectedUser.NickName,
+			&selectedUser.FullName,
+			&about,
+			&selectedUser.Email,
+		)
+		selectedUser.About = about.String
+		if err != nil {
+			return nil, sql_utils.Error(err, errors.ErrNotFoundInDB)
+		}
+
+		users = append(users, selectedUser)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, sql_utils.Error(err, errors.ErrNotFoundInDB)
+	}
+
+	return users, nil
+}
+
+func (r *PostgresqlRepository) IsModerator(ctx context.Context, forumSlug, nickname string) (bool, error) {
+	row := r.db.QueryRowContext(ctx,
+		"SELECT EXISTS ( "+
+			"	SELECT 1 FROM moderators "+
+			"	WHERE forum_slug = $1 AND user_nickname = $2 "+
+			")",
+		forumSlug,
+		nickname,
+	)
+
+	var isModerator bool
+	if err := row.Scan(&isModerator); err != nil {
+		return false, sql_utils.Error(err, errors.ErrNotFoundInDB)
+	}
+
+	return isModerator, nil
+}
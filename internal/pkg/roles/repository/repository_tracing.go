@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/roles"
+	"github.com/forum-api-back/pkg/tracing"
+)
+
+// TracedRepository records a span for every method called within a traced request.
+type TracedRepository struct {
+	repo roles.Repository
+}
+
+func NewTracedRepository(repo roles.Repository) roles.Repository {
+	return &TracedRepository{
+		repo: repo,
+	}
+}
+
+func (r *TracedRepository) InsertModerator(ctx context.Context, forumSlug, nickname string) (err error) {
+	ctx, span := tracing.Start(ctx, "roles.repository.InsertModerator")
+	defer func() { span.End(err) }()
+	return r.repo.InsertModerator(ctx, forumSlug, nickname)
+}
+
+func (r *TracedRepository) DeleteModerator(ctx context.Context, forumSlug, nickname string) (err error) {
+	ctx, span := tracing.Start(ctx, "roles.repository.DeleteModerator")
+	defer func() { span.End(err) }()
+	return r.repo.DeleteModerator(ctx, forumSlug, nickname)
+}
+
+func (r *TracedRepository) SelectModerators(ctx context.Context,
+	forumSlug string) (_ []*models.User, err error) {
+	ctx, span := tracing.Start(ctx, "roles.repository.SelectModerators")
+	defer func() { span.End(err) }()
+	return r.repo.SelectModerators(ctx, forumSlug)
+}
+
+func (r *TracedRepository) IsModerator(ctx context.Context, forumSlug, nickname string) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "roles.repository.IsModerator")
+	defer func() { span.End(err) }()
+	return r.repo.IsModerator(ctx, forumSlug, nickname)
+}
@@ -0,0 +1,26 @@
+package roles
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
+
+type UseCase interface {
+	GetModerators(ctx context.Context, forumSlug string) ([]*models.User, error)
+	AddModerator(ctx context.Context, forumSlug, nickname string) (*models.User, error)
+	RemoveModerator(ctx context.Context, forumSlug, nickname string) error
+
+	// Role returns the role of the caller in a forum, or outside of any
+	// forum when forumSlug is empty.
+	Role(ctx context.Context, forumSlug string) (models.Role, error)
+	// Authorize fails unless the caller has at least the required role.
+	Authorize(ctx context.Context, forumSlug string, required models.Role) error
+	// AuthorizeAuthor is Authorize that also lets the author of the
+	// changed thread or post through.
+	AuthorizeAuthor(ctx context.Context, forumSlug, author string, required models.Role) error
+	// Actor returns the nickname a write is attributed to: the caller, or
+	// for requests without a session the user named in the request, unless
+	// that user has a password and so can only act from a session.
+	Actor(ctx context.Context, nickname string) (string, error)
+}
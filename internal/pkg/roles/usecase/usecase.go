@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/forum-api-back/internal/pkg/auth"
+	"github.com/forum-api-back/internal/pkg/forum"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/roles"
+	"github.com/forum-api-back/internal/pkg/user"
+	"github.com/forum-api-back/pkg/errors"
+)
+
+type RolesUseCase struct {
+	RolesRepo roles.Repository
+	ForumRepo forum.Repository
+	UserRepo  user.Repository
+	Admins    []string
+}
+
+func NewUseCase(rolesRepo roles.Repository, forumRepo forum.Repository,
+	userRepo user.Repository, admins []string) roles.UseCase {
+	return &RolesUseCase{
+		RolesRepo: rolesRepo,
+		ForumRepo: forumRepo,
+		UserRepo:  userRepo,
+		Admins:    admins,
+	}
+}
+
+func (u *RolesUseCase) GetModerators(ctx context.Context, forumSlug string) ([]*models.User, error) {
+	selectedForum, err := u.ForumRepo.SelectForumBySlug(ctx, forumSlug)
+	if err != nil {
+		return nil, errors.Translate(err, forumNotFound(forumSlug))
+	}
+
+	return u.RolesRepo.SelectModerators(ctx, selectedForum.Slug)
+}
+
+func (u *RolesUseCase) AddModerator(ctx context.Context, forumSlug, nickname string) (*models.User, error) {
+	if err := u.Authorize(ctx, forumSlug, models.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	selectedForum, err := u.ForumRepo.SelectForumBySlug(ctx, forumSlug)
+	if err != nil {
+		return nil, errors.Translate(err, forumNotFound(forumSlug))
+	}
+	selectedUser, err := u.UserRepo.SelectUserByNickName(ctx, nickname)
+	if err != nil {
+		return nil, errors.Translate(err, errors.ErrUserNotFound.WithMessage("Can't find user by nickname: %s", nickname))
+	}
+
+	if err := u.RolesRepo.InsertModerator(ctx, selectedForum.Slug, selectedUser.NickName); err != nil {
+		return nil, err
+	}
+
+	return selectedUser, nil
+}
+
+func (u *RolesUseCase) RemoveModerator(ctx context.Context, forumSlug, nickname string) error {
+	if err := u.Authorize(ctx, forumSlug, models.RoleOwner); err != nil {
+		return err
+	}
+
+	err := u.RolesRepo.DeleteModerator(ctx, forumSlug, nickname)
+	return errors.Translate(err, errors.ErrModeratorNotFound.WithMessage(
+		"Can't find moderator %s of forum: %s", nickname, forumSlug))
+}
+
+func (u *RolesUseCase) Role(ctx context.Context, forumSlug string) (models.Role, error) {
+	caller, ok := auth.Caller(ctx)
+	if !ok {
+		return models.RoleGuest, nil
+	}
+	isAdmin, err := u.isAdmin(ctx, caller)
+	switch {
+	case err != nil:
+		return models.RoleGuest, err
+	case isAdmin:
+		return models.RoleAdmin, nil
+	case forumSlug == "":
+		return models.RoleMember, nil
+	}
+
+	selectedForum, err := u.ForumRepo.SelectForumBySlug(ctx, forumSlug)
+	if err != nil {
+		return models.RoleGuest, errors.Translate(err, forumNotFound(forumSlug))
+	}
+	if strings.EqualFold(selectedForum.AuthorNickName, caller) {
+		return models.RoleOwner, nil
+	}
+
+	isModerator, err := u.RolesRepo.IsModerator(ctx, selectedForum.Slug, caller)
+	switch {
+	case err != nil:
+		return models.RoleGuest, err
+	case isModerator:
+		return models.RoleModerator, nil
+	default:
+		return models.RoleMember, nil
+	}
+}
+
+func (u *RolesUseCase) Authorize(ctx context.Context, forumSlug string, required models.Role) error {
+	role, err := u.Role(ctx, forumSlug)
+	switch {
+	case err != nil:
+		return err
+	case role >= required:
+		return nil
+	case role == models.RoleGuest:
+		return errors.ErrUnauthorized
+	case forumSlug == "":
+		return errors.ErrForbidden.WithMessage("Requires %s role", required)
+	default:
+		return errors.ErrForbidden.WithMessage("Requires %s role in forum: %s", required, forumSlug)
+	}
+}
+
+// AuthorizeAuthor lets requests without a session through only for
+// authors without a password, who are still trusted like before sessions
+// existed.
+func (u *RolesUseCase) AuthorizeAuthor(ctx context.Context, forumSlug, author string, required models.Role) error {
+	caller, ok := auth.Caller(ctx)
+	if !ok {
+		_, err := u.Actor(ctx, author)
+		return err
+	}
+	if strings.EqualFold(caller, author) {
+		return nil
+	}
+
+	return u.Authorize(ctx, forumSlug, required)
+}
+
+func (u *RolesUseCase) Actor(ctx context.Context, nickname string) (string, error) {
+	if caller, ok := auth.Caller(ctx); ok {
+		return caller, nil
+	}
+
+	// Unknown users are left to the write itself to report.
+	selectedUser, err := u.UserRepo.SelectUserByNickName(ctx, nickname)
+	switch {
+	case errors.From(err).Code == errors.CodeNotFound:
+		return nickname, nil
+	case err != nil:
+		return "", err
+	case selectedUser.PasswordHash != "":
+		return "", errors.ErrUnauthorized
+	default:
+		return nickname, nil
+	}
+}
+
+// isAdmin tells whether nickname is a configured admin. Admins are only
+// trusted once they have a password, so that a configured nickname can't
+// be claimed by whoever registers it first.
+func (u *RolesUseCase) isAdmin(ctx context.Context, nickname string) (bool, error) {
+	if !isConfiguredAdmin(u.Admins, nickname) {
+		return false, nil
+	}
+
+	selectedUser, err := u.UserRepo.SelectUserByNickName(ctx, nickname)
+	switch {
+	case errors.From(err).Code == errors.CodeNotFound:
+		return false, nil
+	case err != nil:
+		return false, err
+	default:
+		return selectedUser.PasswordHash != "", nil
+	}
+}
+
+func isConfiguredAdmin(admins []string, nickname string) bool {
+	for _, admin := range admins {
+		if strings.EqualFold(admin, nickname) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckAdmins fails unless every configured admin is a user with a
+// password, so that the server doesn't start with admin rights anybody
+// could claim.
+func CheckAdmins(ctx context.Context, userRepo user.Repository, admins []string) error {
+	for _, admin := range admins {
+		selectedUser, err := userRepo.SelectUserByNickName(ctx, admin)
+		switch {
+		case errors.From(err).Code == errors.CodeNotFound:
+			return errors.ErrUserNotFound.WithMessage("Admin %s is not a user, create it with a password first", admin)
+		case err != nil:
+			return err
+		case selectedUser.PasswordHash == "":
+			return errors.ErrUnauthorized.WithMessage("Admin %s has no password, set one with the password command", admin)
+		}
+	}
+	return nil
+}
+
+func forumNotFound(forumSlug string) *errors.Error {
+	return errors.ErrForumNotFound.WithMessage("Can't find forum by slug: %s", forumSlug)
+}
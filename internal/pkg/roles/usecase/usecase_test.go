@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/forum-api-back/internal/pkg/auth"
+	forum_repo "github.com/forum-api-back/internal/pkg/forum/repository"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/roles"
+	roles_repo "github.com/forum-api-back/internal/pkg/roles/repository"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+	user_repo "github.com/forum-api-back/internal/pkg/user/repository"
+	"github.com/forum-api-back/pkg/errors"
+)
+
+// newTestUseCase returns a usecase over forum "f" owned by "owner", moderated
+// by "moder" and administered by "admin". "guest" and "unclaimed" have no
+// password, every other user has one. "unclaimed" and "unregistered" are
+// configured admins too.
+func newTestUseCase(t *testing.T) roles.UseCase {
+	t.Helper()
+	ctx := context.Background()
+
+	storage := memory.NewStorage()
+	userRepo := user_repo.NewSessionMemoryRepository(storage)
+	forumRepo := forum_repo.NewSessionMemoryRepository(storage)
+	rolesRepo := roles_repo.NewSessionMemoryRepository(storage)
+
+	for _, nickname := range []string{"owner", "moder", "member", "admin", "guest", "unclaimed"} {
+		newUser := &models.User{NickName: nickname, Email: nickname + "@example.com"}
+		if nickname != "guest" && nickname != "unclaimed" {
+			newUser.PasswordHash = "hash"
+		}
+		if err := userRepo.InsertUser(ctx, newUser); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := forumRepo.InsertForum(ctx, &models.ForumCreate{Title: "Forum", AuthorNickName: "owner", Slug: "f"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rolesRepo.InsertModerator(ctx, "f", "moder"); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewUseCase(rolesRepo, forumRepo, userRepo, []string{"admin", "unclaimed", "unregistered"})
+}
+
+func callerContext(caller string) context.Context {
+	if caller == "" {
+		return context.Background()
+	}
+	return auth.WithCaller(context.Background(), caller)
+}
+
+func TestRole(t *testing.T) {
+	tests := []struct {
+		caller string
+		forum  string
+		want   models.Role
+	}{
+		{"", "f", models.RoleGuest},
+		{"member", "f", models.RoleMember},
+		{"moder", "f", models.RoleModerator},
+		{"MODER", "f", models.RoleModerator},
+		{"owner", "f", models.RoleOwner},
+		{"admin", "f", models.RoleAdmin},
+		{"owner", "", models.RoleMember},
+		{"admin", "", models.RoleAdmin},
+		{"unclaimed", "", models.RoleMember},
+		{"unregistered", "", models.RoleMember},
+	}
+
+	u := newTestUseCase(t)
+	for _, tt := range tests {
+		role, err := u.Role(callerContext(tt.caller), tt.forum)
+		if err != nil || role != tt.want {
+			t.Errorf("Role(%q, %q) = %v, %v, want %v", tt.caller, tt.forum, role, err, tt.want)
+		}
+	}
+}
+
+func TestAuthorizeAuthor(t *testing.T) {
+	tests := []struct {
+		name     string
+		caller   string
+		author   string
+		required models.Role
+		code     errors.Code
+	}{
+		{"no session, author without password", "", "guest", models.RoleModerator, ""},
+		{"no session, author with password", "", "member", models.RoleModerator, errors.CodeUnauthorized},
+		{"no session, unknown author", "", "nobody", models.RoleModerator, ""},
+		{"author", "member", "member", models.RoleModerator, ""},
+		{"author in other case", "MEMBER", "member", models.RoleModerator, ""},
+		{"other member", "member", "guest", models.RoleModerator, errors.CodeForbidden},
+		{"moderator", "moder", "member", models.RoleModerator, ""},
+		{"moderator, owner required", "moder", "member", models.RoleOwner, errors.CodeForbidden},
+		{"admin", "admin", "member", models.RoleOwner, ""},
+	}
+
+	u := newTestUseCase(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := u.AuthorizeAuthor(callerContext(tt.caller), "f", tt.author, tt.required)
+			if tt.code == "" && err != nil {
+				t.Fatalf("AuthorizeAuthor() error = %v", err)
+			}
+			if tt.code != "" && errors.From(err).Code != tt.code {
+				t.Fatalf("AuthorizeAuthor() error = %v, want code %v", err, tt.code)
+			}
+		})
+	}
+}
+
+func TestActor(t *testing.T) {
+	tests := []struct {
+		name     string
+		caller   string
+		nickname string
+		want     string
+		code     errors.Code
+	}{
+		{"session replaces the body", "member", "guest", "member", ""},
+		{"no session, user without password", "", "guest", "guest", ""},
+		{"no session, user with password", "", "member", "", errors.CodeUnauthorized},
+		{"no session, unknown user", "", "nobody", "nobody", ""},
+	}
+
+	u := newTestUseCase(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actor, err := u.Actor(callerContext(tt.caller), tt.nickname)
+			if tt.code != "" {
+				if errors.From(err).Code != tt.code {
+					t.Fatalf("Actor() error = %v, want code %v", err, tt.code)
+				}
+				return
+			}
+			if err != nil || actor != tt.want {
+				t.Fatalf("Actor() = %q, %v, want %q", actor, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdminWithoutSession(t *testing.T) {
+	u := newTestUseCase(t)
+	ctx := context.Background()
+
+	if err := u.Authorize(ctx, "", models.RoleAdmin); errors.From(err).Code != errors.CodeUnauthorized {
+		t.Errorf("Authorize() = %v, want code %v", err, errors.CodeUnauthorized)
+	}
+	if _, err := u.Actor(ctx, "ADMIN"); errors.From(err).Code != errors.CodeUnauthorized {
+		t.Errorf("Actor(ADMIN) = %v, want code %v", err, errors.CodeUnauthorized)
+	}
+}
+
+func TestCheckAdmins(t *testing.T) {
+	tests := []struct {
+		admins []string
+		code   errors.Code
+	}{
+		{nil, ""},
+		{[]string{"admin", "OWNER"}, ""},
+		{[]string{"admin", "unclaimed"}, errors.CodeUnauthorized},
+		{[]string{"unregistered"}, errors.CodeNotFound},
+	}
+
+	storage := memory.NewStorage()
+	userRepo := user_repo.NewSessionMemoryRepository(storage)
+	for _, newUser := range []*models.User{
+		{NickName: "admin", Email: "admin@example.com", PasswordHash: "hash"},
+		{NickName: "owner", Email: "owner@example.com", PasswordHash: "hash"},
+		{NickName: "unclaimed", Email: "unclaimed@example.com"},
+	} {
+		if err := userRepo.InsertUser(context.Background(), newUser); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tt := range tests {
+		err := CheckAdmins(context.Background(), userRepo, tt.admins)
+		if (err == nil) != (tt.code == "") || err != nil && errors.From(err).Code != tt.code {
+			t.Errorf("CheckAdmins(%v) = %v, want code %q", tt.admins, err, tt.code)
+		}
+	}
+}
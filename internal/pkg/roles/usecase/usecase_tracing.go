@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/roles"
+	"github.com/forum-api-back/pkg/tracing"
+)
+
+// TracedUseCase records a span for every method called within a traced request.
+type TracedUseCase struct {
+	ucase roles.UseCase
+}
+
+func NewTracedUseCase(ucase roles.UseCase) roles.UseCase {
+	return &TracedUseCase{
+		ucase: ucase,
+	}
+}
+
+func (u *TracedUseCase) GetModerators(ctx context.Context, forumSlug string) (_ []*models.User, err error) {
+	ctx, span := tracing.Start(ctx, "roles.usecase.GetModerators")
+	defer func() { span.End(err) }()
+	return u.ucase.GetModerators(ctx, forumSlug)
+}
+
+func (u *TracedUseCase) AddModerator(ctx context.Context, forumSlug,
+	nickname string) (_ *models.User, err error) {
+	ctx, span := tracing.Start(ctx, "roles.usecase.AddModerator")
+	defer func() { span.End(err) }()
+	return u.ucase.AddModerator(ctx, forumSlug, nickname)
+}
+
+func (u *TracedUseCase) RemoveModerator(ctx context.Context, forumSlug, nickname string) (err error) {
+	ctx, span := tracing.Start(ctx, "roles.usecase.RemoveModerator")
+	defer func() { span.End(err) }()
+	return u.ucase.RemoveModerator(ctx, forumSlug, nickname)
+}
+
+func (u *TracedUseCase) Role(ctx context.Context, forumSlug string) (_ models.Role, err error) {
+	ctx, span := tracing.Start(ctx, "roles.usecase.Role")
+	defer func() { span.End(err) }()
+	return u.ucase.Role(ctx, forumSlug)
+}
+
+func (u *TracedUseCase) Authorize(ctx context.Context, forumSlug string, required models.Role) (err error) {
+	ctx, span := tracing.Start(ctx, "roles.usecase.Authorize")
+	defer func() { span.End(err) }()
+	return u.ucase.Authorize(ctx, forumSlug, required)
+}
+
+func (u *TracedUseCase) AuthorizeAuthor(ctx context.Context, forumSlug, author string,
+	required models.Role) (err error) {
+	ctx, span := tracing.Start(ctx, "roles.usecase.AuthorizeAuthor")
+	defer func() { span.End(err) }()
+	return u.ucase.AuthorizeAuthor(ctx, forumSlug, author, required)
+}
+
+func (u *TracedUseCase) Actor(ctx context.Context, nickname string) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "roles.usecase.Actor")
+	defer func() { span.End(err) }()
+	return u.ucase.Actor(ctx, nickname)
+}
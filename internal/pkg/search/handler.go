@@ -0,0 +1,7 @@
+package search
+
+import "github.com/valyala/fasthttp"
+
+type Handler interface {
+	Search(ctx *fasthttp.RequestCtx)
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/search"
+	"github.com/forum-api-back/pkg/errors"
+	"github.com/forum-api-back/pkg/tools/http_utils"
+
+	"github.com/valyala/fasthttp"
+)
+
+type SearchHandler struct {
+	SearchUCase search.UseCase
+}
+
+func NewHandler(searchUCase search.UseCase) search.Handler {
+	return &SearchHandler{
+		SearchUCase: searchUCase,
+	}
+}
+
+func (h *SearchHandler) Search(ctx *fasthttp.RequestCtx) {
+	query := &models.SearchQuery{
+		Query:  string(ctx.FormValue("query")),
+		Type:   string(ctx.FormValue("type")),
+		Forum:  string(ctx.FormValue("forum")),
+		Thread: string(ctx.FormValue("thread")),
+		Author: string(ctx.FormValue("user")),
+		Limit:  models.MaxSearchLimit,
+	}
+
+	details := make([]*errors.FieldError, 0)
+	if query.Query == "" {
+		details = append(details, &errors.FieldError{Field: "query", Message: "must not be empty"})
+	}
+
+	switch query.Type {
+	case "", models.SearchTypePost, models.SearchTypeThread:
+	default:
+		details = append(details, &errors.FieldError{Field: "type", Message: "must be one of post, thread"})
+	}
+
+	parseTime := func(field string, value *time.Time) {
+		if arg := ctx.FormValue(field); len(arg) != 0 {
+			parsedTime, err := time.Parse(time.RFC3339, string(arg))
+			if err != nil {
+				details = append(details, &errors.FieldError{Field: field, Message: "must be an RFC 3339 date"})
+				return
+			}
+			*value = parsedTime
+		}
+	}
+	parseTime("since", &query.Since)
+	parseTime("until", &query.Until)
+
+	parseUint := func(field string, value *uint64, max uint64) {
+		if arg := ctx.FormValue(field); len(arg) != 0 {
+			parsedValue, err := strconv.ParseUint(string(arg), 10, 64)
+			switch {
+			case err != nil:
+				details = append(details, &errors.FieldError{Field: field, Message: "must be a non-negative integer"})
+			case parsedValue > max:
+				details = append(details, &errors.FieldError{Field: field, Message: "must not be greater than " +
+					strconv.FormatUint(max, 10)})
+			default:
+				*value = parsedValue
+			}
+		}
+	}
+	parseUint("limit", &query.Limit, models.MaxSearchLimit)
+	parseUint("offset", &query.Offset, models.MaxSearchOffset)
+
+	if len(details) != 0 {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(details...))
+		return
+	}
+
+	hits, err := h.SearchUCase.Search(http_utils.Context(ctx), query)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, hits, http.StatusOK)
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/pkg/errors"
+
+	"github.com/valyala/fasthttp"
+)
+
+// recordingUseCase keeps the query it is asked for.
+type recordingUseCase struct {
+	query *models.SearchQuery
+}
+
+func (u *recordingUseCase) Search(ctx context.Context, query *models.SearchQuery) ([]*models.SearchHit, error) {
+	u.query = query
+	return []*models.SearchHit{}, nil
+}
+
+func TestSearchBoundsPages(t *testing.T) {
+	tests := []struct {
+		args   string
+		status int
+		field  string
+		limit  uint64
+		offset uint64
+	}{
+		{"", http.StatusOK, "", 100, 0},
+		{"&limit=20&offset=40", http.StatusOK, "", 20, 40},
+		{"&limit=100&offset=10000", http.StatusOK, "", 100, 10000},
+		{"&limit=101", http.StatusBadRequest, "limit", 0, 0},
+		{"&limit=18446744073709551615", http.StatusBadRequest, "limit", 0, 0},
+		{"&offset=10001", http.StatusBadRequest, "offset", 0, 0},
+		{"&offset=-1", http.StatusBadRequest, "offset", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.args, func(t *testing.T) {
+			searchUCase := &recordingUseCase{}
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.SetRequestURI("/api/search?query=word" + tt.args)
+
+			NewHandler(searchUCase).Search(ctx)
+
+			if ctx.Response.StatusCode() != tt.status {
+				t.Fatalf("status = %d, want %d", ctx.Response.StatusCode(), tt.status)
+			}
+			if tt.status != http.StatusOK {
+				response := &errors.Error{}
+				if err := json.Unmarshal(ctx.Response.Body(), response); err != nil {
+					t.Fatal(err)
+				}
+				if len(response.Details) != 1 || response.Details[0].Field != tt.field {
+					t.Errorf("details = %s, want one about %s", ctx.Response.Body(), tt.field)
+				}
+				if searchUCase.query != nil {
+					t.Errorf("rejected query %+v was searched", searchUCase.query)
+				}
+				return
+			}
+			if searchUCase.query.Limit != tt.limit || searchUCase.query.Offset != tt.offset {
+				t.Errorf("limit, offset = %d, %d, want %d, %d",
+					searchUCase.query.Limit, searchUCase.query.Offset, tt.limit, tt.offset)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package search
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
+
+// Repository returns hits ordered by rank, skipping query.Offset of them
+// and returning at most query.Limit.
+type Repository interface {
+	SearchPosts(ctx context.Context, query *models.SearchQuery) ([]*models.SearchHit, error)
+	SearchThreads(ctx context.Context, query *models.SearchQuery) ([]*models.SearchHit, error)
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/search"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+)
+
+// Weights of the thread title and message, the defaults ts_rank uses for
+// the A and B labels.
+const (
+	titleWeight   = 1.0
+	messageWeight = 0.4
+)
+
+// Snippets keep headlineWords words of the document, starting a few words
+// before the first match.
+const (
+	headlineWords   = 35
+	headlineContext = 10
+)
+
+type MemoryRepository struct {
+	storage *memory.Storage
+}
+
+func NewSessionMemoryRepository(storage *memory.Storage) search.Repository {
+	return &MemoryRepository{
+		storage: storage,
+	}
+}
+
+func (r *MemoryRepository) SearchPosts(ctx context.Context, query *models.SearchQuery) ([]*models.SearchHit, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	textQuery := parseTextQuery(query.Query)
+	hits := make([]*models.SearchHit, 0)
+	for _, selectedPost := range r.storage.Posts {
+		if !matchScope(query, selectedPost.Forum, selectedPost.Thread, selectedPost.Author, selectedPost.DateCreated) {
+			continue
+		}
+
+		rank, ok := textQuery.rank(weightedText{selectedPost.Message, messageWeight})
+		if !ok {
+			continue
+		}
+
+		copiedPost := selectedPost.Post
+		hits = append(hits, &models.SearchHit{
+			Type:    models.SearchTypePost,
+			Rank:    rank,
+			Snippet: textQuery.headline(selectedPost.Message),
+			Post:    &copiedPost,
+		})
+	}
+
+	return pageHits(hits, query), nil
+}
+
+func (r *MemoryRepository) SearchThreads(ctx context.Context, query *models.SearchQuery) ([]*models.SearchHit, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	textQuery := parseTextQuery(query.Query)
+	hits := make([]*models.SearchHit, 0)
+	for _, selectedThread := range r.storage.Threads {
+		if !matchScope(query, selectedThread.Forum, selectedThread.Id,
+			selectedThread.AuthorNickName, selectedThread.DateCreated) {
+			continue
+		}
+
+		rank, ok := textQuery.rank(
+			weightedText{selectedThread.Title, titleWeight},
+			weightedText{selectedThread.Message, messageWeight},
+		)
+		if !ok {
+			continue
+		}
+
+		copiedThread := *selectedThread
+		hits = append(hits, &models.SearchHit{
+			Type:    models.SearchTypeThread,
+			Rank:    rank,
+			Snippet: textQuery.headline(selectedThread.Title + " " + selectedThread.Message),
+			Thread:  &copiedThread,
+		})
+	}
+
+	return pageHits(hits, query), nil
+}
+
+func matchScope(query *models.SearchQuery, forumSlug string, threadId uint64,
+	author string, dateCreated time.Time) bool {
+	switch {
+	case query.Forum != "" && memory.Key(query.Forum) != memory.Key(forumSlug):
+		return false
+	case query.ThreadId != 0 && query.ThreadId != threadId:
+		return false
+	case query.Author != "" && memory.Key(query.Author) != memory.Key(author):
+		return false
+	case !query.Since.IsZero() && dateCreated.Before(query.Since):
+		return false
+	case !query.Until.IsZero() && !dateCreated.Before(query.Until):
+		return false
+	default:
+		return true
+	}
+}
+
+func pageHits(hits []*models.SearchHit, query *models.SearchQuery) []*models.SearchHit {
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Rank != hits[j].Rank {
+			return hits[i].Rank > hits[j].Rank
+		}
+		return hitId(hits[i]) < hitId(hits[j])
+	})
+
+	if query.Offset >= uint64(len(hits)) {
+		return hits[:0]
+	}
+	hits = hits[query.Offset:]
+	if uint64(len(hits)) > query.Limit {
+		hits = hits[:query.Limit]
+	}
+	return hits
+}
+
+func hitId(hit *models.SearchHit) uint64 {
+	if hit.Post != nil {
+		return hit.Post.Id
+	}
+	return hit.Thread.Id
+}
+
+// textQuery is a simplified websearch_to_tsquery: every word has to occur
+// in the document and words prefixed with "-" must not. Words are matched
+// case-insensitively but, unlike PostgreSQL, without stemming.
+type textQuery struct {
+	include map[string]bool
+	exclude map[string]bool
+}
+
+func parseTextQuery(query string) *textQuery {
+	textQuery := &textQuery{
+		include: make(map[string]bool),
+		exclude: make(map[string]bool),
+	}
+	for _, field := range strings.Fields(query) {
+		exclude := strings.HasPrefix(field, "-")
+		for _, span := range wordSpans(field) {
+			word := strings.ToLower(field[span[0]:span[1]])
+			if exclude {
+				textQuery.exclude[word] = true
+			} else {
+				textQuery.include[word] = true
+			}
+		}
+	}
+
+	return textQuery
+}
+
+type weightedText struct {
+	text   string
+	weight float64
+}
+
+// rank scores a document made of weighted texts the way ts_rank sums the
+// weights of matched words.
+func (q *textQuery) rank(document ...weightedText) (float64, bool) {
+	if len(q.include) == 0 {
+		return 0, false
+	}
+
+	var rank float64
+	found := make(map[string]bool, len(q.include))
+	for _, part := range document {
+		for _, span := range wordSpans(part.text) {
+			word := strings.ToLower(part.text[span[0]:span[1]])
+			if q.exclude[word] {
+				return 0, false
+			}
+			if q.include[word] {
+				found[word] = true
+				rank += part.weight * 0.1
+			}
+		}
+	}
+
+	return rank, len(found) == len(q.include)
+}
+
+// headline highlights matched words with <b></b> like ts_headline does.
+func (q *textQuery) headline(text string) string {
+	spans := wordSpans(text)
+	from := 0
+	for i, span := range spans {
+		if q.include[strings.ToLower(text[span[0]:span[1]])] {
+			if i > headlineContext {
+				from = i - headlineContext
+			}
+			break
+		}
+	}
+	to := from + headlineWords
+	if to > len(spans) {
+		to = len(spans)
+	}
+
+	var headline strings.Builder
+	for i := from; i < to; i++ {
+		if i > from {
+			headline.WriteString(text[spans[i-1][1]:spans[i][0]])
+		}
+		word := text[spans[i][0]:spans[i][1]]
+		if q.include[strings.ToLower(word)] {
+			headline.WriteString("<b>" + word + "</b>")
+		} else {
+			headline.WriteString(word)
+		}
+	}
+
+	return headline.String()
+}
+
+// wordSpans returns the byte offsets of the words of text.
+func wordSpans(text string) [][2]int {
+	spans := make([][2]int, 0)
+	start := -1
+	for i, r := range text {
+		isWordRune := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWordRune && start < 0:
+			start = i
+		case !isWordRune && start >= 0:
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(text)})
+	}
+
+	return spans
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/search"
+	"github.com/forum-api-back/pkg/logger"
+	"github.com/forum-api-back/pkg/metrics"
+)
+
+// MeasuredRepository observes how long every method takes and logs the
+// calls taking slowQuery or longer.
+type MeasuredRepository struct {
+	repo      search.Repository
+	durations *metrics.HistogramVec
+	slowQuery time.Duration
+}
+
+// NewMeasuredRepository observes the durations of the methods of repo into
+// durations, labeled with the repository and the method. Calls taking
+// slowQuery or longer are logged as well, unless it is zero.
+func NewMeasuredRepository(repo search.Repository, durations *metrics.HistogramVec,
+	slowQuery time.Duration) search.Repository {
+	return &MeasuredRepository{
+		repo:      repo,
+		durations: durations,
+		slowQuery: slowQuery,
+	}
+}
+
+func (r *MeasuredRepository) SearchPosts(ctx context.Context, query *models.SearchQuery) ([]*models.SearchHit, error) {
+	defer r.observe(ctx, "SearchPosts", time.Now())
+	return r.repo.SearchPosts(ctx, query)
+}
+
+func (r *MeasuredRepository) SearchThreads(ctx context.Context,
+	query *models.SearchQuery) ([]*models.SearchHit, error) {
+	defer r.observe(ctx, "SearchThreads", time.Now())
+	return r.repo.SearchThreads(ctx, query)
+}
+
+func (r *MeasuredRepository) observe(ctx context.Context, method string, start time.Time) {
+	elapsed := time.Since(start)
+	r.durations.With("search", method).Observe(elapsed.Seconds())
+	if r.slowQuery > 0 && elapsed >= r.slowQuery {
+		logger.FromContext(ctx).Warn("slow query", "repository", "search", "method", method, "duration", elapsed)
+	}
+}
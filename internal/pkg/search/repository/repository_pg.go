@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/search"
+	"github.com/forum-api-back/pkg/errors"
+	"github.com/forum-api-back/pkg/tools/sql_utils"
+)
+
+const headlineOptions = "StartSel=<b>, StopSel=</b>, MaxWords=35, MinWords=15, MaxFragments=2"
+
+type PostgresqlRepository struct {
+	db *sql.DB
+}
+
+func NewSessionPostgresqlRepository(db *sql.DB) search.Repository {
+	return &PostgresqlRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresqlRepository) SearchPosts(ctx context.Context, query *models.SearchQuery) ([]*models.SearchHit, error) {
+	conditions, args := searchConditions(query, "thread_id")
+	args = append(args, query.Limit, query.Offset)
+
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT id, parent_message_id, author_nickname, message, "+
+			"is_edited, forum_slug, thread_id, date_created, rank, "+
+			"ts_headline('english', message, websearch_to_tsquery('english', $1), '"+headlineOptions+"') "+
+			"FROM ( "+
+			"	SELECT id, parent_message_id, author_nickname, message, "+
+			"		is_edited, forum_slug, thread_id, date_created, "+
+			"		ts_rank(search_vector, websearch_to_tsquery('english', $1)) AS rank "+
+			"	FROM posts "+
+			"	WHERE "+conditions+
+			"	ORDER BY rank DESC, id "+
+			fmt.Sprintf("	LIMIT $%d OFFSET $%d ", len(args)-1, len(args))+
+			") AS hits "+
+			"ORDER BY rank DESC, id",
+		args...,
+	)
+	if err != nil {
+		return nil, sql_utils.Error(err, errors.ErrNotFoundInDB)
+	}
+	defer rows.Close()
+
+	hits := make([]*models.SearchHit, 0)
+	for rows.Next() {
+		hit := &models.SearchHit{Type: models.SearchTypePost, Post: &models.Post{}}
+		err := rows.Scan(
+			&hit.Post.Id,
+			&hit.Post.Parent,
+			&hit.Post.Author,
+			&hit.Post.Message,
+			&hit.Post.IsEdited,
+			&hit.Post.Forum,
+			&hit.Post.Thread,
+			&hit.Post.DateCreated,
+			&hit.Rank,
+			&hit.Snippet,
+		)
+		if err != nil {
+			return nil, sql_utils.Error(err, errors.ErrNotFoundInDB)
+		}
+
+		hits = append(hits, hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, sql_utils.Error(err, errors.ErrNotFoundInDB)
+	}
+
+	return hits, nil
+}
+
+func (r *PostgresqlRepository) SearchThreads(ctx context.Context, query *models.SearchQuery) ([]*models.SearchHit, error) {
+	conditions, args := searchConditions(query, "id")
+	args = append(args, query.Limit, query.Offset)
+
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT id, slug, title, author_nickname, "+
+			"forum_slug, message, date_created, votes, rank, "+
+			"ts_headline('english', title || ' ' || message, websearch_to_tsquery('english', $1), '"+headlineOptions+"') "+
+			"FROM ( "+
+			"	SELECT id, slug, title, author_nickname, "+
+			"		forum_slug, message, date_created, votes, "+
+			"		ts_rank(search_vector, websearch_to_tsquery('english', $1)) AS rank "+
+			"	FROM threads "+
+			"	WHERE "+conditions+
+			"	ORDER BY rank DESC, id "+
+			fmt.Sprintf("	LIMIT $%d OFFSET $%d ", len(args)-1, len(args))+
+			") AS hits "+
+			"ORDER BY rank DESC, id",
+		args...,
+	)
+	if err != nil {
+		return nil, sql_utils.Error(err, errors.ErrNotFoundInDB)
+	}
+	defer rows.Close()
+
+	hits := make([]*models.SearchHit, 0)
+	for rows.Next() {
+		hit := &models.SearchHit{Type: models.SearchTypeThread, Thread: &models.Thread{}}
+		slug := sql.NullString{}
+		err := rows.Scan(
+			&hit.Thread.Id,
+			&slug,
+			&hit.Thread.Title,
+			&hit.Thread.AuthorNickName,
+			&hit.Thread.Forum,
+			&hit.Thread.Message,
+			&hit.Thread.DateCreated,
+			&hit.Thread.Votes,
+			&hit.Rank,
+			&hit.Snippet,
+		)
+		hit.Thread.Slug = slug.String
+		if err != nil {
+			return nil, sql_utils.Error(err, errors.ErrNotFoundInDB)
+		}
+
+		hits = append(hits, hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, sql_utils.Error(err, errors.ErrNotFoundInDB)
+	}
+
+	return hits, nil
+}
+
+// searchConditions builds the WHERE clause of a search; the text query is
+// always the first argument. threadColumn names the column holding the
+// thread id in the searched table.
+func searchConditions(query *models.SearchQuery, threadColumn string) (string, []interface{}) {
+	args := []interface{}{query.Query}
+	conditions := []string{"search_vector @@ websearch_to_tsquery('english', $1)"}
+	if query.Forum != "" {
+		args = append(args, query.Forum)
+		conditions = append(conditions, fmt.Sprintf("forum_slug = $%d", len(args)))
+	}
+	if query.ThreadId != 0 {
+		args = append(args, query.ThreadId)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", threadColumn, len(args)))
+	}
+	if query.Author != "" {
+		args = append(args, query.Author)
+		conditions = append(conditions, fmt.Sprintf("author_nickname = $%d", len(args)))
+	}
+	if !query.Since.IsZero() {
+		args = append(args, query.Since)
+		conditions = append(conditions, fmt.Sprintf("date_created >= $%d", len(args)))
+	}
+	if !query.Until.IsZero() {
+		args = append(args, query.Until)
+		conditions = append(conditions, fmt.Sprintf("date_created < $%d", len(args)))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/search"
+	"github.com/forum-api-back/pkg/tracing"
+)
+
+// TracedRepository records a span for every method called within a traced request.
+type TracedRepository struct {
+	repo search.Repository
+}
+
+func NewTracedRepository(repo search.Repository) search.Repository {
+	return &TracedRepository{
+		repo: repo,
+	}
+}
+
+func (r *TracedRepository) SearchPosts(ctx context.Context,
+	query *models.SearchQuery) (_ []*models.SearchHit, err error) {
+	ctx, span := tracing.Start(ctx, "search.repository.SearchPosts")
+	defer func() { span.End(err) }()
+	return r.repo.SearchPosts(ctx, query)
+}
+
+func (r *TracedRepository) SearchThreads(ctx context.Context,
+	query *models.SearchQuery) (_ []*models.SearchHit, err error) {
+	ctx, span := tracing.Start(ctx, "search.repository.SearchThreads")
+	defer func() { span.End(err) }()
+	return r.repo.SearchThreads(ctx, query)
+}
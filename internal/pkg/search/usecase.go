@@ -0,0 +1,11 @@
+package search
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
+
+type UseCase interface {
+	Search(ctx context.Context, query *models.SearchQuery) ([]*models.SearchHit, error)
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/forum-api-back/internal/pkg/forum"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/search"
+	"github.com/forum-api-back/internal/pkg/thread"
+	"github.com/forum-api-back/internal/pkg/user"
+	"github.com/forum-api-back/pkg/errors"
+)
+
+type SearchUseCase struct {
+	SearchRepo search.Repository
+	ForumRepo  forum.Repository
+	ThreadRepo thread.Repository
+	UserRepo   user.Repository
+}
+
+func NewUseCase(searchRepo search.Repository, forumRepo forum.Repository,
+	threadRepo thread.Repository, userRepo user.Repository) search.UseCase {
+	return &SearchUseCase{
+		SearchRepo: searchRepo,
+		ForumRepo:  forumRepo,
+		ThreadRepo: threadRepo,
+		UserRepo:   userRepo,
+	}
+}
+
+func (u *SearchUseCase) Search(ctx context.Context, query *models.SearchQuery) ([]*models.SearchHit, error) {
+	if err := u.resolveScope(ctx, query); err != nil {
+		return nil, err
+	}
+
+	switch query.Type {
+	case models.SearchTypePost:
+		return u.SearchRepo.SearchPosts(ctx, query)
+	case models.SearchTypeThread:
+		return u.SearchRepo.SearchThreads(ctx, query)
+	}
+
+	// Both kinds are ranked together: every repository returns the hits
+	// up to the end of the requested page, which is cut after merging.
+	merged := *query
+	merged.Limit = query.Offset + query.Limit
+	merged.Offset = 0
+
+	posts, err := u.SearchRepo.SearchPosts(ctx, &merged)
+	if err != nil {
+		return nil, err
+	}
+	threads, err := u.SearchRepo.SearchThreads(ctx, &merged)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := append(threads, posts...)
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Rank > hits[j].Rank
+	})
+
+	if query.Offset >= uint64(len(hits)) {
+		return []*models.SearchHit{}, nil
+	}
+	hits = hits[query.Offset:]
+	if uint64(len(hits)) > query.Limit {
+		hits = hits[:query.Limit]
+	}
+
+	return hits, nil
+}
+
+// resolveScope checks that the forum, thread and user the search is
+// limited to exist and finds the id of the thread.
+func (u *SearchUseCase) resolveScope(ctx context.Context, query *models.SearchQuery) error {
+	if query.Forum != "" {
+		selectedForum, err := u.ForumRepo.SelectForumBySlug(ctx, query.Forum)
+		if err != nil {
+			return errors.Translate(err, errors.ErrForumNotFound.WithMessage("Can't find forum by slug: %s", query.Forum))
+		}
+		query.Forum = selectedForum.Slug
+	}
+
+	if query.Thread != "" {
+		threadNotFound := errors.ErrThreadNotFound.WithMessage("Can't find thread by slug or id: %s", query.Thread)
+
+		var selectedThread *models.Thread
+		threadId, err := strconv.Atoi(query.Thread)
+		if err != nil {
+			selectedThread, err = u.ThreadRepo.SelectThreadBySlug(ctx, query.Thread)
+		} else if threadId >= 1 {
+			selectedThread, err = u.ThreadRepo.SelectThreadById(ctx, uint64(threadId))
+		} else {
+			return threadNotFound
+		}
+
+		if err != nil {
+			return errors.Translate(err, threadNotFound)
+		}
+		query.ThreadId = selectedThread.Id
+	}
+
+	if query.Author != "" {
+		if _, err := u.UserRepo.SelectUserByNickName(ctx, query.Author); err != nil {
+			return errors.Translate(err, errors.ErrUserNotFound.WithMessage("Can't find user by nickname: %s", query.Author))
+		}
+	}
+
+	return nil
+}
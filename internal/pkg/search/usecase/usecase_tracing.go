@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/search"
+	"github.com/forum-api-back/pkg/tracing"
+)
+
+// TracedUseCase records a span for every method called within a traced request.
+type TracedUseCase struct {
+	ucase search.UseCase
+}
+
+func NewTracedUseCase(ucase search.UseCase) search.UseCase {
+	return &TracedUseCase{
+		ucase: ucase,
+	}
+}
+
+func (u *TracedUseCase) Search(ctx context.Context,
+	query *models.SearchQuery) (_ []*models.SearchHit, err error) {
+	ctx, span := tracing.Start(ctx, "search.usecase.Search")
+	defer func() { span.End(err) }()
+	return u.ucase.Search(ctx, query)
+}
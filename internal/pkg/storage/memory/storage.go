@@ -0,0 +1,221 @@
+package memory
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
+
+// Storage keeps every table of the forum schema in memory. Repositories
+// hold the lock while they work with the tables and use the helpers below
+// to reproduce what the PostgreSQL triggers do.
+type Storage struct {
+	sync.RWMutex
+
+	Users           map[string]*models.User
+	UserEmails      map[string]string
+	Forums          map[string]*models.Forum
+	Threads         map[uint64]*models.Thread
+	ThreadSlugs     map[string]uint64
+	ThreadRevisions map[uint64][]*models.ThreadRevision
+	Posts           map[uint64]*Post
+	ThreadPosts     map[uint64][]uint64
+	PostRevisions   map[uint64][]*models.PostRevision
+	Authors         map[string]map[string]bool
+	Votes           map[uint64]map[string]int
+	Sessions        map[string]*models.Session
+	Moderators      map[string]map[string]bool
+	Webhooks        map[uint64]*models.Webhook
+	Deliveries      map[uint64]*models.WebhookDelivery
+	Notifications   map[uint64]*models.Notification
+
+	lastThreadId       uint64
+	lastPostId         uint64
+	lastWebhookId      uint64
+	lastDeliveryId     uint64
+	lastNotificationId uint64
+}
+
+type Post struct {
+	models.Post
+	PathOfNesting []uint64
+}
+
+func NewStorage() *Storage {
+	s := &Storage{}
+	s.clear()
+	return s
+}
+
+// Key folds a value the way CITEXT columns compare it.
+func Key(value string) string {
+	return strings.ToLower(value)
+}
+
+// Now returns the current time with the precision of TIMESTAMP(3).
+func Now() time.Time {
+	return time.Now().Truncate(time.Millisecond)
+}
+
+func (s *Storage) NextThreadId() uint64 {
+	s.lastThreadId++
+	return s.lastThreadId
+}
+
+func (s *Storage) NextPostId() uint64 {
+	s.lastPostId++
+	return s.lastPostId
+}
+
+func (s *Storage) NextWebhookId() uint64 {
+	s.lastWebhookId++
+	return s.lastWebhookId
+}
+
+func (s *Storage) NextDeliveryId() uint64 {
+	s.lastDeliveryId++
+	return s.lastDeliveryId
+}
+
+func (s *Storage) NextNotificationId() uint64 {
+	s.lastNotificationId++
+	return s.lastNotificationId
+}
+
+// Clear empties every table. Like TRUNCATE it keeps the id sequences.
+func (s *Storage) Clear() {
+	s.clear()
+}
+
+func (s *Storage) clear() {
+	s.Users = make(map[string]*models.User)
+	s.UserEmails = make(map[string]string)
+	s.Forums = make(map[string]*models.Forum)
+	s.Threads = make(map[uint64]*models.Thread)
+	s.ThreadSlugs = make(map[string]uint64)
+	s.ThreadRevisions = make(map[uint64][]*models.ThreadRevision)
+	s.Posts = make(map[uint64]*Post)
+	s.ThreadPosts = make(map[uint64][]uint64)
+	s.PostRevisions = make(map[uint64][]*models.PostRevision)
+	s.Authors = make(map[string]map[string]bool)
+	s.Votes = make(map[uint64]map[string]int)
+	s.Sessions = make(map[string]*models.Session)
+	s.Moderators = make(map[string]map[string]bool)
+	s.Webhooks = make(map[uint64]*models.Webhook)
+	s.Deliveries = make(map[uint64]*models.WebhookDelivery)
+	s.Notifications = make(map[uint64]*models.Notification)
+}
+
+func (s *Storage) UserByNickName(nickname string) (*models.User, bool) {
+	selectedUser, ok := s.Users[Key(nickname)]
+	return selectedUser, ok
+}
+
+func (s *Storage) ThreadBySlug(threadSlug string) (*models.Thread, bool) {
+	threadId, ok := s.ThreadSlugs[Key(threadSlug)]
+	if !ok {
+		return nil, false
+	}
+	return s.Threads[threadId], true
+}
+
+// AddAuthor mirrors the triggers filling the authors table on new
+// threads and posts.
+func (s *Storage) AddAuthor(nickname, forumSlug string) {
+	forumKey := Key(forumSlug)
+	if s.Authors[forumKey] == nil {
+		s.Authors[forumKey] = make(map[string]bool)
+	}
+	s.Authors[forumKey][Key(nickname)] = true
+}
+
+// RemoveAuthor mirrors the triggers dropping a user from the authors of
+// a forum once their last thread or post in it is gone.
+func (s *Storage) RemoveAuthor(nickname, forumSlug string) {
+	nicknameKey, forumKey := Key(nickname), Key(forumSlug)
+	for _, selectedThread := range s.Threads {
+		if Key(selectedThread.AuthorNickName) == nicknameKey && Key(selectedThread.Forum) == forumKey {
+			return
+		}
+	}
+	for _, selectedPost := range s.Posts {
+		if !selectedPost.IsDeleted && Key(selectedPost.Author) == nicknameKey && Key(selectedPost.Forum) == forumKey {
+			return
+		}
+	}
+
+	delete(s.Authors[forumKey], nicknameKey)
+}
+
+// RemoveThread deletes a thread with its posts, votes, revisions and
+// notifications like the cascading foreign keys and the triggers of the
+// removed rows do.
+func (s *Storage) RemoveThread(threadId uint64) {
+	selectedThread, ok := s.Threads[threadId]
+	if !ok {
+		return
+	}
+
+	selectedForum := s.Forums[Key(selectedThread.Forum)]
+	authors := map[string]string{Key(selectedThread.AuthorNickName): selectedThread.AuthorNickName}
+	for _, postId := range s.ThreadPosts[threadId] {
+		selectedPost := s.Posts[postId]
+		if !selectedPost.IsDeleted {
+			selectedForum.Posts--
+			authors[Key(selectedPost.Author)] = selectedPost.Author
+		}
+		delete(s.Posts, postId)
+		delete(s.PostRevisions, postId)
+	}
+	delete(s.ThreadPosts, threadId)
+	delete(s.Votes, threadId)
+	delete(s.ThreadRevisions, threadId)
+	for notificationId, notification := range s.Notifications {
+		if notification.Thread == threadId {
+			delete(s.Notifications, notificationId)
+		}
+	}
+	if selectedThread.Slug != "" {
+		delete(s.ThreadSlugs, Key(selectedThread.Slug))
+	}
+	delete(s.Threads, threadId)
+	selectedForum.Threads--
+
+	for _, nickname := range authors {
+		s.RemoveAuthor(nickname, selectedThread.Forum)
+	}
+}
+
+// RemoveWebhook deletes a webhook with its deliveries like the cascading
+// foreign key does.
+func (s *Storage) RemoveWebhook(webhookId uint64) {
+	for deliveryId, delivery := range s.Deliveries {
+		if delivery.Webhook == webhookId {
+			delete(s.Deliveries, deliveryId)
+		}
+	}
+	delete(s.Webhooks, webhookId)
+}
+
+// ComparePaths orders paths of nesting like PostgreSQL compares arrays.
+func ComparePaths(a, b []uint64) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	default:
+		return 0
+	}
+}
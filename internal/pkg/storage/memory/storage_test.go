@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
+
+func TestComparePaths(t *testing.T) {
+	tests := []struct {
+		a, b []uint64
+		want int
+	}{
+		{[]uint64{1}, []uint64{1}, 0},
+		{[]uint64{1}, []uint64{2}, -1},
+		{[]uint64{2}, []uint64{1, 5}, 1},
+		{[]uint64{1}, []uint64{1, 4}, -1},
+		{[]uint64{1, 4, 6}, []uint64{1, 5}, -1},
+		{[]uint64{1, 10}, []uint64{1, 9, 11}, 1},
+		{nil, []uint64{1}, -1},
+	}
+
+	for _, tt := range tests {
+		if got := ComparePaths(tt.a, tt.b); got != tt.want {
+			t.Errorf("ComparePaths(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestRemoveAuthor(t *testing.T) {
+	tests := []struct {
+		name     string
+		threads  []*models.Thread
+		posts    []*Post
+		nickname string
+		want     map[string]bool
+	}{
+		{
+			name:     "without threads and posts",
+			nickname: "Bob",
+			want:     map[string]bool{"alice": true},
+		},
+		{
+			name:     "thread left",
+			threads:  []*models.Thread{{Id: 1, AuthorNickName: "BOB", Forum: "F"}},
+			nickname: "bob",
+			want:     map[string]bool{"alice": true, "bob": true},
+		},
+		{
+			name:     "post left",
+			posts:    []*Post{{Post: models.Post{Id: 1, Author: "bob", Forum: "f"}}},
+			nickname: "bob",
+			want:     map[string]bool{"alice": true, "bob": true},
+		},
+		{
+			name:     "only deleted posts left",
+			posts:    []*Post{{Post: models.Post{Id: 1, Author: "bob", Forum: "f", IsDeleted: true}}},
+			nickname: "bob",
+			want:     map[string]bool{"alice": true},
+		},
+		{
+			name:     "thread in another forum",
+			threads:  []*models.Thread{{Id: 1, AuthorNickName: "bob", Forum: "g"}},
+			nickname: "bob",
+			want:     map[string]bool{"alice": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStorage()
+			s.AddAuthor("Alice", "f")
+			s.AddAuthor("Bob", "F")
+			for _, selectedThread := range tt.threads {
+				s.Threads[selectedThread.Id] = selectedThread
+			}
+			for _, selectedPost := range tt.posts {
+				s.Posts[selectedPost.Id] = selectedPost
+			}
+
+			s.RemoveAuthor(tt.nickname, "f")
+			if !reflect.DeepEqual(s.Authors["f"], tt.want) {
+				t.Errorf("authors = %v, want %v", s.Authors["f"], tt.want)
+			}
+		})
+	}
+}
@@ -8,4 +8,8 @@ type Handler interface {
 	GetThreadDetails(ctx *fasthttp.RequestCtx)
 	UpdateThreadDetails(ctx *fasthttp.RequestCtx)
 	UpdateThreadVote(ctx *fasthttp.RequestCtx)
+	ArchiveThread(ctx *fasthttp.RequestCtx)
+	UnarchiveThread(ctx *fasthttp.RequestCtx)
+	DeleteThread(ctx *fasthttp.RequestCtx)
+	GetThreadHistory(ctx *fasthttp.RequestCtx)
 }
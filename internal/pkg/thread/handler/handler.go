@@ -2,7 +2,6 @@ package handler
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -28,33 +27,33 @@ func NewHandler(threadUCase thread.UseCase) thread.Handler {
 func (h *ThreadHandler) CreateNewThread(ctx *fasthttp.RequestCtx) {
 	threadInfo := &models.ThreadCreate{}
 	if err := json.Unmarshal(ctx.PostBody(), threadInfo); err != nil {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadRequest, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadRequest.Wrap(err))
 		return
 	}
 
 	forumSlug := ctx.UserValue("slug").(string)
 	if forumSlug == "" {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadArguments, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "slug", Message: "must not be empty"}))
 		return
 	}
 
-	newThread, err := h.ThreadUCase.CreateNewThread(forumSlug, threadInfo)
-	switch err {
-	case nil:
+	newThread, err := h.ThreadUCase.CreateNewThread(http_utils.Context(ctx), forumSlug, threadInfo)
+	switch {
+	case err == nil:
 		http_utils.SetJSONResponse(ctx, newThread, http.StatusCreated)
-	case errors.ErrDataConflict:
-		http_utils.SetJSONResponse(ctx, errors.ErrDataConflict, http.StatusNotFound)
-	case errors.ErrAlreadyExists:
+	case errors.Is(err, errors.ErrAlreadyExists):
 		http_utils.SetJSONResponse(ctx, newThread, http.StatusConflict)
 	default:
-		http_utils.SetJSONResponse(ctx, errors.ErrInternalError, http.StatusInternalServerError)
+		http_utils.SetErrorResponse(ctx, err)
 	}
 }
 
 func (h *ThreadHandler) GetThreadsByForum(ctx *fasthttp.RequestCtx) {
 	forumSlug := ctx.UserValue("slug").(string)
 	if forumSlug == "" {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadArguments, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "slug", Message: "must not be empty"}))
 		return
 	}
 
@@ -73,80 +72,132 @@ func (h *ThreadHandler) GetThreadsByForum(ctx *fasthttp.RequestCtx) {
 		threadPaginator.Limit = uint64(parseLimit)
 	}
 
-	selectedThreads, err := h.ThreadUCase.GetThreadsByForum(forumSlug, threadPaginator)
-	switch err {
-	case nil:
-		http_utils.SetJSONResponse(ctx, selectedThreads, http.StatusOK)
-	case errors.ErrForumNotFound:
-		http_utils.SetJSONResponse(ctx, errors.Error{
-			Message: fmt.Sprintf("Can't find forum by slug: %s", forumSlug)}, http.StatusNotFound)
-	default:
-		http_utils.SetJSONResponse(ctx, errors.ErrInternalError, http.StatusInternalServerError)
+	selectedThreads, err := h.ThreadUCase.GetThreadsByForum(http_utils.Context(ctx), forumSlug, threadPaginator)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
 	}
+
+	http_utils.SetJSONResponse(ctx, selectedThreads, http.StatusOK)
 }
 
 func (h *ThreadHandler) GetThreadDetails(ctx *fasthttp.RequestCtx) {
 	threadSlugOrId := ctx.UserValue("slug_or_id").(string)
 	if threadSlugOrId == "" {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadArguments, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "slug_or_id", Message: "must not be empty"}))
 		return
 	}
 
-	threadDetails, err := h.ThreadUCase.GetThreadDetails(threadSlugOrId)
-	switch err {
-	case nil:
-		http_utils.SetJSONResponse(ctx, threadDetails, http.StatusOK)
-	case errors.ErrThreadNotFound:
-		http_utils.SetJSONResponse(ctx, errors.ErrThreadNotFound, http.StatusNotFound)
-	default:
-		http_utils.SetJSONResponse(ctx, errors.ErrInternalError, http.StatusInternalServerError)
+	threadDetails, err := h.ThreadUCase.GetThreadDetails(http_utils.Context(ctx), threadSlugOrId)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
 	}
+
+	http_utils.SetJSONResponse(ctx, threadDetails, http.StatusOK)
 }
 
 func (h *ThreadHandler) UpdateThreadDetails(ctx *fasthttp.RequestCtx) {
 	threadUpdate := &models.ThreadUpdate{}
 	if err := json.Unmarshal(ctx.PostBody(), threadUpdate); err != nil {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadRequest, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadRequest.Wrap(err))
 		return
 	}
 
 	threadSlugOrId := ctx.UserValue("slug_or_id").(string)
 	if threadSlugOrId == "" {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadArguments, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "slug_or_id", Message: "must not be empty"}))
 		return
 	}
 
-	updatedThread, err := h.ThreadUCase.UpdateThreadDetails(threadSlugOrId, threadUpdate)
-	switch err {
-	case nil:
-		http_utils.SetJSONResponse(ctx, updatedThread, http.StatusOK)
-	case errors.ErrThreadNotFound:
-		http_utils.SetJSONResponse(ctx, errors.ErrThreadNotFound, http.StatusNotFound)
-	default:
-		http_utils.SetJSONResponse(ctx, errors.ErrInternalError, http.StatusInternalServerError)
+	updatedThread, err := h.ThreadUCase.UpdateThreadDetails(http_utils.Context(ctx), threadSlugOrId, threadUpdate)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
 	}
+
+	http_utils.SetJSONResponse(ctx, updatedThread, http.StatusOK)
 }
 
 func (h *ThreadHandler) UpdateThreadVote(ctx *fasthttp.RequestCtx) {
 	threadVote := &models.ThreadVote{}
 	if err := json.Unmarshal(ctx.PostBody(), threadVote); err != nil {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadRequest, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadRequest.Wrap(err))
 		return
 	}
 
 	threadSlugOrId := ctx.UserValue("slug_or_id").(string)
 	if threadSlugOrId == "" {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadArguments, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "slug_or_id", Message: "must not be empty"}))
 		return
 	}
 
-	updatedThread, err := h.ThreadUCase.UpdateThreadVote(threadSlugOrId, threadVote)
-	switch err {
-	case nil:
-		http_utils.SetJSONResponse(ctx, updatedThread, http.StatusOK)
-	case errors.ErrThreadNotFound:
-		http_utils.SetJSONResponse(ctx, errors.ErrThreadNotFound, http.StatusNotFound)
-	default:
-		http_utils.SetJSONResponse(ctx, errors.ErrInternalError, http.StatusInternalServerError)
+	updatedThread, err := h.ThreadUCase.UpdateThreadVote(http_utils.Context(ctx), threadSlugOrId, threadVote)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, updatedThread, http.StatusOK)
+}
+
+func (h *ThreadHandler) ArchiveThread(ctx *fasthttp.RequestCtx) {
+	h.setThreadArchived(ctx, true)
+}
+
+func (h *ThreadHandler) UnarchiveThread(ctx *fasthttp.RequestCtx) {
+	h.setThreadArchived(ctx, false)
+}
+
+func (h *ThreadHandler) setThreadArchived(ctx *fasthttp.RequestCtx, isArchived bool) {
+	threadSlugOrId := ctx.UserValue("slug_or_id").(string)
+	if threadSlugOrId == "" {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "slug_or_id", Message: "must not be empty"}))
+		return
+	}
+
+	updatedThread, err := h.ThreadUCase.SetThreadArchived(http_utils.Context(ctx), threadSlugOrId, isArchived)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, updatedThread, http.StatusOK)
+}
+
+func (h *ThreadHandler) DeleteThread(ctx *fasthttp.RequestCtx) {
+	threadSlugOrId := ctx.UserValue("slug_or_id").(string)
+	if threadSlugOrId == "" {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "slug_or_id", Message: "must not be empty"}))
+		return
+	}
+
+	if err := h.ThreadUCase.DeleteThread(http_utils.Context(ctx), threadSlugOrId); err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
 	}
+
+	http_utils.SetJSONResponse(ctx, struct{}{}, http.StatusOK)
+}
+
+func (h *ThreadHandler) GetThreadHistory(ctx *fasthttp.RequestCtx) {
+	threadSlugOrId := ctx.UserValue("slug_or_id").(string)
+	if threadSlugOrId == "" {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "slug_or_id", Message: "must not be empty"}))
+		return
+	}
+
+	revisions, err := h.ThreadUCase.GetThreadHistory(http_utils.Context(ctx), threadSlugOrId)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, revisions, http.StatusOK)
 }
@@ -1,14 +1,29 @@
 package thread
 
-import "github.com/forum-api-back/internal/pkg/models"
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
 
 type Repository interface {
-	InsertThread(forumSlug string, threadInfo *models.ThreadCreate) (uint64, error)
-	SelectThreadBySlug(threadSlug string) (*models.Thread, error)
-	SelectThreadById(threadId uint64) (*models.Thread, error)
-	SelectThreadsByForum(forumSlug string, threadPaginator *models.ThreadPaginator) ([]*models.Thread, error)
-	UpdateThreadDetailsBySlug(threadSlug string, threadInfo *models.ThreadUpdate) (*models.Thread, error)
-	UpdateThreadDetailsById(threadId uint64, threadInfo *models.ThreadUpdate) (*models.Thread, error)
-	UpdateThreadVoteBySlug(threadSlug string, threadVote *models.ThreadVote) error
-	UpdateThreadVoteById(threadId uint64, threadVote *models.ThreadVote) error
+	InsertThread(ctx context.Context, forumSlug string, threadInfo *models.ThreadCreate) (uint64, error)
+	SelectThreadBySlug(ctx context.Context, threadSlug string) (*models.Thread, error)
+	SelectThreadById(ctx context.Context, threadId uint64) (*models.Thread, error)
+	SelectThreadsByForum(ctx context.Context, forumSlug string, threadPaginator *models.ThreadPaginator) ([]*models.Thread, error)
+	// UpdateThreadDetailsBySlug and UpdateThreadDetailsById change the
+	// title and the message of a thread and record them as a new revision
+	// made by editor. Unchanged threads get no revision.
+	UpdateThreadDetailsBySlug(ctx context.Context, threadSlug string, threadInfo *models.ThreadUpdate,
+		editor string) (*models.Thread, error)
+	UpdateThreadDetailsById(ctx context.Context, threadId uint64, threadInfo *models.ThreadUpdate,
+		editor string) (*models.Thread, error)
+	// SelectRevisionsById returns the revisions of an edited thread ordered
+	// by number. Threads that were never edited have none.
+	SelectRevisionsById(ctx context.Context, threadId uint64) ([]*models.ThreadRevision, error)
+	UpdateThreadVoteBySlug(ctx context.Context, threadSlug string, threadVote *models.ThreadVote) error
+	UpdateThreadVoteById(ctx context.Context, threadId uint64, threadVote *models.ThreadVote) error
+	UpdateThreadArchivedById(ctx context.Context, threadId uint64, isArchived bool) error
+	// DeleteThreadById removes a thread with its posts and votes.
+	DeleteThreadById(ctx context.Context, threadId uint64) error
 }
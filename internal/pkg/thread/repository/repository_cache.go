@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/forum-api-back/internal/pkg/cache"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/thread"
+)
+
+// CachedRepository reads threads through a cache. Threads are stored by
+// id, slugs only map to ids, so a change is dropped from one key. Creating
+// and deleting threads drops their forum, whose counters change.
+type CachedRepository struct {
+	thread.Repository
+	cache cache.Cache
+}
+
+func NewCachedRepository(repo thread.Repository, c cache.Cache) thread.Repository {
+	return &CachedRepository{
+		Repository: repo,
+		cache:      c,
+	}
+}
+
+func (r *CachedRepository) InsertThread(ctx context.Context, forumSlug string,
+	threadInfo *models.ThreadCreate) (uint64, error) {
+	threadId, err := r.Repository.InsertThread(ctx, forumSlug, threadInfo)
+	r.cache.Delete(ctx, cache.ForumKey(forumSlug))
+	return threadId, err
+}
+
+func (r *CachedRepository) SelectThreadBySlug(ctx context.Context, threadSlug string) (*models.Thread, error) {
+	var threadId uint64
+	if r.cache.Get(ctx, cache.ThreadSlugKey(threadSlug), &threadId) {
+		// The mapping outlives deleted threads, so the slug is checked.
+		var cachedThread models.Thread
+		if r.cache.Get(ctx, cache.ThreadKey(threadId), &cachedThread) && strings.EqualFold(cachedThread.Slug, threadSlug) {
+			return &cachedThread, nil
+		}
+	}
+
+	selectedThread, err := r.Repository.SelectThreadBySlug(ctx, threadSlug)
+	if err != nil {
+		return nil, err
+	}
+	r.store(ctx, selectedThread)
+
+	return selectedThread, nil
+}
+
+func (r *CachedRepository) SelectThreadById(ctx context.Context, threadId uint64) (*models.Thread, error) {
+	var cachedThread models.Thread
+	if r.cache.Get(ctx, cache.ThreadKey(threadId), &cachedThread) {
+		return &cachedThread, nil
+	}
+
+	selectedThread, err := r.Repository.SelectThreadById(ctx, threadId)
+	if err != nil {
+		return nil, err
+	}
+	r.store(ctx, selectedThread)
+
+	return selectedThread, nil
+}
+
+func (r *CachedRepository) UpdateThreadDetailsBySlug(ctx context.Context, threadSlug string,
+	threadInfo *models.ThreadUpdate, editor string) (*models.Thread, error) {
+	updatedThread, err := r.Repository.UpdateThreadDetailsBySlug(ctx, threadSlug, threadInfo, editor)
+	if err != nil {
+		return nil, err
+	}
+	r.cache.Delete(ctx, cache.ThreadKey(updatedThread.Id))
+
+	return updatedThread, nil
+}
+
+func (r *CachedRepository) UpdateThreadDetailsById(ctx context.Context, threadId uint64,
+	threadInfo *models.ThreadUpdate, editor string) (*models.Thread, error) {
+	updatedThread, err := r.Repository.UpdateThreadDetailsById(ctx, threadId, threadInfo, editor)
+	r.cache.Delete(ctx, cache.ThreadKey(threadId))
+	return updatedThread, err
+}
+
+func (r *CachedRepository) UpdateThreadVoteBySlug(ctx context.Context, threadSlug string,
+	threadVote *models.ThreadVote) error {
+	if err := r.Repository.UpdateThreadVoteBySlug(ctx, threadSlug, threadVote); err != nil {
+		return err
+	}
+
+	// The thread may be cached by id only, so its id is looked up.
+	selectedThread, err := r.Repository.SelectThreadBySlug(ctx, threadSlug)
+	if err != nil {
+		return err
+	}
+	r.cache.Delete(ctx, cache.ThreadKey(selectedThread.Id))
+
+	return nil
+}
+
+func (r *CachedRepository) UpdateThreadVoteById(ctx context.Context, threadId uint64,
+	threadVote *models.ThreadVote) error {
+	err := r.Repository.UpdateThreadVoteById(ctx, threadId, threadVote)
+	r.cache.Delete(ctx, cache.ThreadKey(threadId))
+	return err
+}
+
+func (r *CachedRepository) UpdateThreadArchivedById(ctx context.Context, threadId uint64, isArchived bool) error {
+	err := r.Repository.UpdateThreadArchivedById(ctx, threadId, isArchived)
+	r.cache.Delete(ctx, cache.ThreadKey(threadId))
+	return err
+}
+
+func (r *CachedRepository) DeleteThreadById(ctx context.Context, threadId uint64) error {
+	selectedThread, err := r.SelectThreadById(ctx, threadId)
+	if err != nil {
+		return r.Repository.DeleteThreadById(ctx, threadId)
+	}
+
+	err = r.Repository.DeleteThreadById(ctx, threadId)
+	r.cache.Delete(ctx, cache.ThreadKey(threadId), cache.ForumKey(selectedThread.Forum))
+	return err
+}
+
+func (r *CachedRepository) store(ctx context.Context, selectedThread *models.Thread) {
+	r.cache.Set(ctx, cache.ThreadKey(selectedThread.Id), *selectedThread)
+	if selectedThread.Slug != "" {
+		r.cache.Set(ctx, cache.ThreadSlugKey(selectedThread.Slug), selectedThread.Id)
+	}
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/cache"
+	"github.com/forum-api-back/internal/pkg/cache/lru"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/thread"
+)
+
+func TestCachedRepositoryInvalidates(t *testing.T) {
+	tests := []struct {
+		name string
+		// change changes thread 1, "first" of forum "f".
+		change      func(ctx context.Context, r thread.Repository) error
+		dropsForum  bool
+		dropsThread bool
+	}{
+		{"update by id", func(ctx context.Context, r thread.Repository) error {
+			_, err := r.UpdateThreadDetailsById(ctx, 1, &models.ThreadUpdate{Title: "Updated"}, "alice")
+			return err
+		}, false, true},
+		{"update by slug", func(ctx context.Context, r thread.Repository) error {
+			_, err := r.UpdateThreadDetailsBySlug(ctx, "FIRST", &models.ThreadUpdate{Message: "Updated"}, "alice")
+			return err
+		}, false, true},
+		{"vote by id", func(ctx context.Context, r thread.Repository) error {
+			return r.UpdateThreadVoteById(ctx, 1, &models.ThreadVote{NickName: "bob", Voice: 1})
+		}, false, true},
+		{"vote by slug", func(ctx context.Context, r thread.Repository) error {
+			return r.UpdateThreadVoteBySlug(ctx, "first", &models.ThreadVote{NickName: "bob", Voice: -1})
+		}, false, true},
+		{"archive", func(ctx context.Context, r thread.Repository) error {
+			return r.UpdateThreadArchivedById(ctx, 1, true)
+		}, false, true},
+		{"delete", func(ctx context.Context, r thread.Repository) error {
+			return r.DeleteThreadById(ctx, 1)
+		}, true, true},
+		{"insert", func(ctx context.Context, r thread.Repository) error {
+			_, err := r.InsertThread(ctx, "f", &models.ThreadCreate{Title: "Thread", AuthorNickName: "bob", DateCreated: day})
+			return err
+		}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memoryRepo, _ := newTestRepository(t)
+			hotCache := lru.NewCache(100, time.Hour)
+			r := NewCachedRepository(memoryRepo, hotCache)
+			ctx := context.Background()
+
+			if _, err := r.SelectThreadBySlug(ctx, "first"); err != nil {
+				t.Fatal(err)
+			}
+			hotCache.Set(ctx, cache.ForumKey("f"), models.Forum{Slug: "f"})
+
+			if err := tt.change(ctx, r); err != nil {
+				t.Fatal(err)
+			}
+
+			var cachedForum models.Forum
+			if isCached := hotCache.Get(ctx, cache.ForumKey("f"), &cachedForum); isCached == tt.dropsForum {
+				t.Errorf("forum cached = %v, want %v", isCached, !tt.dropsForum)
+			}
+			var cachedThread models.Thread
+			if isCached := hotCache.Get(ctx, cache.ThreadKey(1), &cachedThread); isCached == tt.dropsThread {
+				t.Errorf("thread cached = %v, want %v", isCached, !tt.dropsThread)
+			}
+
+			// Whatever is left in the cache matches the repository.
+			for _, read := range []func(r thread.Repository) (*models.Thread, error){
+				func(r thread.Repository) (*models.Thread, error) { return r.SelectThreadById(ctx, 1) },
+				func(r thread.Repository) (*models.Thread, error) { return r.SelectThreadBySlug(ctx, "first") },
+			} {
+				got, gotErr := read(r)
+				want, wantErr := read(memoryRepo)
+				if !reflect.DeepEqual(got, want) || (gotErr == nil) != (wantErr == nil) {
+					t.Errorf("cached read = %+v, %v, want %+v, %v", got, gotErr, want, wantErr)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+	"github.com/forum-api-back/internal/pkg/thread"
+	"github.com/forum-api-back/pkg/errors"
+)
+
+type MemoryRepository struct {
+	storage *memory.Storage
+}
+
+func NewSessionMemoryRepository(storage *memory.Storage) thread.Repository {
+	return &MemoryRepository{
+		storage: storage,
+	}
+}
+
+func (r *MemoryRepository) InsertThread(ctx context.Context, forumSlug string,
+	threadInfo *models.ThreadCreate) (uint64, error) {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	if threadInfo.Slug != "" {
+		if _, ok := r.storage.ThreadSlugs[memory.Key(threadInfo.Slug)]; ok {
+			return 0, errors.ErrDataConflict
+		}
+	}
+	if _, ok := r.storage.UserByNickName(threadInfo.AuthorNickName); !ok {
+		return 0, errors.ErrUserNotFound
+	}
+	selectedForum, ok := r.storage.Forums[memory.Key(forumSlug)]
+	if !ok {
+		return 0, errors.ErrForumNotFound
+	}
+
+	threadId := r.storage.NextThreadId()
+	r.storage.Threads[threadId] = &models.Thread{
+		Id:             threadId,
+		Title:          threadInfo.Title,
+		AuthorNickName: threadInfo.AuthorNickName,
+		Forum:          forumSlug,
+		Message:        threadInfo.Message,
+		Slug:           threadInfo.Slug,
+		DateCreated:    threadInfo.DateCreated.Truncate(time.Millisecond),
+	}
+	if threadInfo.Slug != "" {
+		r.storage.ThreadSlugs[memory.Key(threadInfo.Slug)] = threadId
+	}
+
+	selectedForum.Threads++
+	r.storage.AddAuthor(threadInfo.AuthorNickName, forumSlug)
+
+	return threadId, nil
+}
+
+func (r *MemoryRepository) SelectThreadBySlug(ctx context.Context, threadSlug string) (*models.Thread, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	selectedThread, ok := r.storage.ThreadBySlug(threadSlug)
+	if !ok {
+		return nil, errors.ErrThreadNotFound
+	}
+
+	copiedThread := *selectedThread
+	return &copiedThread, nil
+}
+
+func (r *MemoryRepository) SelectThreadById(ctx context.Context, threadId uint64) (*models.Thread, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	selectedThread, ok := r.storage.Threads[threadId]
+	if !ok {
+		return nil, errors.ErrThreadNotFound
+	}
+
+	copiedThread := *selectedThread
+	return &copiedThread, nil
+}
+
+func (r *MemoryRepository) SelectThreadsByForum(ctx context.Context, forumSlug string,
+	threadPaginator *models.ThreadPaginator) ([]*models.Thread, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	forumKey := memory.Key(forumSlug)
+	threads := make([]*models.Thread, 0)
+	for _, selectedThread := range r.storage.Threads {
+		if memory.Key(selectedThread.Forum) != forumKey {
+			continue
+		}
+		if !threadPaginator.Since.IsZero() {
+			if threadPaginator.SortOrder && selectedThread.DateCreated.After(threadPaginator.Since) ||
+				!threadPaginator.SortOrder && selectedThread.DateCreated.Before(threadPaginator.Since) {
+				continue
+			}
+		}
+		copiedThread := *selectedThread
+		threads = append(threads, &copiedThread)
+	}
+
+	sort.Slice(threads, func(i, j int) bool {
+		if !threads[i].DateCreated.Equal(threads[j].DateCreated) {
+			if threadPaginator.SortOrder {
+				return threads[i].DateCreated.After(threads[j].DateCreated)
+			}
+			return threads[i].DateCreated.Before(threads[j].DateCreated)
+		}
+		return threads[i].Id < threads[j].Id
+	})
+	if uint64(len(threads)) > threadPaginator.Limit {
+		threads = threads[:threadPaginator.Limit]
+	}
+
+	return threads, nil
+}
+
+func (r *MemoryRepository) UpdateThreadDetailsBySlug(ctx context.Context, threadSlug string,
+	threadInfo *models.ThreadUpdate, editor string) (*models.Thread, error) {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	selectedThread, _ := r.storage.ThreadBySlug(threadSlug)
+	return r.updateThreadDetails(selectedThread, threadInfo, editor)
+}
+
+func (r *MemoryRepository) UpdateThreadDetailsById(ctx context.Context, threadId uint64,
+	threadInfo *models.ThreadUpdate, editor string) (*models.Thread, error) {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	return r.updateThreadDetails(r.storage.Threads[threadId], threadInfo, editor)
+}
+
+func (r *MemoryRepository) updateThreadDetails(selectedThread *models.Thread,
+	threadInfo *models.ThreadUpdate, editor string) (*models.Thread, error) {
+	if threadInfo.Title == "" && threadInfo.Message == "" {
+		return nil, errors.ErrEmptyParameters
+	}
+	if selectedThread == nil {
+		return nil, errors.ErrThreadNotFound
+	}
+
+	title, message := selectedThread.Title, selectedThread.Message
+	if threadInfo.Title != "" {
+		title = threadInfo.Title
+	}
+	if threadInfo.Message != "" {
+		message = threadInfo.Message
+	}
+
+	if title != selectedThread.Title || message != selectedThread.Message {
+		revisions := r.storage.ThreadRevisions[selectedThread.Id]
+		if len(revisions) == 0 {
+			revisions = append(revisions, &models.ThreadRevision{
+				Revision:   1,
+				Editor:     selectedThread.AuthorNickName,
+				Title:      selectedThread.Title,
+				Message:    selectedThread.Message,
+				DateEdited: selectedThread.DateCreated,
+			})
+		}
+		dateEdited := memory.Now()
+		r.storage.ThreadRevisions[selectedThread.Id] = append(revisions, &models.ThreadRevision{
+			Revision:   uint64(len(revisions)) + 1,
+			Editor:     editor,
+			Title:      title,
+			Message:    message,
+			DateEdited: dateEdited,
+		})
+
+		selectedThread.Title = title
+		selectedThread.Message = message
+		selectedThread.IsEdited = true
+		selectedThread.EditedAt = &dateEdited
+	}
+
+	updatedThread := *selectedThread
+	return &updatedThread, nil
+}
+
+func (r *MemoryRepository) SelectRevisionsById(ctx context.Context, threadId uint64) ([]*models.ThreadRevision, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	revisions := make([]*models.ThreadRevision, 0, len(r.storage.ThreadRevisions[threadId]))
+	for _, revision := range r.storage.ThreadRevisions[threadId] {
+		copiedRevision := *revision
+		revisions = append(revisions, &copiedRevision)
+	}
+
+	return revisions, nil
+}
+
+func (r *MemoryRepository) UpdateThreadVoteBySlug(ctx context.Context, threadSlug string,
+	threadVote *models.ThreadVote) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	selectedThread, ok := r.storage.ThreadBySlug(threadSlug)
+	if !ok {
+		return nil
+	}
+
+	return r.updateThreadVote(selectedThread, threadVote)
+}
+
+func (r *MemoryRepository) UpdateThreadVoteById(ctx context.Context, threadId uint64,
+	threadVote *models.ThreadVote) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	selectedThread, ok := r.storage.Threads[threadId]
+	if !ok {
+		return errors.ErrThreadNotFound
+	}
+
+	return r.updateThreadVote(selectedThread, threadVote)
+}
+
+func (r *MemoryRepository) updateThreadVote(selectedThread *models.Thread,
+	threadVote *models.ThreadVote) error {
+	if _, ok := r.storage.UserByNickName(threadVote.NickName); !ok {
+		return errors.ErrUserNotFound
+	}
+
+	votes, ok := r.storage.Votes[selectedThread.Id]
+	if !ok {
+		votes = make(map[string]int)
+		r.storage.Votes[selectedThread.Id] = votes
+	}
+
+	nicknameKey := memory.Key(threadVote.NickName)
+	selectedThread.Votes += threadVote.Voice - votes[nicknameKey]
+	votes[nicknameKey] = threadVote.Voice
+
+	return nil
+}
+
+func (r *MemoryRepository) UpdateThreadArchivedById(ctx context.Context, threadId uint64, isArchived bool) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	selectedThread, ok := r.storage.Threads[threadId]
+	if !ok {
+		return errors.ErrThreadNotFound
+	}
+	selectedThread.IsArchived = isArchived
+
+	return nil
+}
+
+func (r *MemoryRepository) DeleteThreadById(ctx context.Context, threadId uint64) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	if _, ok := r.storage.Threads[threadId]; !ok {
+		return errors.ErrThreadNotFound
+	}
+	r.storage.RemoveThread(threadId)
+
+	return nil
+}
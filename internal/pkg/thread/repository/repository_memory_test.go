@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+	"github.com/forum-api-back/internal/pkg/thread"
+	"github.com/forum-api-back/pkg/errors"
+)
+
+var day = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// newTestRepository returns a repository over forums "f" and "g" with
+// threads 1 to 4 of forum "f" created on days 0, 2, 1 and 1 and thread 5
+// of forum "g".
+func newTestRepository(t *testing.T) (thread.Repository, *memory.Storage) {
+	t.Helper()
+
+	storage := memory.NewStorage()
+	for _, nickname := range []string{"alice", "bob"} {
+		storage.Users[nickname] = &models.User{NickName: nickname, Email: nickname + "@example.com"}
+	}
+	for _, forumSlug := range []string{"f", "g"} {
+		storage.Forums[forumSlug] = &models.Forum{Title: "Forum", AuthorNickName: "alice", Slug: forumSlug}
+	}
+
+	r := NewSessionMemoryRepository(storage)
+	threads := []struct {
+		forum  string
+		author string
+		slug   string
+		days   int
+	}{
+		{"f", "alice", "first", 0},
+		{"f", "bob", "", 2},
+		{"f", "alice", "", 1},
+		{"f", "alice", "", 1},
+		{"g", "bob", "", 0},
+	}
+	for _, newThread := range threads {
+		_, err := r.InsertThread(context.Background(), newThread.forum, &models.ThreadCreate{
+			Title:          "Thread",
+			AuthorNickName: newThread.author,
+			Slug:           newThread.slug,
+			DateCreated:    day.AddDate(0, 0, newThread.days),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return r, storage
+}
+
+func threadIds(threads []*models.Thread) []uint64 {
+	ids := make([]uint64, len(threads))
+	for i, selectedThread := range threads {
+		ids[i] = selectedThread.Id
+	}
+	return ids
+}
+
+func TestInsertThreadCounters(t *testing.T) {
+	_, storage := newTestRepository(t)
+
+	if got := storage.Forums["f"].Threads; got != 4 {
+		t.Errorf("threads of f = %d, want 4", got)
+	}
+	if got := storage.Forums["g"].Threads; got != 1 {
+		t.Errorf("threads of g = %d, want 1", got)
+	}
+	if got := storage.Authors["f"]; !reflect.DeepEqual(got, map[string]bool{"alice": true, "bob": true}) {
+		t.Errorf("authors of f = %v, want alice and bob", got)
+	}
+	if got := storage.Authors["g"]; !reflect.DeepEqual(got, map[string]bool{"bob": true}) {
+		t.Errorf("authors of g = %v, want bob", got)
+	}
+}
+
+func TestInsertThreadRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		forum  string
+		thread models.ThreadCreate
+		err    *errors.Error
+	}{
+		{"taken slug", "f", models.ThreadCreate{AuthorNickName: "alice", Slug: "FIRST"}, errors.ErrDataConflict},
+		{"unknown author", "f", models.ThreadCreate{AuthorNickName: "carol"}, errors.ErrUserNotFound},
+		{"unknown forum", "h", models.ThreadCreate{AuthorNickName: "alice"}, errors.ErrForumNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, storage := newTestRepository(t)
+
+			if _, err := r.InsertThread(context.Background(), tt.forum, &tt.thread); !errors.Is(err, tt.err) {
+				t.Fatalf("InsertThread() error = %v, want %v", err, tt.err)
+			}
+			if got := storage.Forums["f"].Threads; got != 4 {
+				t.Errorf("threads of f = %d, want 4", got)
+			}
+		})
+	}
+}
+
+func TestSelectThreadsByForum(t *testing.T) {
+	tests := []struct {
+		name      string
+		forum     string
+		paginator models.ThreadPaginator
+		want      []uint64
+	}{
+		{"ascending", "f", models.ThreadPaginator{Limit: 100}, []uint64{1, 3, 4, 2}},
+		{"descending", "f", models.ThreadPaginator{Limit: 100, SortOrder: true}, []uint64{2, 3, 4, 1}},
+		{"limit", "f", models.ThreadPaginator{Limit: 2}, []uint64{1, 3}},
+		{"since includes equal dates", "f", models.ThreadPaginator{Limit: 100, Since: day.AddDate(0, 0, 1)}, []uint64{3, 4, 2}},
+		{"since descending", "f", models.ThreadPaginator{Limit: 100, Since: day.AddDate(0, 0, 1), SortOrder: true}, []uint64{3, 4, 1}},
+		{"other case", "G", models.ThreadPaginator{Limit: 100}, []uint64{5}},
+		{"unknown forum", "h", models.ThreadPaginator{Limit: 100}, []uint64{}},
+	}
+
+	r, _ := newTestRepository(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threads, err := r.SelectThreadsByForum(context.Background(), tt.forum, &tt.paginator)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := threadIds(threads); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectThreadsByForum() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateThreadVoteById(t *testing.T) {
+	tests := []struct {
+		name  string
+		votes []models.ThreadVote
+		want  int
+	}{
+		{"one vote", []models.ThreadVote{{NickName: "alice", Voice: 1}}, 1},
+		{"two voters", []models.ThreadVote{{NickName: "alice", Voice: 1}, {NickName: "bob", Voice: -1}}, 0},
+		{"changed vote", []models.ThreadVote{{NickName: "alice", Voice: 1}, {NickName: "ALICE", Voice: -1}}, -1},
+		{"repeated vote", []models.ThreadVote{{NickName: "bob", Voice: 1}, {NickName: "bob", Voice: 1}}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := newTestRepository(t)
+			ctx := context.Background()
+			for i := range tt.votes {
+				if err := r.UpdateThreadVoteById(ctx, 1, &tt.votes[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			selectedThread, err := r.SelectThreadById(ctx, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if selectedThread.Votes != tt.want {
+				t.Errorf("votes = %d, want %d", selectedThread.Votes, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateThreadVoteByIdRejects(t *testing.T) {
+	r, _ := newTestRepository(t)
+	ctx := context.Background()
+
+	if err := r.UpdateThreadVoteById(ctx, 1, &models.ThreadVote{NickName: "carol", Voice: 1}); !errors.Is(err, errors.ErrUserNotFound) {
+		t.Errorf("UpdateThreadVoteById() error = %v, want %v", err, errors.ErrUserNotFound)
+	}
+	if err := r.UpdateThreadVoteById(ctx, 42, &models.ThreadVote{NickName: "alice", Voice: 1}); !errors.Is(err, errors.ErrThreadNotFound) {
+		t.Errorf("UpdateThreadVoteById() error = %v, want %v", err, errors.ErrThreadNotFound)
+	}
+}
+
+func TestDeleteThreadById(t *testing.T) {
+	r, storage := newTestRepository(t)
+	ctx := context.Background()
+
+	if err := r.DeleteThreadById(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.DeleteThreadById(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := storage.Forums["f"].Threads; got != 2 {
+		t.Errorf("threads of f = %d, want 2", got)
+	}
+	if got := storage.Authors["f"]; !reflect.DeepEqual(got, map[string]bool{"alice": true}) {
+		t.Errorf("authors of f = %v, want alice", got)
+	}
+	if _, err := r.SelectThreadBySlug(ctx, "first"); !errors.Is(err, errors.ErrThreadNotFound) {
+		t.Errorf("SelectThreadBySlug() error = %v, want %v", err, errors.ErrThreadNotFound)
+	}
+	if err := r.DeleteThreadById(ctx, 1); !errors.Is(err, errors.ErrThreadNotFound) {
+		t.Errorf("DeleteThreadById() error = %v, want %v", err, errors.ErrThreadNotFound)
+	}
+}
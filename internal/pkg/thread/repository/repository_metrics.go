@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/thread"
+	"github.com/forum-api-back/pkg/logger"
+	"github.com/forum-api-back/pkg/metrics"
+)
+
+// MeasuredRepository observes how long every method takes and logs the
+// calls taking slowQuery or longer.
+type MeasuredRepository struct {
+	repo      thread.Repository
+	durations *metrics.HistogramVec
+	slowQuery time.Duration
+}
+
+// NewMeasuredRepository observes the durations of the methods of repo into
+// durations, labeled with the repository and the method. Calls taking
+// slowQuery or longer are logged as well, unless it is zero.
+func NewMeasuredRepository(repo thread.Repository, durations *metrics.HistogramVec,
+	slowQuery time.Duration) thread.Repository {
+	return &MeasuredRepository{
+		repo:      repo,
+		durations: durations,
+		slowQuery: slowQuery,
+	}
+}
+
+func (r *MeasuredRepository) InsertThread(ctx context.Context, forumSlug string,
+	threadInfo *models.ThreadCreate) (uint64, error) {
+	defer r.observe(ctx, "InsertThread", time.Now())
+	return r.repo.InsertThread(ctx, forumSlug, threadInfo)
+}
+
+func (r *MeasuredRepository) SelectThreadBySlug(ctx context.Context, threadSlug string) (*models.Thread, error) {
+	defer r.observe(ctx, "SelectThreadBySlug", time.Now())
+	return r.repo.SelectThreadBySlug(ctx, threadSlug)
+}
+
+func (r *MeasuredRepository) SelectThreadById(ctx context.Context, threadId uint64) (*models.Thread, error) {
+	defer r.observe(ctx, "SelectThreadById", time.Now())
+	return r.repo.SelectThreadById(ctx, threadId)
+}
+
+func (r *MeasuredRepository) SelectThreadsByForum(ctx context.Context, forumSlug string,
+	threadPaginator *models.ThreadPaginator) ([]*models.Thread, error) {
+	defer r.observe(ctx, "SelectThreadsByForum", time.Now())
+	return r.repo.SelectThreadsByForum(ctx, forumSlug, threadPaginator)
+}
+
+func (r *MeasuredRepository) UpdateThreadDetailsBySlug(ctx context.Context, threadSlug string,
+	threadInfo *models.ThreadUpdate, editor string) (*models.Thread, error) {
+	defer r.observe(ctx, "UpdateThreadDetailsBySlug", time.Now())
+	return r.repo.UpdateThreadDetailsBySlug(ctx, threadSlug, threadInfo, editor)
+}
+
+func (r *MeasuredRepository) UpdateThreadDetailsById(ctx context.Context, threadId uint64,
+	threadInfo *models.ThreadUpdate, editor string) (*models.Thread, error) {
+	defer r.observe(ctx, "UpdateThreadDetailsById", time.Now())
+	return r.repo.UpdateThreadDetailsById(ctx, threadId, threadInfo, editor)
+}
+
+func (r *MeasuredRepository) SelectRevisionsById(ctx context.Context,
+	threadId uint64) ([]*models.ThreadRevision, error) {
+	defer r.observe(ctx, "SelectRevisionsById", time.Now())
+	return r.repo.SelectRevisionsById(ctx, threadId)
+}
+
+func (r *MeasuredRepository) UpdateThreadVoteBySlug(ctx context.Context, threadSlug string,
+	threadVote *models.ThreadVote) error {
+	defer r.observe(ctx, "UpdateThreadVoteBySlug", time.Now())
+	return r.repo.UpdateThreadVoteBySlug(ctx, threadSlug, threadVote)
+}
+
+func (r *MeasuredRepository) UpdateThreadVoteById(ctx context.Context, threadId uint64,
+	threadVote *models.ThreadVote) error {
+	defer r.observe(ctx, "UpdateThreadVoteById", time.Now())
+	return r.repo.UpdateThreadVoteById(ctx, threadId, threadVote)
+}
+
+func (r *MeasuredRepository) UpdateThreadArchivedById(ctx context.Context, threadId uint64, isArchived bool) error {
+	defer r.observe(ctx, "UpdateThreadArchivedById", time.Now())
+	return r.repo.UpdateThreadArchivedById(ctx, threadId, isArchived)
+}
+
+func (r *MeasuredRepository) DeleteThreadById(ctx context.Context, threadId uint64) error {
+	defer r.observe(ctx, "DeleteThreadById", time.Now())
+	return r.repo.DeleteThreadById(ctx, threadId)
+}
+
+func (r *MeasuredRepository) observe(ctx context.Context, method string, start time.Time) {
+	elapsed := time.Since(start)
+	r.durations.With("thread", method).Observe(elapsed.Seconds())
+	if r.slowQuery > 0 && elapsed >= r.slowQuery {
+		logger.FromContext(ctx).Warn("slow query", "repository", "thread", "method", method, "duration", elapsed)
+	}
+}
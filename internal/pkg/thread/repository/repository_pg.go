@@ -1,13 +1,13 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
-	"strings"
 
 	"github.com/forum-api-back/internal/pkg/models"
 	"github.com/forum-api-back/internal/pkg/thread"
 	"github.com/forum-api-back/pkg/errors"
+	"github.com/forum-api-back/pkg/tools/sql_utils"
 )
 
 type PostgresqlRepository struct {
@@ -20,14 +20,14 @@ func NewSessionPostgresqlRepository(db *sql.DB) thread.Repository {
 	}
 }
 
-func (r *PostgresqlRepository) InsertThread(forumSlug string,
+func (r *PostgresqlRepository) InsertThread(ctx context.Context, forumSlug string,
 	threadInfo *models.ThreadCreate) (uint64, error) {
 	slug := sql.NullString{}
 	if threadInfo.Slug != "" {
 		slug.String = threadInfo.Slug
 		slug.Valid = true
 	}
-	row := r.db.QueryRow(
+	row := r.db.QueryRowContext(ctx,
 		"INSERT INTO threads(slug, title, author_nickname, "+
 			"	forum_slug, message, date_created) "+
 			"VALUES ($1, $2, $3, $4, $5, $6) "+
@@ -42,16 +42,16 @@ func (r *PostgresqlRepository) InsertThread(forumSlug string,
 
 	var threadId uint64
 	if err := row.Scan(&threadId); err != nil {
-		return 0, errors.ErrDataConflict
+		return 0, threadError(err)
 	}
 
 	return threadId, nil
 }
 
-func (r *PostgresqlRepository) SelectThreadBySlug(threadSlug string) (*models.Thread, error) {
-	row := r.db.QueryRow(
+func (r *PostgresqlRepository) SelectThreadBySlug(ctx context.Context, threadSlug string) (*models.Thread, error) {
+	row := r.db.QueryRowContext(ctx,
 		"SELECT id, slug, title, author_nickname, "+
-			"forum_slug, message, date_created, votes "+
+			"forum_slug, message, date_created, votes, is_archived, date_edited "+
 			"FROM threads "+
 			"WHERE slug = $1",
 		threadSlug,
@@ -59,6 +59,7 @@ func (r *PostgresqlRepository) SelectThreadBySlug(threadSlug string) (*models.Th
 
 	selectedThread := &models.Thread{}
 	slug := sql.NullString{}
+	dateEdited := sql.NullTime{}
 	err := row.Scan(
 		&selectedThread.Id,
 		&slug,
@@ -68,20 +69,23 @@ func (r *PostgresqlRepository) SelectThreadBySlug(threadSlug string) (*models.Th
 		&selectedThread.Message,
 		&selectedThread.DateCreated,
 		&selectedThread.Votes,
+		&selectedThread.IsArchived,
+		&dateEdited,
 	)
 	selectedThread.Slug = slug.String
+	setEdited(selectedThread, dateEdited)
 
 	if err != nil {
-		return nil, errors.ErrThreadNotFound
+		return nil, threadError(err)
 	}
 
 	return selectedThread, nil
 }
 
-func (r *PostgresqlRepository) SelectThreadById(threadId uint64) (*models.Thread, error) {
-	row := r.db.QueryRow(
+func (r *PostgresqlRepository) SelectThreadById(ctx context.Context, threadId uint64) (*models.Thread, error) {
+	row := r.db.QueryRowContext(ctx,
 		"SELECT id, slug, title, author_nickname, "+
-			"forum_slug, message, date_created, votes "+
+			"forum_slug, message, date_created, votes, is_archived, date_edited "+
 			"FROM threads "+
 			"WHERE id = $1",
 		threadId,
@@ -89,6 +93,7 @@ func (r *PostgresqlRepository) SelectThreadById(threadId uint64) (*models.Thread
 
 	selectedThread := &models.Thread{}
 	slug := sql.NullString{}
+	dateEdited := sql.NullTime{}
 	err := row.Scan(
 		&selectedThread.Id,
 		&slug,
@@ -98,17 +103,20 @@ func (r *PostgresqlRepository) SelectThreadById(threadId uint64) (*models.Thread
 		&selectedThread.Message,
 		&selectedThread.DateCreated,
 		&selectedThread.Votes,
+		&selectedThread.IsArchived,
+		&dateEdited,
 	)
 	selectedThread.Slug = slug.String
+	setEdited(selectedThread, dateEdited)
 
 	if err != nil {
-		return nil, errors.ErrThreadNotFound
+		return nil, threadError(err)
 	}
 
 	return selectedThread, nil
 }
 
-func (r *PostgresqlRepository) SelectThreadsByForum(forumSlug string,
+func (r *PostgresqlRepository) SelectThreadsByForum(ctx context.Context, forumSlug string,
 	threadPaginator *models.ThreadPaginator) ([]*models.Thread, error) {
 	var orderSort, orderCompare string
 	if threadPaginator.SortOrder {
@@ -122,9 +130,9 @@ func (r *PostgresqlRepository) SelectThreadsByForum(forumSlug string,
 	var rows *sql.Rows
 	var err error
 	if threadPaginator.Since.IsZero() {
-		rows, err = r.db.Query(
+		rows, err = r.db.QueryContext(ctx,
 			"SELECT id, slug, title, author_nickname, "+
-				"forum_slug, message, date_created, votes "+
+				"forum_slug, message, date_created, votes, is_archived, date_edited "+
 				"FROM threads "+
 				"WHERE forum_slug = $1 "+
 				"ORDER BY date_created "+orderSort+
@@ -133,9 +141,9 @@ func (r *PostgresqlRepository) SelectThreadsByForum(forumSlug string,
 			threadPaginator.Limit,
 		)
 	} else {
-		rows, err = r.db.Query(
+		rows, err = r.db.QueryContext(ctx,
 			"SELECT id, slug, title, author_nickname, "+
-				"forum_slug, message, date_created, votes "+
+				"forum_slug, message, date_created, votes, is_archived, date_edited "+
 				"FROM threads "+
 				"WHERE (forum_slug = $1 AND "+
 				"date_created"+orderCompare+"$2) "+
@@ -148,7 +156,7 @@ func (r *PostgresqlRepository) SelectThreadsByForum(forumSlug string,
 	}
 
 	if err != nil {
-		return nil, errors.ErrThreadNotFound
+		return nil, threadError(err)
 	}
 	defer rows.Close()
 
@@ -156,6 +164,7 @@ func (r *PostgresqlRepository) SelectThreadsByForum(forumSlug string,
 	for rows.Next() {
 		selectedThread := &models.Thread{}
 		slug := sql.NullString{}
+		dateEdited := sql.NullTime{}
 		err := rows.Scan(
 			&selectedThread.Id,
 			&slug,
@@ -165,48 +174,62 @@ func (r *PostgresqlRepository) SelectThreadsByForum(forumSlug string,
 			&selectedThread.Message,
 			&selectedThread.DateCreated,
 			&selectedThread.Votes,
+			&selectedThread.IsArchived,
+			&dateEdited,
 		)
 		selectedThread.Slug = slug.String
+		setEdited(selectedThread, dateEdited)
 		if err != nil {
-			return nil, errors.ErrInternalError
+			return nil, threadError(err)
 		}
 
 		threads = append(threads, selectedThread)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, threadError(err)
+	}
+
 	return threads, nil
 }
 
-func (r *PostgresqlRepository) UpdateThreadDetailsBySlug(threadSlug string,
-	threadInfo *models.ThreadUpdate) (*models.Thread, error) {
-	columns := make([]string, 0)
-	args := make([]interface{}, 1)
-	args[0] = threadSlug
-	if threadInfo.Title != "" {
-		args = append(args, threadInfo.Title)
-		columns = append(columns, fmt.Sprintf("title = $%d ", len(args)))
-	}
-	if threadInfo.Message != "" {
-		args = append(args, threadInfo.Message)
-		columns = append(columns, fmt.Sprintf("message = $%d ", len(args)))
-	}
+func (r *PostgresqlRepository) UpdateThreadDetailsBySlug(ctx context.Context, threadSlug string,
+	threadInfo *models.ThreadUpdate, editor string) (*models.Thread, error) {
+	return r.updateThreadDetails(ctx, "slug", threadSlug, threadInfo, editor)
+}
+
+func (r *PostgresqlRepository) UpdateThreadDetailsById(ctx context.Context, threadId uint64,
+	threadInfo *models.ThreadUpdate, editor string) (*models.Thread, error) {
+	return r.updateThreadDetails(ctx, "id", threadId, threadInfo, editor)
+}
 
-	if len(args) == 1 {
+// updateThreadDetails changes the title and the message of the thread
+// whose column equals key and records them as a new revision.
+func (r *PostgresqlRepository) updateThreadDetails(ctx context.Context, column string, key interface{},
+	threadInfo *models.ThreadUpdate, editor string) (*models.Thread, error) {
+	if threadInfo.Title == "" && threadInfo.Message == "" {
 		return nil, errors.ErrEmptyParameters
 	}
 
-	row := r.db.QueryRow(
-		"UPDATE threads SET "+
-			strings.Join(columns, ", ")+
-			" WHERE slug = $1 "+
-			"RETURNING id, slug, title, author_nickname, "+
-			"	forum_slug, message, date_created",
-		args...,
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, threadError(err)
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRowContext(ctx,
+		"SELECT id, slug, title, author_nickname, "+
+			"forum_slug, message, date_created, votes, is_archived, date_edited "+
+			"FROM threads "+
+			"WHERE "+column+" = $1 "+
+			"FOR UPDATE",
+		key,
 	)
 
 	updatedThread := &models.Thread{}
 	slug := sql.NullString{}
-	err := row.Scan(
+	dateEdited := sql.NullTime{}
+	err = row.Scan(
 		&updatedThread.Id,
 		&slug,
 		&updatedThread.Title,
@@ -214,67 +237,124 @@ func (r *PostgresqlRepository) UpdateThreadDetailsBySlug(threadSlug string,
 		&updatedThread.Forum,
 		&updatedThread.Message,
 		&updatedThread.DateCreated,
+		&updatedThread.Votes,
+		&updatedThread.IsArchived,
+		&dateEdited,
 	)
 	updatedThread.Slug = slug.String
+	setEdited(updatedThread, dateEdited)
 
 	if err != nil {
-		return nil, errors.ErrThreadNotFound
+		return nil, threadError(err)
 	}
 
-	return updatedThread, nil
-}
-
-func (r *PostgresqlRepository) UpdateThreadDetailsById(threadId uint64,
-	threadInfo *models.ThreadUpdate) (*models.Thread, error) {
-
-	columns := make([]string, 0)
-	args := make([]interface{}, 1)
-	args[0] = threadId
+	title, message := updatedThread.Title, updatedThread.Message
 	if threadInfo.Title != "" {
-		args = append(args, threadInfo.Title)
-		columns = append(columns, fmt.Sprintf("title = $%d ", len(args)))
+		title = threadInfo.Title
 	}
 	if threadInfo.Message != "" {
-		args = append(args, threadInfo.Message)
-		columns = append(columns, fmt.Sprintf("message = $%d ", len(args)))
+		message = threadInfo.Message
 	}
-
-	if len(args) == 1 {
-		return nil, errors.ErrEmptyParameters
+	if title == updatedThread.Title && message == updatedThread.Message {
+		return updatedThread, nil
 	}
 
-	row := r.db.QueryRow(
-		"UPDATE threads SET "+
-			strings.Join(columns, ", ")+
-			" WHERE id = $1 "+
-			"RETURNING id, slug, title, author_nickname, "+
-			"	forum_slug, message, date_created",
-		args...,
+	// The original title and message become the first revision on the
+	// first edit, so threads that are never edited don't cost a row.
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO thread_revisions (thread_id, revision, editor_nickname, title, message, date_edited) "+
+			"VALUES ($1, 1, $2, $3, $4, $5) "+
+			"ON CONFLICT DO NOTHING",
+		updatedThread.Id,
+		updatedThread.AuthorNickName,
+		updatedThread.Title,
+		updatedThread.Message,
+		updatedThread.DateCreated,
 	)
+	if err != nil {
+		return nil, threadError(err)
+	}
 
-	updatedThread := &models.Thread{}
-	slug := sql.NullString{}
-	err := row.Scan(
-		&updatedThread.Id,
-		&slug,
-		&updatedThread.Title,
-		&updatedThread.AuthorNickName,
-		&updatedThread.Forum,
-		&updatedThread.Message,
-		&updatedThread.DateCreated,
+	row = tx.QueryRowContext(ctx,
+		"INSERT INTO thread_revisions (thread_id, revision, editor_nickname, title, message) "+
+			"SELECT $1, MAX(revision) + 1, $2, $3, $4 "+
+			"FROM thread_revisions "+
+			"WHERE thread_id = $1 "+
+			"RETURNING date_edited",
+		updatedThread.Id,
+		editor,
+		title,
+		message,
 	)
-	updatedThread.Slug = slug.String
+	if err := row.Scan(&dateEdited); err != nil {
+		return nil, threadError(err)
+	}
 
+	_, err = tx.ExecContext(ctx,
+		"UPDATE threads SET "+
+			"title = $2, "+
+			"message = $3, "+
+			"date_edited = $4 "+
+			"WHERE id = $1",
+		updatedThread.Id,
+		title,
+		message,
+		dateEdited,
+	)
 	if err != nil {
-		return nil, errors.ErrThreadNotFound
+		return nil, threadError(err)
 	}
+	if err := tx.Commit(); err != nil {
+		return nil, threadError(err)
+	}
+
+	updatedThread.Title = title
+	updatedThread.Message = message
+	setEdited(updatedThread, dateEdited)
 
 	return updatedThread, nil
 }
 
-func (r *PostgresqlRepository) UpdateThreadVoteBySlug(threadSlug string,
+func (r *PostgresqlRepository) SelectRevisionsById(ctx context.Context, threadId uint64) ([]*models.ThreadRevision, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT revision, editor_nickname, title, message, date_edited "+
+			"FROM thread_revisions "+
+			"WHERE thread_id = $1 "+
+			"ORDER BY revision",
+		threadId,
+	)
+	if err != nil {
+		return nil, threadError(err)
+	}
+	defer rows.Close()
+
+	revisions := make([]*models.ThreadRevision, 0)
+	for rows.Next() {
+		revision := &models.ThreadRevision{}
+		err = rows.Scan(
+			&revision.Revision,
+			&revision.Editor,
+			&revision.Title,
+			&revision.Message,
+			&revision.DateEdited,
+		)
+		if err != nil {
+			return nil, threadError(err)
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, threadError(err)
+	}
+
+	return revisions, nil
+}
+
+func (r *PostgresqlRepository) UpdateThreadVoteBySlug(ctx context.Context, threadSlug string,
 	threadVote *models.ThreadVote) error {
-	_, err := r.db.Exec(
+	_, err := r.db.ExecContext(ctx,
 		"WITH thread_info AS ( "+
 			"	SELECT id "+
 			"	FROM threads "+
@@ -291,16 +371,12 @@ func (r *PostgresqlRepository) UpdateThreadVoteBySlug(threadSlug string,
 		threadSlug,
 	)
 
-	if err != nil {
-		return errors.ErrDataConflict
-	}
-
-	return nil
+	return threadError(err)
 }
 
-func (r *PostgresqlRepository) UpdateThreadVoteById(threadId uint64,
+func (r *PostgresqlRepository) UpdateThreadVoteById(ctx context.Context, threadId uint64,
 	threadVote *models.ThreadVote) error {
-	_, err := r.db.Exec(
+	_, err := r.db.ExecContext(ctx,
 		"INSERT INTO votes (vote, author_nickname, thread_id) "+
 			"VALUES ($1, $2, $3) "+
 			"ON CONFLICT (thread_id, author_nickname) "+
@@ -311,9 +387,67 @@ func (r *PostgresqlRepository) UpdateThreadVoteById(threadId uint64,
 		threadId,
 	)
 
+	return threadError(err)
+}
+
+func (r *PostgresqlRepository) UpdateThreadArchivedById(ctx context.Context, threadId uint64, isArchived bool) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE threads SET "+
+			"is_archived = $2 "+
+			"WHERE id = $1",
+		threadId,
+		isArchived,
+	)
+
+	return affectedThread(result, err)
+}
+
+func (r *PostgresqlRepository) DeleteThreadById(ctx context.Context, threadId uint64) error {
+	result, err := r.db.ExecContext(ctx,
+		"DELETE FROM threads "+
+			"WHERE id = $1",
+		threadId,
+	)
+
+	return affectedThread(result, err)
+}
+
+// affectedThread reports a thread missing when a statement changed no row.
+func affectedThread(result sql.Result, err error) error {
 	if err != nil {
-		return errors.ErrDataConflict
+		return threadError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return threadError(err)
+	}
+	if affected == 0 {
+		return errors.ErrThreadNotFound
 	}
 
 	return nil
 }
+
+// setEdited fills the edit indicator of a thread from its date_edited column.
+func setEdited(selectedThread *models.Thread, dateEdited sql.NullTime) {
+	if dateEdited.Valid {
+		selectedThread.IsEdited = true
+		selectedThread.EditedAt = &dateEdited.Time
+	}
+}
+
+// threadError tells missing authors, forums and threads referenced by
+// a statement from other failures.
+func threadError(err error) error {
+	switch sql_utils.Constraint(err) {
+	case "threads_author_nickname_fkey", "votes_author_nickname_fkey", "authors_user_nickname_fkey":
+		return errors.ErrUserNotFound.Wrap(err)
+	case "threads_forum_slug_fkey", "authors_forum_slug_fkey":
+		return errors.ErrForumNotFound.Wrap(err)
+	case "votes_thread_id_fkey":
+		return errors.ErrThreadNotFound.Wrap(err)
+	}
+
+	return sql_utils.Error(err, errors.ErrThreadNotFound)
+}
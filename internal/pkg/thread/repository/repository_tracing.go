@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/thread"
+	"github.com/forum-api-back/pkg/tracing"
+)
+
+// TracedRepository records a span for every method called within a traced request.
+type TracedRepository struct {
+	repo thread.Repository
+}
+
+func NewTracedRepository(repo thread.Repository) thread.Repository {
+	return &TracedRepository{
+		repo: repo,
+	}
+}
+
+func (r *TracedRepository) InsertThread(ctx context.Context, forumSlug string,
+	threadInfo *models.ThreadCreate) (_ uint64, err error) {
+	ctx, span := tracing.Start(ctx, "thread.repository.InsertThread")
+	defer func() { span.End(err) }()
+	return r.repo.InsertThread(ctx, forumSlug, threadInfo)
+}
+
+func (r *TracedRepository) SelectThreadBySlug(ctx context.Context,
+	threadSlug string) (_ *models.Thread, err error) {
+	ctx, span := tracing.Start(ctx, "thread.repository.SelectThreadBySlug")
+	defer func() { span.End(err) }()
+	return r.repo.SelectThreadBySlug(ctx, threadSlug)
+}
+
+func (r *TracedRepository) SelectThreadById(ctx context.Context,
+	threadId uint64) (_ *models.Thread, err error) {
+	ctx, span := tracing.Start(ctx, "thread.repository.SelectThreadById")
+	defer func() { span.End(err) }()
+	return r.repo.SelectThreadById(ctx, threadId)
+}
+
+func (r *TracedRepository) SelectThreadsByForum(ctx context.Context, forumSlug string,
+	threadPaginator *models.ThreadPaginator) (_ []*models.Thread, err error) {
+	ctx, span := tracing.Start(ctx, "thread.repository.SelectThreadsByForum")
+	defer func() { span.End(err) }()
+	return r.repo.SelectThreadsByForum(ctx, forumSlug, threadPaginator)
+}
+
+func (r *TracedRepository) UpdateThreadDetailsBySlug(ctx context.Context, threadSlug string,
+	threadInfo *models.ThreadUpdate, editor string) (_ *models.Thread, err error) {
+	ctx, span := tracing.Start(ctx, "thread.repository.UpdateThreadDetailsBySlug")
+	defer func() { span.End(err) }()
+	return r.repo.UpdateThreadDetailsBySlug(ctx, threadSlug, threadInfo, editor)
+}
+
+func (r *TracedRepository) UpdateThreadDetailsById(ctx context.Context, threadId uint64,
+	threadInfo *models.ThreadUpdate, editor string) (_ *models.Thread, err error) {
+	ctx, span := tracing.Start(ctx, "thread.repository.UpdateThreadDetailsById")
+	defer func() { span.End(err) }()
+	return r.repo.UpdateThreadDetailsById(ctx, threadId, threadInfo, editor)
+}
+
+func (r *TracedRepository) SelectRevisionsById(ctx context.Context,
+	threadId uint64) (_ []*models.ThreadRevision, err error) {
+	ctx, span := tracing.Start(ctx, "thread.repository.SelectRevisionsById")
+	defer func() { span.End(err) }()
+	return r.repo.SelectRevisionsById(ctx, threadId)
+}
+
+func (r *TracedRepository) UpdateThreadVoteBySlug(ctx context.Context, threadSlug string,
+	threadVote *models.ThreadVote) (err error) {
+	ctx, span := tracing.Start(ctx, "thread.repository.UpdateThreadVoteBySlug")
+	defer func() { span.End(err) }()
+	return r.repo.UpdateThreadVoteBySlug(ctx, threadSlug, threadVote)
+}
+
+func (r *TracedRepository) UpdateThreadVoteById(ctx context.Context, threadId uint64,
+	threadVote *models.ThreadVote) (err error) {
+	ctx, span := tracing.Start(ctx, "thread.repository.UpdateThreadVoteById")
+	defer func() { span.End(err) }()
+	return r.repo.UpdateThreadVoteById(ctx, threadId, threadVote)
+}
+
+func (r *TracedRepository) UpdateThreadArchivedById(ctx context.Context, threadId uint64,
+	isArchived bool) (err error) {
+	ctx, span := tracing.Start(ctx, "thread.repository.UpdateThreadArchivedById")
+	defer func() { span.End(err) }()
+	return r.repo.UpdateThreadArchivedById(ctx, threadId, isArchived)
+}
+
+func (r *TracedRepository) DeleteThreadById(ctx context.Context, threadId uint64) (err error) {
+	ctx, span := tracing.Start(ctx, "thread.repository.DeleteThreadById")
+	defer func() { span.End(err) }()
+	return r.repo.DeleteThreadById(ctx, threadId)
+}
@@ -1,15 +1,22 @@
 package thread
 
-import "github.com/forum-api-back/internal/pkg/models"
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
 
 type UseCase interface {
-	CreateNewThread(forumSlug string,
+	CreateNewThread(ctx context.Context, forumSlug string,
 		threadInfo *models.ThreadCreate) (*models.Thread, error)
-	GetThreadsByForum(forumSlug string,
+	GetThreadsByForum(ctx context.Context, forumSlug string,
 		threadPaginator *models.ThreadPaginator) ([]*models.Thread, error)
-	GetThreadDetails(threadSlugOrId string) (*models.Thread, error)
-	UpdateThreadDetails(threadSlugOrId string,
+	GetThreadDetails(ctx context.Context, threadSlugOrId string) (*models.Thread, error)
+	UpdateThreadDetails(ctx context.Context, threadSlugOrId string,
 		threadInfo *models.ThreadUpdate) (*models.Thread, error)
-	UpdateThreadVote(threadSlugOrId string,
+	UpdateThreadVote(ctx context.Context, threadSlugOrId string,
 		threadVote *models.ThreadVote) (*models.Thread, error)
+	SetThreadArchived(ctx context.Context, threadSlugOrId string, isArchived bool) (*models.Thread, error)
+	DeleteThread(ctx context.Context, threadSlugOrId string) error
+	GetThreadHistory(ctx context.Context, threadSlugOrId string) ([]*models.ThreadRevision, error)
 }
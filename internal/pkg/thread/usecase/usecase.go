@@ -1,10 +1,14 @@
 package usecase
 
 import (
+	"context"
 	"strconv"
 
+	"github.com/forum-api-back/internal/pkg/auth"
+	"github.com/forum-api-back/internal/pkg/events"
 	"github.com/forum-api-back/internal/pkg/forum"
 	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/roles"
 	"github.com/forum-api-back/internal/pkg/thread"
 	"github.com/forum-api-back/pkg/errors"
 )
@@ -12,26 +16,40 @@ import (
 type ThreadUseCase struct {
 	ThreadRepo thread.Repository
 	ForumRepo  forum.Repository
+	RolesUCase roles.UseCase
+	Events     events.Publisher
 }
 
-func NewUseCase(threadRepo thread.Repository, forumRepo forum.Repository) thread.UseCase {
+func NewUseCase(threadRepo thread.Repository, forumRepo forum.Repository, rolesUCase roles.UseCase,
+	publisher events.Publisher) thread.UseCase {
 	return &ThreadUseCase{
 		ThreadRepo: threadRepo,
 		ForumRepo:  forumRepo,
+		RolesUCase: rolesUCase,
+		Events:     publisher,
 	}
 }
 
-func (u *ThreadUseCase) CreateNewThread(forumSlug string,
+func (u *ThreadUseCase) CreateNewThread(ctx context.Context, forumSlug string,
 	threadInfo *models.ThreadCreate) (*models.Thread, error) {
-	selectedForum, err := u.ForumRepo.SelectForumBySlug(forumSlug)
+	actor, err := u.RolesUCase.Actor(ctx, threadInfo.AuthorNickName)
 	if err != nil {
-		return nil, errors.ErrDataConflict
+		return nil, err
 	}
+	threadInfo.AuthorNickName = actor
 
-	threadId, err := u.ThreadRepo.InsertThread(selectedForum.Slug, threadInfo)
-	switch err {
-	case nil:
-		return &models.Thread{
+	selectedForum, err := u.ForumRepo.SelectForumBySlug(ctx, forumSlug)
+	if err != nil {
+		return nil, errors.Translate(err, errors.ErrForumNotFound.WithMessage("Can't find forum by slug: %s", forumSlug))
+	}
+	if selectedForum.IsArchived {
+		return nil, errors.ErrForumArchived.WithMessage("Forum %s is archived", selectedForum.Slug)
+	}
+
+	threadId, err := u.ThreadRepo.InsertThread(ctx, selectedForum.Slug, threadInfo)
+	switch {
+	case err == nil:
+		newThread := &models.Thread{
 			Id:             threadId,
 			Title:          threadInfo.Title,
 			AuthorNickName: threadInfo.AuthorNickName,
@@ -39,100 +57,209 @@ func (u *ThreadUseCase) CreateNewThread(forumSlug string,
 			Message:        threadInfo.Message,
 			Slug:           threadInfo.Slug,
 			DateCreated:    threadInfo.DateCreated,
-		}, nil
-	case errors.ErrDataConflict:
-		if threadInfo.Slug != "" {
-			selectedThread, err := u.ThreadRepo.SelectThreadBySlug(threadInfo.Slug)
-			if err == nil {
-				return selectedThread, errors.ErrAlreadyExists
-			}
 		}
-		return nil, errors.ErrDataConflict
+		copiedThread := *newThread
+		u.Events.Publish(ctx, &events.ThreadCreated{Thread: &copiedThread})
+		return newThread, nil
+	case errors.Is(err, errors.ErrDataConflict) && threadInfo.Slug != "":
+		selectedThread, selectErr := u.ThreadRepo.SelectThreadBySlug(ctx, threadInfo.Slug)
+		if selectErr != nil {
+			return nil, err
+		}
+		return selectedThread, errors.ErrAlreadyExists.Wrap(err)
 	default:
-		return nil, errors.ErrInternalError
+		return nil, err
 	}
 }
 
-func (u *ThreadUseCase) GetThreadsByForum(forumSlug string,
+func (u *ThreadUseCase) GetThreadsByForum(ctx context.Context, forumSlug string,
 	threadPaginator *models.ThreadPaginator) ([]*models.Thread, error) {
-	if _, err := u.ForumRepo.SelectForumBySlug(forumSlug); err != nil {
-		return nil, errors.ErrForumNotFound
+	if _, err := u.ForumRepo.SelectForumBySlug(ctx, forumSlug); err != nil {
+		return nil, errors.Translate(err, errors.ErrForumNotFound.WithMessage("Can't find forum by slug: %s", forumSlug))
 	}
 
-	threads, err := u.ThreadRepo.SelectThreadsByForum(forumSlug, threadPaginator)
+	threads, err := u.ThreadRepo.SelectThreadsByForum(ctx, forumSlug, threadPaginator)
 	if err != nil {
-		return nil, errors.ErrInternalError
+		return nil, err
 	}
 
 	return threads, nil
 }
 
-func (u *ThreadUseCase) GetThreadDetails(threadSlugOrId string) (*models.Thread, error) {
+func (u *ThreadUseCase) GetThreadDetails(ctx context.Context, threadSlugOrId string) (*models.Thread, error) {
 	threadId, err := strconv.Atoi(threadSlugOrId)
 
 	var selectedThread *models.Thread
 	if err != nil {
-		selectedThread, err = u.ThreadRepo.SelectThreadBySlug(threadSlugOrId)
+		selectedThread, err = u.ThreadRepo.SelectThreadBySlug(ctx, threadSlugOrId)
 	} else if threadId >= 1 {
-		selectedThread, err = u.ThreadRepo.SelectThreadById(uint64(threadId))
+		selectedThread, err = u.ThreadRepo.SelectThreadById(ctx, uint64(threadId))
 	} else {
-		return nil, errors.ErrThreadNotFound
+		return nil, threadNotFound(threadSlugOrId)
 	}
 
 	if err != nil {
-		return nil, errors.ErrThreadNotFound
+		return nil, errors.Translate(err, threadNotFound(threadSlugOrId))
 	}
 
 	return selectedThread, nil
 }
 
-func (u *ThreadUseCase) UpdateThreadDetails(threadSlugOrId string,
+func (u *ThreadUseCase) UpdateThreadDetails(ctx context.Context, threadSlugOrId string,
 	threadInfo *models.ThreadUpdate) (*models.Thread, error) {
-	threadId, err := strconv.Atoi(threadSlugOrId)
-
-	var updatedThread *models.Thread
+	selectedThread, err := u.GetThreadDetails(ctx, threadSlugOrId)
 	if err != nil {
-		updatedThread, err = u.ThreadRepo.UpdateThreadDetailsBySlug(threadSlugOrId, threadInfo)
-		if err == errors.ErrEmptyParameters {
-			updatedThread, err = u.ThreadRepo.SelectThreadBySlug(threadSlugOrId)
-		}
-	} else if threadId >= 1 {
-		updatedThread, err = u.ThreadRepo.UpdateThreadDetailsById(uint64(threadId), threadInfo)
-		if err == errors.ErrEmptyParameters {
-			updatedThread, err = u.ThreadRepo.SelectThreadById(uint64(threadId))
-		}
-	} else {
-		return nil, errors.ErrThreadNotFound
+		return nil, err
 	}
 
+	err = u.RolesUCase.AuthorizeAuthor(ctx, selectedThread.Forum, selectedThread.AuthorNickName, models.RoleModerator)
 	if err != nil {
-		return nil, errors.ErrThreadNotFound
+		return nil, err
 	}
+
+	editor := selectedThread.AuthorNickName
+	if caller, ok := auth.Caller(ctx); ok {
+		editor = caller
+	}
+
+	updatedThread, err := u.ThreadRepo.UpdateThreadDetailsById(ctx, selectedThread.Id, threadInfo, editor)
+	switch {
+	case errors.Is(err, errors.ErrEmptyParameters):
+		return selectedThread, nil
+	case err != nil:
+		return nil, errors.Translate(err, threadNotFound(threadSlugOrId))
+	}
+
+	if updatedThread.Title != selectedThread.Title || updatedThread.Message != selectedThread.Message {
+		copiedThread := *updatedThread
+		u.Events.Publish(ctx, &events.ThreadEdited{Thread: &copiedThread, Editor: editor})
+	}
+
 	return updatedThread, nil
 }
 
-func (u *ThreadUseCase) UpdateThreadVote(threadSlugOrId string,
+func (u *ThreadUseCase) UpdateThreadVote(ctx context.Context, threadSlugOrId string,
 	threadVote *models.ThreadVote) (*models.Thread, error) {
-	threadId, err := strconv.Atoi(threadSlugOrId)
+	actor, err := u.RolesUCase.Actor(ctx, threadVote.NickName)
+	if err != nil {
+		return nil, err
+	}
+	threadVote.NickName = actor
+
+	selectedThread, err := u.GetThreadDetails(ctx, threadSlugOrId)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkWritable(ctx, selectedThread, threadSlugOrId); err != nil {
+		return nil, err
+	}
+
+	if err = u.ThreadRepo.UpdateThreadVoteById(ctx, selectedThread.Id, threadVote); err != nil {
+		return nil, errors.Translate(err, threadNotFound(threadSlugOrId))
+	}
 
-	var updatedThread *models.Thread
+	updatedThread, err := u.ThreadRepo.SelectThreadById(ctx, selectedThread.Id)
 	if err != nil {
-		if err = u.ThreadRepo.UpdateThreadVoteBySlug(threadSlugOrId, threadVote); err != nil {
-			return nil, errors.ErrThreadNotFound
+		return nil, errors.Translate(err, threadNotFound(threadSlugOrId))
+	}
+	copiedThread, copiedVote := *updatedThread, *threadVote
+	u.Events.Publish(ctx, &events.VoteCast{Thread: &copiedThread, Vote: &copiedVote})
+
+	return updatedThread, nil
+}
+
+func (u *ThreadUseCase) SetThreadArchived(ctx context.Context, threadSlugOrId string,
+	isArchived bool) (*models.Thread, error) {
+	selectedThread, err := u.GetThreadDetails(ctx, threadSlugOrId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.RolesUCase.Authorize(ctx, selectedThread.Forum, models.RoleModerator); err != nil {
+		return nil, err
+	}
+
+	// Threads of an archived forum are only reopened with the forum.
+	if !isArchived {
+		selectedForum, err := u.ForumRepo.SelectForumBySlug(ctx, selectedThread.Forum)
+		if err != nil {
+			return nil, errors.Translate(err, errors.ErrForumNotFound)
 		}
-		updatedThread, err = u.ThreadRepo.SelectThreadBySlug(threadSlugOrId)
-	} else if threadId >= 1 {
-		if err = u.ThreadRepo.UpdateThreadVoteById(uint64(threadId), threadVote); err != nil {
-			return nil, errors.ErrThreadNotFound
+		if selectedForum.IsArchived {
+			return nil, errors.ErrForumArchived.WithMessage("Forum %s is archived", selectedForum.Slug)
 		}
-		updatedThread, err = u.ThreadRepo.SelectThreadById(uint64(threadId))
-	} else {
-		return nil, errors.ErrThreadNotFound
 	}
 
+	err = u.ThreadRepo.UpdateThreadArchivedById(ctx, selectedThread.Id, isArchived)
 	if err != nil {
-		return nil, errors.ErrThreadNotFound
+		return nil, errors.Translate(err, threadNotFound(threadSlugOrId))
 	}
+	selectedThread.IsArchived = isArchived
 
-	return updatedThread, nil
+	return selectedThread, nil
+}
+
+func (u *ThreadUseCase) DeleteThread(ctx context.Context, threadSlugOrId string) error {
+	selectedThread, err := u.GetThreadDetails(ctx, threadSlugOrId)
+	if err != nil {
+		return err
+	}
+
+	if err := u.RolesUCase.Authorize(ctx, selectedThread.Forum, models.RoleModerator); err != nil {
+		return err
+	}
+
+	err = u.ThreadRepo.DeleteThreadById(ctx, selectedThread.Id)
+	return errors.Translate(err, threadNotFound(threadSlugOrId))
+}
+
+// GetThreadHistory returns the revisions of a thread. A thread that was
+// never edited has a single revision: the one it was created with.
+func (u *ThreadUseCase) GetThreadHistory(ctx context.Context, threadSlugOrId string) ([]*models.ThreadRevision, error) {
+	selectedThread, err := u.GetThreadDetails(ctx, threadSlugOrId)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := u.ThreadRepo.SelectRevisionsById(ctx, selectedThread.Id)
+	if err != nil {
+		return nil, errors.Translate(err, threadNotFound(threadSlugOrId))
+	}
+	if len(revisions) == 0 {
+		revisions = []*models.ThreadRevision{{
+			Revision:   1,
+			Editor:     selectedThread.AuthorNickName,
+			Title:      selectedThread.Title,
+			Message:    selectedThread.Message,
+			DateEdited: selectedThread.DateCreated,
+		}}
+	}
+
+	return revisions, nil
+}
+
+// checkWritable rejects changes to threads archived on their own or with
+// their forum.
+func (u *ThreadUseCase) checkWritable(ctx context.Context, selectedThread *models.Thread, threadSlugOrId string) error {
+	if selectedThread.IsArchived {
+		return threadArchived(threadSlugOrId)
+	}
+
+	selectedForum, err := u.ForumRepo.SelectForumBySlug(ctx, selectedThread.Forum)
+	if err != nil {
+		return errors.Translate(err, errors.ErrForumNotFound)
+	}
+	if selectedForum.IsArchived {
+		return errors.ErrForumArchived.WithMessage("Forum %s is archived", selectedForum.Slug)
+	}
+
+	return nil
+}
+
+func threadNotFound(threadSlugOrId string) *errors.Error {
+	return errors.ErrThreadNotFound.WithMessage("Can't find thread by slug or id: %s", threadSlugOrId)
+}
+
+func threadArchived(threadSlugOrId string) *errors.Error {
+	return errors.ErrThreadArchived.WithMessage("Thread %s is archived", threadSlugOrId)
 }
@@ -0,0 +1,332 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/forum-api-back/internal/pkg/auth"
+	events_dispatcher "github.com/forum-api-back/internal/pkg/events/dispatcher"
+	forum_repo "github.com/forum-api-back/internal/pkg/forum/repository"
+	"github.com/forum-api-back/internal/pkg/models"
+	roles_repo "github.com/forum-api-back/internal/pkg/roles/repository"
+	roles_usecase "github.com/forum-api-back/internal/pkg/roles/usecase"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+	"github.com/forum-api-back/internal/pkg/thread"
+	thread_repo "github.com/forum-api-back/internal/pkg/thread/repository"
+	user_repo "github.com/forum-api-back/internal/pkg/user/repository"
+	"github.com/forum-api-back/pkg/errors"
+)
+
+// newTestUseCase returns a usecase over forum "f" of "owner" and the
+// storage behind it. "guest" has no password, "member" and "owner" do.
+func newTestUseCase(t *testing.T) (thread.UseCase, *memory.Storage) {
+	t.Helper()
+	ctx := context.Background()
+
+	storage := memory.NewStorage()
+	userRepo := user_repo.NewSessionMemoryRepository(storage)
+	forumRepo := forum_repo.NewSessionMemoryRepository(storage)
+
+	for _, nickname := range []string{"owner", "member", "guest"} {
+		newUser := &models.User{NickName: nickname, Email: nickname + "@example.com"}
+		if nickname != "guest" {
+			newUser.PasswordHash = "hash"
+		}
+		if err := userRepo.InsertUser(ctx, newUser); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := forumRepo.InsertForum(ctx, &models.ForumCreate{Title: "Forum", AuthorNickName: "owner", Slug: "f"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rolesUCase := roles_usecase.NewUseCase(roles_repo.NewSessionMemoryRepository(storage), forumRepo, userRepo, nil)
+	return NewUseCase(thread_repo.NewSessionMemoryRepository(storage), forumRepo, rolesUCase,
+		events_dispatcher.NewDispatcher(0)), storage
+}
+
+func callerContext(caller string) context.Context {
+	if caller == "" {
+		return context.Background()
+	}
+	return auth.WithCaller(context.Background(), caller)
+}
+
+func checkCode(t *testing.T, err error, code errors.Code) {
+	t.Helper()
+	switch {
+	case code == "" && err != nil:
+		t.Fatalf("error = %v", err)
+	case code != "" && errors.From(err).Code != code:
+		t.Fatalf("error = %v, want code %v", err, code)
+	}
+}
+
+var authorTests = []struct {
+	name   string
+	caller string
+	author string
+	want   string
+	code   errors.Code
+}{
+	{"session replaces the body", "member", "guest", "member", ""},
+	{"no session, author without password", "", "guest", "guest", ""},
+	{"no session, author with password", "", "member", "", errors.CodeUnauthorized},
+}
+
+func TestCreateNewThreadAuthor(t *testing.T) {
+	for _, tt := range authorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := newTestUseCase(t)
+
+			newThread, err := u.CreateNewThread(callerContext(tt.caller), "f",
+				&models.ThreadCreate{Title: "Title", AuthorNickName: tt.author, Message: "message"})
+			checkCode(t, err, tt.code)
+			if err == nil && newThread.AuthorNickName != tt.want {
+				t.Errorf("author = %q, want %q", newThread.AuthorNickName, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateThreadVoteVoter(t *testing.T) {
+	for _, tt := range authorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, storage := newTestUseCase(t)
+			newThread, err := u.CreateNewThread(context.Background(), "f",
+				&models.ThreadCreate{Title: "Title", AuthorNickName: "guest", Message: "message"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = u.UpdateThreadVote(callerContext(tt.caller), "1",
+				&models.ThreadVote{NickName: tt.author, Voice: 1})
+			checkCode(t, err, tt.code)
+			if err == nil && storage.Votes[newThread.Id][tt.want] != 1 {
+				t.Errorf("votes = %v, want a vote of %q", storage.Votes[newThread.Id], tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateThreadDetailsEditor(t *testing.T) {
+	tests := []struct {
+		name   string
+		caller string
+		author string
+		code   errors.Code
+	}{
+		{"no session, author without password", "", "guest", ""},
+		{"no session, author with password", "", "member", errors.CodeUnauthorized},
+		{"author", "member", "member", ""},
+		{"other member", "guest", "member", errors.CodeForbidden},
+		{"forum owner", "owner", "member", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := newTestUseCase(t)
+			_, err := u.CreateNewThread(callerContext(tt.author), "f",
+				&models.ThreadCreate{Title: "Title", AuthorNickName: tt.author, Message: "message"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = u.UpdateThreadDetails(callerContext(tt.caller), "1", &models.ThreadUpdate{Message: "edited"})
+			checkCode(t, err, tt.code)
+		})
+	}
+}
+
+func TestCreateNewThread(t *testing.T) {
+	tests := []struct {
+		name   string
+		forum  string
+		thread models.ThreadCreate
+		want   models.Thread
+		code   errors.Code
+	}{
+		{
+			name:   "canonical forum slug",
+			forum:  "F",
+			thread: models.ThreadCreate{Title: "Title", AuthorNickName: "guest", Slug: "t"},
+			want:   models.Thread{Id: 2, Title: "Title", AuthorNickName: "guest", Forum: "f", Slug: "t"},
+		},
+		{
+			name:   "taken slug returns the existing thread",
+			forum:  "f",
+			thread: models.ThreadCreate{Title: "Other", AuthorNickName: "guest", Slug: "FIRST"},
+			want:   models.Thread{Id: 1, Title: "First", AuthorNickName: "guest", Forum: "f", Slug: "first"},
+			code:   errors.CodeConflict,
+		},
+		{
+			name:   "unknown forum",
+			forum:  "g",
+			thread: models.ThreadCreate{Title: "Title", AuthorNickName: "guest"},
+			code:   errors.CodeNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := newTestUseCase(t)
+			ctx := context.Background()
+			_, err := u.CreateNewThread(ctx, "f", &models.ThreadCreate{Title: "First", AuthorNickName: "guest", Slug: "first"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			newThread, err := u.CreateNewThread(ctx, tt.forum, &tt.thread)
+			checkCode(t, err, tt.code)
+			if tt.want.Id == 0 {
+				return
+			}
+			if newThread == nil || newThread.Id != tt.want.Id || newThread.Title != tt.want.Title ||
+				newThread.Forum != tt.want.Forum || newThread.Slug != tt.want.Slug {
+				t.Errorf("thread = %+v, want %+v", newThread, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateNewThreadCountsThreads(t *testing.T) {
+	u, storage := newTestUseCase(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := u.CreateNewThread(ctx, "f", &models.ThreadCreate{Title: "Title", AuthorNickName: "guest"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := u.DeleteThread(auth.WithCaller(ctx, "owner"), "2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := storage.Forums["f"].Threads; got != 2 {
+		t.Errorf("forum threads = %d, want 2", got)
+	}
+}
+
+func TestGetThreadDetails(t *testing.T) {
+	tests := []struct {
+		slugOrId string
+		want     uint64
+		code     errors.Code
+	}{
+		{"1", 1, ""},
+		{"first", 1, ""},
+		{"FIRST", 1, ""},
+		{"2", 0, errors.CodeNotFound},
+		{"0", 0, errors.CodeNotFound},
+		{"second", 0, errors.CodeNotFound},
+	}
+
+	u, _ := newTestUseCase(t)
+	_, err := u.CreateNewThread(context.Background(), "f",
+		&models.ThreadCreate{Title: "First", AuthorNickName: "guest", Slug: "first"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.slugOrId, func(t *testing.T) {
+			selectedThread, err := u.GetThreadDetails(context.Background(), tt.slugOrId)
+			checkCode(t, err, tt.code)
+			if err == nil && selectedThread.Id != tt.want {
+				t.Errorf("thread id = %d, want %d", selectedThread.Id, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateThreadVote(t *testing.T) {
+	u, _ := newTestUseCase(t)
+	ctx := context.Background()
+	_, err := u.CreateNewThread(ctx, "f", &models.ThreadCreate{Title: "Title", AuthorNickName: "guest", Slug: "t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	votes := []struct {
+		caller string
+		voice  int
+		want   int
+	}{
+		{"guest", 1, 1},
+		{"member", 1, 2},
+		{"guest", -1, 0},
+		{"member", -1, -2},
+	}
+	for _, vote := range votes {
+		updatedThread, err := u.UpdateThreadVote(callerContext(vote.caller), "t",
+			&models.ThreadVote{NickName: vote.caller, Voice: vote.voice})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if updatedThread.Votes != vote.want {
+			t.Errorf("votes after %s voted %d = %d, want %d", vote.caller, vote.voice, updatedThread.Votes, vote.want)
+		}
+	}
+}
+
+func TestArchivedThreadsAreReadOnly(t *testing.T) {
+	u, _ := newTestUseCase(t)
+	ctx := context.Background()
+	_, err := u.CreateNewThread(ctx, "f", &models.ThreadCreate{Title: "Title", AuthorNickName: "guest"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = u.SetThreadArchived(callerContext("guest"), "1", true)
+	checkCode(t, err, errors.CodeForbidden)
+	if _, err := u.SetThreadArchived(callerContext("owner"), "1", true); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = u.UpdateThreadVote(ctx, "1", &models.ThreadVote{NickName: "guest", Voice: 1})
+	checkCode(t, err, errors.CodeConflict)
+}
+
+func TestForumArchivalKeepsThreadArchival(t *testing.T) {
+	u, storage := newTestUseCase(t)
+	ctx := context.Background()
+	owner := callerContext("owner")
+	for i := 0; i < 2; i++ {
+		_, err := u.CreateNewThread(ctx, "f", &models.ThreadCreate{Title: "Title", AuthorNickName: "guest"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := u.SetThreadArchived(owner, "1", true); err != nil {
+		t.Fatal(err)
+	}
+
+	forumRepo := forum_repo.NewSessionMemoryRepository(storage)
+	if err := forumRepo.UpdateForumArchived(ctx, "f", true); err != nil {
+		t.Fatal(err)
+	}
+	_, err := u.UpdateThreadVote(ctx, "2", &models.ThreadVote{NickName: "guest", Voice: 1})
+	checkCode(t, err, errors.CodeConflict)
+	_, err = u.SetThreadArchived(owner, "1", false)
+	checkCode(t, err, errors.CodeConflict)
+
+	if err := forumRepo.UpdateForumArchived(ctx, "f", false); err != nil {
+		t.Fatal(err)
+	}
+	_, err = u.UpdateThreadVote(ctx, "1", &models.ThreadVote{NickName: "guest", Voice: 1})
+	checkCode(t, err, errors.CodeConflict)
+	if _, err := u.UpdateThreadVote(ctx, "2", &models.ThreadVote{NickName: "guest", Voice: 1}); err != nil {
+		t.Errorf("vote for a thread of an unarchived forum error = %v", err)
+	}
+
+	for slugOrId, want := range map[string]bool{"1": true, "2": false} {
+		selectedThread, err := u.GetThreadDetails(ctx, slugOrId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if selectedThread.IsArchived != want {
+			t.Errorf("thread %s archived = %v, want %v", slugOrId, selectedThread.IsArchived, want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/thread"
+	"github.com/forum-api-back/pkg/tracing"
+)
+
+// TracedUseCase records a span for every method called within a traced request.
+type TracedUseCase struct {
+	ucase thread.UseCase
+}
+
+func NewTracedUseCase(ucase thread.UseCase) thread.UseCase {
+	return &TracedUseCase{
+		ucase: ucase,
+	}
+}
+
+func (u *TracedUseCase) CreateNewThread(ctx context.Context, forumSlug string,
+	threadInfo *models.ThreadCreate) (_ *models.Thread, err error) {
+	ctx, span := tracing.Start(ctx, "thread.usecase.CreateNewThread")
+	defer func() { span.End(err) }()
+	return u.ucase.CreateNewThread(ctx, forumSlug, threadInfo)
+}
+
+func (u *TracedUseCase) GetThreadsByForum(ctx context.Context, forumSlug string,
+	threadPaginator *models.ThreadPaginator) (_ []*models.Thread, err error) {
+	ctx, span := tracing.Start(ctx, "thread.usecase.GetThreadsByForum")
+	defer func() { span.End(err) }()
+	return u.ucase.GetThreadsByForum(ctx, forumSlug, threadPaginator)
+}
+
+func (u *TracedUseCase) GetThreadDetails(ctx context.Context,
+	threadSlugOrId string) (_ *models.Thread, err error) {
+	ctx, span := tracing.Start(ctx, "thread.usecase.GetThreadDetails")
+	defer func() { span.End(err) }()
+	return u.ucase.GetThreadDetails(ctx, threadSlugOrId)
+}
+
+func (u *TracedUseCase) UpdateThreadDetails(ctx context.Context, threadSlugOrId string,
+	threadInfo *models.ThreadUpdate) (_ *models.Thread, err error) {
+	ctx, span := tracing.Start(ctx, "thread.usecase.UpdateThreadDetails")
+	defer func() { span.End(err) }()
+	return u.ucase.UpdateThreadDetails(ctx, threadSlugOrId, threadInfo)
+}
+
+func (u *TracedUseCase) UpdateThreadVote(ctx context.Context, threadSlugOrId string,
+	threadVote *models.ThreadVote) (_ *models.Thread, err error) {
+	ctx, span := tracing.Start(ctx, "thread.usecase.UpdateThreadVote")
+	defer func() { span.End(err) }()
+	return u.ucase.UpdateThreadVote(ctx, threadSlugOrId, threadVote)
+}
+
+func (u *TracedUseCase) SetThreadArchived(ctx context.Context, threadSlugOrId string,
+	isArchived bool) (_ *models.Thread, err error) {
+	ctx, span := tracing.Start(ctx, "thread.usecase.SetThreadArchived")
+	defer func() { span.End(err) }()
+	return u.ucase.SetThreadArchived(ctx, threadSlugOrId, isArchived)
+}
+
+func (u *TracedUseCase) DeleteThread(ctx context.Context, threadSlugOrId string) (err error) {
+	ctx, span := tracing.Start(ctx, "thread.usecase.DeleteThread")
+	defer func() { span.End(err) }()
+	return u.ucase.DeleteThread(ctx, threadSlugOrId)
+}
+
+func (u *TracedUseCase) GetThreadHistory(ctx context.Context,
+	threadSlugOrId string) (_ []*models.ThreadRevision, err error) {
+	ctx, span := tracing.Start(ctx, "thread.usecase.GetThreadHistory")
+	defer func() { span.End(err) }()
+	return u.ucase.GetThreadHistory(ctx, threadSlugOrId)
+}
@@ -2,7 +2,6 @@ package handler
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"strconv"
 
@@ -27,48 +26,49 @@ func NewHandler(userUCase user.UseCase) user.Handler {
 func (h *UserHandler) CreateNewUser(ctx *fasthttp.RequestCtx) {
 	userInfo := &models.User{}
 	if err := json.Unmarshal(ctx.PostBody(), userInfo); err != nil {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadRequest, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadRequest.Wrap(err))
 		return
 	}
 
 	if userInfo.NickName = ctx.UserValue("nickname").(string); userInfo.NickName == "" {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadArguments, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "nickname", Message: "must not be empty"}))
 		return
 	}
 
-	newUser, err := h.UserUCase.CreateNewUser(userInfo)
-	switch err {
-	case nil:
+	newUser, err := h.UserUCase.CreateNewUser(http_utils.Context(ctx), userInfo)
+	switch {
+	case err == nil:
 		http_utils.SetJSONResponse(ctx, newUser[0], http.StatusCreated)
-	case errors.ErrAlreadyExists:
+	case errors.Is(err, errors.ErrAlreadyExists):
 		http_utils.SetJSONResponse(ctx, newUser, http.StatusConflict)
 	default:
-		http_utils.SetJSONResponse(ctx, errors.ErrInternalError, http.StatusInternalServerError)
+		http_utils.SetErrorResponse(ctx, err)
 	}
 }
 
 func (h *UserHandler) GetUserProfile(ctx *fasthttp.RequestCtx) {
 	userNickName := ctx.UserValue("nickname").(string)
 	if userNickName == "" {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadArguments, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "nickname", Message: "must not be empty"}))
 		return
 	}
 
-	selectedUser, err := h.UserUCase.GetUserByNickName(userNickName)
-	switch err {
-	case nil:
-		http_utils.SetJSONResponse(ctx, selectedUser, http.StatusOK)
-	case errors.ErrUserNotFound:
-		http_utils.SetJSONResponse(ctx, errors.ErrUserNotFound, http.StatusNotFound)
-	default:
-		http_utils.SetJSONResponse(ctx, errors.ErrInternalError, http.StatusInternalServerError)
+	selectedUser, err := h.UserUCase.GetUserByNickName(http_utils.Context(ctx), userNickName)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
 	}
+
+	http_utils.SetJSONResponse(ctx, selectedUser, http.StatusOK)
 }
 
 func (h *UserHandler) GetUsersByForum(ctx *fasthttp.RequestCtx) {
 	forumSlug := ctx.UserValue("slug").(string)
 	if forumSlug == "" {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadArguments, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "slug", Message: "must not be empty"}))
 		return
 	}
 
@@ -84,38 +84,33 @@ func (h *UserHandler) GetUsersByForum(ctx *fasthttp.RequestCtx) {
 		userPaginator.Limit = uint64(parseLimit)
 	}
 
-	selectedUsers, err := h.UserUCase.GetUsersByForum(forumSlug, userPaginator)
-	switch err {
-	case nil:
-		http_utils.SetJSONResponse(ctx, selectedUsers, http.StatusOK)
-	case errors.ErrForumNotFound:
-		http_utils.SetJSONResponse(ctx, errors.Error{Message: fmt.Sprintf("Can't find forum by slug: %s", forumSlug)}, http.StatusNotFound)
-	default:
-		http_utils.SetJSONResponse(ctx, errors.ErrInternalError, http.StatusInternalServerError)
+	selectedUsers, err := h.UserUCase.GetUsersByForum(http_utils.Context(ctx), forumSlug, userPaginator)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
 	}
+
+	http_utils.SetJSONResponse(ctx, selectedUsers, http.StatusOK)
 }
 
 func (h *UserHandler) UpdateUserProfile(ctx *fasthttp.RequestCtx) {
 	userInfo := &models.User{}
 	if err := json.Unmarshal(ctx.PostBody(), userInfo); err != nil {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadRequest, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadRequest.Wrap(err))
 		return
 	}
 
 	if userInfo.NickName = ctx.UserValue("nickname").(string); userInfo.NickName == "" {
-		http_utils.SetJSONResponse(ctx, errors.ErrBadArguments, http.StatusBadRequest)
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "nickname", Message: "must not be empty"}))
 		return
 	}
 
-	updatedUser, err := h.UserUCase.SetUserProfile(userInfo)
-	switch err {
-	case nil:
-		http_utils.SetJSONResponse(ctx, updatedUser, http.StatusOK)
-	case errors.ErrUserNotFound:
-		http_utils.SetJSONResponse(ctx, errors.ErrUserNotFound, http.StatusNotFound)
-	case errors.ErrAlreadyExists:
-		http_utils.SetJSONResponse(ctx, errors.ErrAlreadyExists, http.StatusConflict)
-	default:
-		http_utils.SetJSONResponse(ctx, errors.ErrInternalError, http.StatusInternalServerError)
+	updatedUser, err := h.UserUCase.SetUserProfile(http_utils.Context(ctx), userInfo)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
 	}
+
+	http_utils.SetJSONResponse(ctx, updatedUser, http.StatusOK)
 }
@@ -1,11 +1,15 @@
 package user
 
-import "github.com/forum-api-back/internal/pkg/models"
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
 
 type Repository interface {
-	InsertUser(userInfo *models.User) error
-	SelectUserByEmailOrNickname(email, nickname string) ([]*models.User, error)
-	SelectUserByNickName(nickname string) (*models.User, error)
-	SelectUsersByForum(forumSlug string, paginator *models.UserPaginator) ([]*models.User, error)
-	UpdateUserProfile(userInfo *models.User) error
+	InsertUser(ctx context.Context, userInfo *models.User) error
+	SelectUserByEmailOrNickname(ctx context.Context, email, nickname string) ([]*models.User, error)
+	SelectUserByNickName(ctx context.Context, nickname string) (*models.User, error)
+	SelectUsersByForum(ctx context.Context, forumSlug string, paginator *models.UserPaginator) ([]*models.User, error)
+	UpdateUserProfile(ctx context.Context, userInfo *models.User) error
 }
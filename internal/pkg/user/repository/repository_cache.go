@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/cache"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/user"
+)
+
+// CachedRepository reads users by nickname through a cache.
+type CachedRepository struct {
+	user.Repository
+	cache cache.Cache
+}
+
+func NewCachedRepository(repo user.Repository, c cache.Cache) user.Repository {
+	return &CachedRepository{
+		Repository: repo,
+		cache:      c,
+	}
+}
+
+func (r *CachedRepository) SelectUserByNickName(ctx context.Context, nickname string) (*models.User, error) {
+	var cachedUser models.User
+	if r.cache.Get(ctx, cache.UserKey(nickname), &cachedUser) {
+		return &cachedUser, nil
+	}
+
+	selectedUser, err := r.Repository.SelectUserByNickName(ctx, nickname)
+	if err != nil {
+		return nil, err
+	}
+	r.cache.Set(ctx, cache.UserKey(nickname), *selectedUser)
+
+	return selectedUser, nil
+}
+
+func (r *CachedRepository) UpdateUserProfile(ctx context.Context, userInfo *models.User) error {
+	err := r.Repository.UpdateUserProfile(ctx, userInfo)
+	r.cache.Delete(ctx, cache.UserKey(userInfo.NickName))
+	return err
+}
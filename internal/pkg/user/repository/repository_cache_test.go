@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/cache/lru"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+)
+
+func TestCachedRepositoryInvalidatesOnUpdate(t *testing.T) {
+	storage := memory.NewStorage()
+	memoryRepo := NewSessionMemoryRepository(storage)
+	r := NewCachedRepository(memoryRepo, lru.NewCache(10, time.Hour))
+	ctx := context.Background()
+	if err := r.InsertUser(ctx, &models.User{NickName: "alice", Email: "alice@example.com", About: "old"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.SelectUserByNickName(ctx, "ALICE"); err != nil {
+		t.Fatal(err)
+	}
+	storage.Users["alice"].FullName = "Changed"
+	if cachedUser, _ := r.SelectUserByNickName(ctx, "Alice"); cachedUser.FullName != "" {
+		t.Fatalf("full name = %q, want the cached one", cachedUser.FullName)
+	}
+
+	if err := r.UpdateUserProfile(ctx, &models.User{NickName: "alice", About: "new", PasswordHash: "hash"}); err != nil {
+		t.Fatal(err)
+	}
+	selectedUser, err := r.SelectUserByNickName(ctx, "ALICE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if selectedUser.About != "new" || selectedUser.PasswordHash != "hash" || selectedUser.FullName != "Changed" {
+		t.Errorf("user = %+v, want the updated one", selectedUser)
+	}
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+	"github.com/forum-api-back/internal/pkg/user"
+	"github.com/forum-api-back/pkg/errors"
+)
+
+type MemoryRepository struct {
+	storage *memory.Storage
+}
+
+func NewSessionMemoryRepository(storage *memory.Storage) user.Repository {
+	return &MemoryRepository{
+		storage: storage,
+	}
+}
+
+func (r *MemoryRepository) InsertUser(ctx context.Context, userInfo *models.User) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	nicknameKey, emailKey := memory.Key(userInfo.NickName), memory.Key(userInfo.Email)
+	if _, ok := r.storage.Users[nicknameKey]; ok {
+		return errors.ErrDataConflict
+	}
+	if _, ok := r.storage.UserEmails[emailKey]; ok {
+		return errors.ErrDataConflict
+	}
+
+	newUser := *userInfo
+	r.storage.Users[nicknameKey] = &newUser
+	r.storage.UserEmails[emailKey] = nicknameKey
+
+	return nil
+}
+
+func (r *MemoryRepository) SelectUserByEmailOrNickname(ctx context.Context, email, nickname string) ([]*models.User, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	users := make([]*models.User, 0)
+	nicknameKey := memory.Key(nickname)
+	if selectedUser, ok := r.storage.Users[nicknameKey]; ok {
+		copiedUser := *selectedUser
+		users = append(users, &copiedUser)
+	}
+	if emailOwner, ok := r.storage.UserEmails[memory.Key(email)]; ok && emailOwner != nicknameKey {
+		copiedUser := *r.storage.Users[emailOwner]
+		users = append(users, &copiedUser)
+	}
+
+	return users, nil
+}
+
+func (r *MemoryRepository) SelectUserByNickName(ctx context.Context, nickname string) (*models.User, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	selectedUser, ok := r.storage.UserByNickName(nickname)
+	if !ok {
+		return nil, errors.ErrNotFoundInDB
+	}
+
+	copiedUser := *selectedUser
+	return &copiedUser, nil
+}
+
+func (r *MemoryRepository) SelectUsersByForum(ctx context.Context, forumSlug string,
+	paginator *models.UserPaginator) ([]*models.User, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	sinceKey := memory.Key(paginator.Since)
+	users := make([]*models.User, 0)
+	for nicknameKey := range r.storage.Authors[memory.Key(forumSlug)] {
+		if paginator.Since != "" {
+			if paginator.SortOrder && nicknameKey >= sinceKey ||
+				!paginator.SortOrder && nicknameKey <= sinceKey {
+				continue
+			}
+		}
+		copiedUser := *r.storage.Users[nicknameKey]
+		users = append(users, &copiedUser)
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		if paginator.SortOrder {
+			return memory.Key(users[i].NickName) > memory.Key(users[j].NickName)
+		}
+		return memory.Key(users[i].NickName) < memory.Key(users[j].NickName)
+	})
+	if uint64(len(users)) > paginator.Limit {
+		users = users[:paginator.Limit]
+	}
+
+	return users, nil
+}
+
+func (r *MemoryRepository) UpdateUserProfile(ctx context.Context, userInfo *models.User) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	nicknameKey := memory.Key(userInfo.NickName)
+	selectedUser, ok := r.storage.Users[nicknameKey]
+	if !ok {
+		return nil
+	}
+
+	if userInfo.Email != "" {
+		emailKey := memory.Key(userInfo.Email)
+		if emailOwner, ok := r.storage.UserEmails[emailKey]; ok && emailOwner != nicknameKey {
+			return errors.ErrDataConflict
+		}
+		delete(r.storage.UserEmails, memory.Key(selectedUser.Email))
+		r.storage.UserEmails[emailKey] = nicknameKey
+		selectedUser.Email = userInfo.Email
+	}
+	if userInfo.FullName != "" {
+		selectedUser.FullName = userInfo.FullName
+	}
+	if userInfo.About != "" {
+		selectedUser.About = userInfo.About
+	}
+	if userInfo.PasswordHash != "" {
+		selectedUser.PasswordHash = userInfo.PasswordHash
+	}
+
+	return nil
+}
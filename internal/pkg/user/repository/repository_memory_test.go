@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+	"github.com/forum-api-back/pkg/errors"
+)
+
+func nicknames(users []*models.User) []string {
+	names := make([]string, len(users))
+	for i, selectedUser := range users {
+		names[i] = selectedUser.NickName
+	}
+	return names
+}
+
+func TestSelectUsersByForum(t *testing.T) {
+	tests := []struct {
+		name      string
+		forum     string
+		paginator models.UserPaginator
+		want      []string
+	}{
+		{"ascending ignores case", "f", models.UserPaginator{Limit: 100}, []string{"alice", "Bob", "carol", "dave"}},
+		{"descending", "f", models.UserPaginator{Limit: 100, SortOrder: true}, []string{"dave", "carol", "Bob", "alice"}},
+		{"limit", "f", models.UserPaginator{Limit: 2}, []string{"alice", "Bob"}},
+		{"since excludes the user", "f", models.UserPaginator{Limit: 100, Since: "BOB"}, []string{"carol", "dave"}},
+		{"since descending", "f", models.UserPaginator{Limit: 100, Since: "carol", SortOrder: true}, []string{"Bob", "alice"}},
+		{"other forum", "g", models.UserPaginator{Limit: 100}, []string{"eve"}},
+		{"unknown forum", "h", models.UserPaginator{Limit: 100}, []string{}},
+	}
+
+	storage := memory.NewStorage()
+	r := NewSessionMemoryRepository(storage)
+	for _, nickname := range []string{"dave", "Bob", "alice", "carol", "eve"} {
+		if err := r.InsertUser(context.Background(), &models.User{NickName: nickname, Email: nickname + "@example.com"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, nickname := range []string{"dave", "bob", "alice", "carol", "Alice"} {
+		storage.AddAuthor(nickname, "f")
+	}
+	storage.AddAuthor("eve", "G")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, err := r.SelectUsersByForum(context.Background(), tt.forum, &tt.paginator)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := nicknames(users); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectUsersByForum() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInsertUserRejectsTakenNames(t *testing.T) {
+	tests := []struct {
+		name string
+		user models.User
+	}{
+		{"taken nickname", models.User{NickName: "ALICE", Email: "other@example.com"}},
+		{"taken email", models.User{NickName: "bob", Email: "Alice@Example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewSessionMemoryRepository(memory.NewStorage())
+			ctx := context.Background()
+			if err := r.InsertUser(ctx, &models.User{NickName: "alice", Email: "alice@example.com"}); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := r.InsertUser(ctx, &tt.user); !errors.Is(err, errors.ErrDataConflict) {
+				t.Errorf("InsertUser() error = %v, want %v", err, errors.ErrDataConflict)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/user"
+	"github.com/forum-api-back/pkg/logger"
+	"github.com/forum-api-back/pkg/metrics"
+)
+
+// MeasuredRepository observes how long every method takes and logs the
+// calls taking slowQuery or longer.
+type MeasuredRepository struct {
+	repo      user.Repository
+	durations *metrics.HistogramVec
+	slowQuery time.Duration
+}
+
+// NewMeasuredRepository observes the durations of the methods of repo into
+// durations, labeled with the repository and the method. Calls taking
+// slowQuery or longer are logged as well, unless it is zero.
+func NewMeasuredRepository(repo user.Repository, durations *metrics.HistogramVec,
+	slowQuery time.Duration) user.Repository {
+	return &MeasuredRepository{
+		repo:      repo,
+		durations: durations,
+		slowQuery: slowQuery,
+	}
+}
+
+func (r *MeasuredRepository) InsertUser(ctx context.Context, userInfo *models.User) error {
+	defer r.observe(ctx, "InsertUser", time.Now())
+	return r.repo.InsertUser(ctx, userInfo)
+}
+
+func (r *MeasuredRepository) SelectUserByEmailOrNickname(ctx context.Context, email,
+	nickname string) ([]*models.User, error) {
+	defer r.observe(ctx, "SelectUserByEmailOrNickname", time.Now())
+	return r.repo.SelectUserByEmailOrNickname(ctx, email, nickname)
+}
+
+func (r *MeasuredRepository) SelectUserByNickName(ctx context.Context, nickname string) (*models.User, error) {
+	defer r.observe(ctx, "SelectUserByNickName", time.Now())
+	return r.repo.SelectUserByNickName(ctx, nickname)
+}
+
+func (r *MeasuredRepository) SelectUsersByForum(ctx context.Context, forumSlug string,
+	paginator *models.UserPaginator) ([]*models.User, error) {
+	defer r.observe(ctx, "SelectUsersByForum", time.Now())
+	return r.repo.SelectUsersByForum(ctx, forumSlug, paginator)
+}
+
+func (r *MeasuredRepository) UpdateUserProfile(ctx context.Context, userInfo *models.User) error {
+	defer r.observe(ctx, "UpdateUserProfile", time.Now())
+	return r.repo.UpdateUserProfile(ctx, userInfo)
+}
+
+func (r *MeasuredRepository) observe(ctx context.Context, method string, start time.Time) {
+	elapsed := time.Since(start)
+	r.durations.With("user", method).Observe(elapsed.Seconds())
+	if r.slowQuery > 0 && elapsed >= r.slowQuery {
+		logger.FromContext(ctx).Warn("slow query", "repository", "user", "method", method, "duration", elapsed)
+	}
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -8,6 +9,7 @@ import (
 	"github.com/forum-api-back/internal/pkg/models"
 	"github.com/forum-api-back/internal/pkg/user"
 	"github.com/forum-api-back/pkg/errors"
+	"github.com/forum-api-back/pkg/tools/sql_utils"
 )
 
 type PostgresqlRepository struct {
@@ -20,25 +22,22 @@ func NewSessionPostgresqlRepository(db *sql.DB) user.Repository {
 	}
 }
 
-func (r *PostgresqlRepository) InsertUser(userInfo *models.User) error {
-	_, err := r.db.Exec(
-		"INSERT INTO users(nickname, fullname, about, email) "+
-			"VALUES ($1, $2, $3, $4)",
+func (r *PostgresqlRepository) InsertUser(ctx context.Context, userInfo *models.User) error {
+	_, err := r.db.ExecContext(ctx,
+		"INSERT INTO users(nickname, fullname, about, email, password_hash) "+
+			"VALUES ($1, $2, $3, $4, $5)",
 		userInfo.NickName,
 		userInfo.FullName,
 		userInfo.About,
 		userInfo.Email,
+		sql.NullString{String: userInfo.PasswordHash, Valid: userInfo.PasswordHash != ""},
 	)
 
-	if err != nil {
-		return errors.ErrDataConflict
-	}
-
-	return nil
+	return sql_utils.Error(err, errors.ErrNotFoundInDB)
 }
 
-func (r *PostgresqlRepository) SelectUserByEmailOrNickname(email, nickname string) ([]*models.User, error) {
-	rows, err := r.db.Query(
+func (r *PostgresqlRepository) SelectUserByEmailOrNickname(ctx context.Context, email, nickname string) ([]*models.User, error) {
+	rows, err := r.db.QueryContext(ctx,
 		"SELECT nickname, fullname, about, email "+
 			"FROM users "+
 			"WHERE nickname = $1 OR email = $2",
@@ -47,7 +46,7 @@ func (r *PostgresqlRepository) SelectUserByEmailOrNickname(email, nickname strin
 	)
 
 	if err != nil {
-		return nil, errors.ErrNotFoundInDB
+		return nil, sql_utils.Error(err, errors.ErrNotFoundInDB)
 	}
 	defer rows.Close()
 
@@ -64,18 +63,22 @@ func (r *PostgresqlRepository) SelectUserByEmailOrNickname(email, nickname strin
 		selectedUser.About = about.String
 
 		if err != nil {
-			return nil, errors.ErrInternalError
+			return nil, sql_utils.Error(err, errors.ErrNotFoundInDB)
 		}
 
 		users = append(users, selectedUser)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, sql_utils.Error(err, errors.ErrNotFoundInDB)
+	}
+
 	return users, nil
 }
 
-func (r *PostgresqlRepository) SelectUserByNickName(nickname string) (*models.User, error) {
-	row := r.db.QueryRow(
-		"SELECT nickname, fullname, about, email "+
+func (r *PostgresqlRepository) SelectUserByNickName(ctx context.Context, nickname string) (*models.User, error) {
+	row := r.db.QueryRowContext(ctx,
+		"SELECT nickname, fullname, about, email, password_hash "+
 			"FROM users "+
 			"WHERE nickname = $1",
 		nickname,
@@ -83,25 +86,25 @@ func (r *PostgresqlRepository) SelectUserByNickName(nickname string) (*models.Us
 
 	selectedUser := &models.User{}
 	about := sql.NullString{}
+	passwordHash := sql.NullString{}
 	err := row.Scan(
 		&selectedUser.NickName,
 		&selectedUser.FullName,
 		&about,
 		&selectedUser.Email,
+		&passwordHash,
 	)
 	selectedUser.About = about.String
+	selectedUser.PasswordHash = passwordHash.String
 
-	switch err {
-	case nil:
-		return selectedUser, nil
-	case sql.ErrNoRows:
-		return nil, errors.ErrNotFoundInDB
-	default:
-		return nil, errors.ErrInternalError
+	if err != nil {
+		return nil, sql_utils.Error(err, errors.ErrNotFoundInDB)
 	}
+
+	return selectedUser, nil
 }
 
-func (r *PostgresqlRepository) SelectUsersByForum(forumSlug string,
+func (r *PostgresqlRepository) SelectUsersByForum(ctx context.Context, forumSlug string,
 	paginator *models.UserPaginator) ([]*models.User, error) {
 	var orderSort, orderCompare string
 	if paginator.SortOrder {
@@ -115,7 +118,7 @@ func (r *PostgresqlRepository) SelectUsersByForum(forumSlug string,
 	var rows *sql.Rows
 	var err error
 	if paginator.Since == "" {
-		rows, err = r.db.Query(
+		rows, err = r.db.QueryContext(ctx,
 			"SELECT u.nickname, u.fullname, u.about, u.email "+
 				"FROM users u "+
 				"JOIN authors a ON (u.nickname = a.user_nickname AND a.forum_slug = $1) "+
@@ -125,7 +128,7 @@ func (r *PostgresqlRepository) SelectUsersByForum(forumSlug string,
 			paginator.Limit,
 		)
 	} else {
-		rows, err = r.db.Query(
+		rows, err = r.db.QueryContext(ctx,
 			"SELECT u.nickname, u.fullname, u.about, u.email "+
 				"FROM users u "+
 				"JOIN authors a ON (u.nickname = a.user_nickname AND a.forum_slug = $1) "+
@@ -138,35 +141,37 @@ func (r *PostgresqlRepository) SelectUsersByForum(forumSlug string,
 		)
 	}
 
-	switch err {
-	case nil:
-		defer rows.Close()
-		users := make([]*models.User, 0)
-		for rows.Next() {
-			about := sql.NullString{}
-			selectedUser := &models.User{}
-			err := rows.Scan(
-				&selectedUser.NickName,
-				&selectedUser.FullName,
-				&about,
-				&selectedUser.Email,
-			)
-			selectedUser.About = about.String
-			if err != nil {
-				return nil, errors.ErrInternalError
-			}
-
-			users = append(users, selectedUser)
+	if err != nil {
+		return nil, sql_utils.Error(err, errors.ErrNotFoundInDB)
+	}
+	defer rows.Close()
+
+	users := make([]*models.User, 0)
+	for rows.Next() {
+		about := sql.NullString{}
+		selectedUser := &models.User{}
+		err := rows.Scan(
+			&selectedUser.NickName,
+			&selectedUser.FullName,
+			&about,
+			&selectedUser.Email,
+		)
+		selectedUser.About = about.String
+		if err != nil {
+			return nil, sql_utils.Error(err, errors.ErrNotFoundInDB)
 		}
-		return users, nil
-	case sql.ErrNoRows:
-		return nil, errors.ErrNotFoundInDB
-	default:
-		return nil, errors.ErrInternalError
+
+		users = append(users, selectedUser)
 	}
+
+	if err := rows.Err(); err != nil {
+		return nil, sql_utils.Error(err, errors.ErrNotFoundInDB)
+	}
+
+	return users, nil
 }
 
-func (r *PostgresqlRepository) UpdateUserProfile(userInfo *models.User) error {
+func (r *PostgresqlRepository) UpdateUserProfile(ctx context.Context, userInfo *models.User) error {
 	columns := make([]string, 0)
 	args := make([]interface{}, 1)
 	args[0] = userInfo.NickName
@@ -182,21 +187,21 @@ func (r *PostgresqlRepository) UpdateUserProfile(userInfo *models.User) error {
 		args = append(args, userInfo.About)
 		columns = append(columns, fmt.Sprintf("about = $%d ", len(args)))
 	}
+	if userInfo.PasswordHash != "" {
+		args = append(args, userInfo.PasswordHash)
+		columns = append(columns, fmt.Sprintf("password_hash = $%d ", len(args)))
+	}
 
 	if len(columns) == 0 {
 		return nil
 	}
 
-	_, err := r.db.Exec(
+	_, err := r.db.ExecContext(ctx,
 		"UPDATE users SET "+
 			strings.Join(columns, ", ")+
 			" WHERE nickname = $1",
 		args...,
 	)
 
-	if err != nil {
-		return errors.ErrDataConflict
-	}
-
-	return nil
+	return sql_utils.Error(err, errors.ErrNotFoundInDB)
 }
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/user"
+	"github.com/forum-api-back/pkg/tracing"
+)
+
+// TracedRepository records a span for every method called within a traced request.
+type TracedRepository struct {
+	repo user.Repository
+}
+
+func NewTracedRepository(repo user.Repository) user.Repository {
+	return &TracedRepository{
+		repo: repo,
+	}
+}
+
+func (r *TracedRepository) InsertUser(ctx context.Context, userInfo *models.User) (err error) {
+	ctx, span := tracing.Start(ctx, "user.repository.InsertUser")
+	defer func() { span.End(err) }()
+	return r.repo.InsertUser(ctx, userInfo)
+}
+
+func (r *TracedRepository) SelectUserByEmailOrNickname(ctx context.Context, email,
+	nickname string) (_ []*models.User, err error) {
+	ctx, span := tracing.Start(ctx, "user.repository.SelectUserByEmailOrNickname")
+	defer func() { span.End(err) }()
+	return r.repo.SelectUserByEmailOrNickname(ctx, email, nickname)
+}
+
+func (r *TracedRepository) SelectUserByNickName(ctx context.Context,
+	nickname string) (_ *models.User, err error) {
+	ctx, span := tracing.Start(ctx, "user.repository.SelectUserByNickName")
+	defer func() { span.End(err) }()
+	return r.repo.SelectUserByNickName(ctx, nickname)
+}
+
+func (r *TracedRepository) SelectUsersByForum(ctx context.Context, forumSlug string,
+	paginator *models.UserPaginator) (_ []*models.User, err error) {
+	ctx, span := tracing.Start(ctx, "user.repository.SelectUsersByForum")
+	defer func() { span.End(err) }()
+	return r.repo.SelectUsersByForum(ctx, forumSlug, paginator)
+}
+
+func (r *TracedRepository) UpdateUserProfile(ctx context.Context, userInfo *models.User) (err error) {
+	ctx, span := tracing.Start(ctx, "user.repository.UpdateUserProfile")
+	defer func() { span.End(err) }()
+	return r.repo.UpdateUserProfile(ctx, userInfo)
+}
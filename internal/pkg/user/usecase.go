@@ -1,10 +1,14 @@
 package user
 
-import "github.com/forum-api-back/internal/pkg/models"
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
 
 type UseCase interface {
-	CreateNewUser(userInfo *models.User) ([]*models.User, error)
-	GetUserByNickName(userNickName string) (*models.User, error)
-	GetUsersByForum(forumSlug string, paginator *models.UserPaginator) ([]*models.User, error)
-	SetUserProfile(userInfo *models.User) (*models.User, error)
+	CreateNewUser(ctx context.Context, userInfo *models.User) ([]*models.User, error)
+	GetUserByNickName(ctx context.Context, userNickName string) (*models.User, error)
+	GetUsersByForum(ctx context.Context, forumSlug string, paginator *models.UserPaginator) ([]*models.User, error)
+	SetUserProfile(ctx context.Context, userInfo *models.User) (*models.User, error)
 }
@@ -1,74 +1,101 @@
 package usecase
 
 import (
+	"context"
+	"strings"
+
+	"github.com/forum-api-back/internal/pkg/auth"
+	"github.com/forum-api-back/internal/pkg/events"
 	"github.com/forum-api-back/internal/pkg/forum"
 	"github.com/forum-api-back/internal/pkg/models"
 	"github.com/forum-api-back/internal/pkg/user"
 	"github.com/forum-api-back/pkg/errors"
+	"github.com/forum-api-back/pkg/tools/auth_utils"
 )
 
 type UserUseCase struct {
 	UserRepo  user.Repository
 	ForumRepo forum.Repository
+	Events    events.Publisher
 }
 
-func NewUseCase(userRepo user.Repository, forumRepo forum.Repository) user.UseCase {
+func NewUseCase(userRepo user.Repository, forumRepo forum.Repository, publisher events.Publisher) user.UseCase {
 	return &UserUseCase{
 		UserRepo:  userRepo,
 		ForumRepo: forumRepo,
+		Events:    publisher,
 	}
 }
 
-func (u *UserUseCase) CreateNewUser(userInfo *models.User) ([]*models.User, error) {
-	err := u.UserRepo.InsertUser(userInfo)
-	switch err {
-	case nil:
+func (u *UserUseCase) CreateNewUser(ctx context.Context, userInfo *models.User) ([]*models.User, error) {
+	if err := hashPassword(userInfo); err != nil {
+		return nil, err
+	}
+
+	err := u.UserRepo.InsertUser(ctx, userInfo)
+	switch {
+	case err == nil:
+		copiedUser := *userInfo
+		copiedUser.Password, copiedUser.PasswordHash = "", ""
+		u.Events.Publish(ctx, &events.UserCreated{User: &copiedUser})
 		return []*models.User{userInfo}, nil
-	case errors.ErrDataConflict:
-		selectedUser, err := u.UserRepo.SelectUserByEmailOrNickname(userInfo.Email, userInfo.NickName)
-		if err != nil {
-			return nil, errors.ErrInternalError
+	case errors.Is(err, errors.ErrDataConflict):
+		selectedUser, selectErr := u.UserRepo.SelectUserByEmailOrNickname(ctx, userInfo.Email, userInfo.NickName)
+		if selectErr != nil {
+			return nil, selectErr
 		}
-		return selectedUser, errors.ErrAlreadyExists
+		return selectedUser, errors.ErrAlreadyExists.Wrap(err)
 	default:
-		return nil, errors.ErrInternalError
+		return nil, err
 	}
 }
 
-func (u *UserUseCase) GetUserByNickName(userNickName string) (*models.User, error) {
-	selectedUser, err := u.UserRepo.SelectUserByNickName(userNickName)
+func (u *UserUseCase) GetUserByNickName(ctx context.Context, userNickName string) (*models.User, error) {
+	selectedUser, err := u.UserRepo.SelectUserByNickName(ctx, userNickName)
 	if err != nil {
-		return nil, errors.ErrUserNotFound
+		return nil, errors.Translate(err, errors.ErrUserNotFound.WithMessage("Can't find user by nickname: %s", userNickName))
 	}
 
 	return selectedUser, nil
 }
 
-func (u *UserUseCase) GetUsersByForum(forumSlug string, paginator *models.UserPaginator) ([]*models.User, error) {
-	if _, err := u.ForumRepo.SelectForumBySlug(forumSlug); err != nil {
-		return nil, errors.ErrForumNotFound
+func (u *UserUseCase) GetUsersByForum(ctx context.Context, forumSlug string, paginator *models.UserPaginator) ([]*models.User, error) {
+	if _, err := u.ForumRepo.SelectForumBySlug(ctx, forumSlug); err != nil {
+		return nil, errors.Translate(err, errors.ErrForumNotFound.WithMessage("Can't find forum by slug: %s", forumSlug))
 	}
 
-	selectedUsers, err := u.UserRepo.SelectUsersByForum(forumSlug, paginator)
-	switch err {
-	case nil:
-		return selectedUsers, nil
-	case errors.ErrNotFoundInDB:
-		return nil, errors.ErrUserNotFound
-	default:
-		return nil, errors.ErrInternalError
+	selectedUsers, err := u.UserRepo.SelectUsersByForum(ctx, forumSlug, paginator)
+	if err != nil {
+		return nil, errors.Translate(err, errors.ErrUserNotFound)
 	}
+
+	return selectedUsers, nil
 }
 
-func (u *UserUseCase) SetUserProfile(userInfo *models.User) (*models.User, error) {
-	selectedUser, err := u.UserRepo.SelectUserByNickName(userInfo.NickName)
+func (u *UserUseCase) SetUserProfile(ctx context.Context, userInfo *models.User) (*models.User, error) {
+	selectedUser, err := u.UserRepo.SelectUserByNickName(ctx, userInfo.NickName)
 	if err != nil {
-		return nil, errors.ErrUserNotFound
+		return nil, errors.Translate(err, errors.ErrUserNotFound.WithMessage("Can't find user by nickname: %s", userInfo.NickName))
+	}
+
+	// Users who set a password can only be changed from their own session,
+	// and passwords are only set from it, so that nobody claims an account
+	// which has none yet.
+	caller, ok := auth.Caller(ctx)
+	switch {
+	case ok && !strings.EqualFold(caller, selectedUser.NickName):
+		return nil, errors.ErrForbidden.WithMessage("Can't change profile of user: %s", userInfo.NickName)
+	case !ok && (selectedUser.PasswordHash != "" || userInfo.Password != ""):
+		return nil, errors.ErrUnauthorized
 	}
 
-	err = u.UserRepo.UpdateUserProfile(userInfo)
-	switch err {
-	case nil:
+	if err := hashPassword(userInfo); err != nil {
+		return nil, err
+	}
+
+	err = u.UserRepo.UpdateUserProfile(ctx, userInfo)
+	switch {
+	case err == nil:
 		if userInfo.Email != "" {
 			selectedUser.Email = userInfo.Email
 		}
@@ -79,11 +106,25 @@ func (u *UserUseCase) SetUserProfile(userInfo *models.User) (*models.User, error
 			selectedUser.FullName = userInfo.FullName
 		}
 		return selectedUser, nil
-	case errors.ErrNotFoundInDB:
-		return nil, errors.ErrUserNotFound
-	case errors.ErrDataConflict:
-		return nil, errors.ErrAlreadyExists
+	case errors.Is(err, errors.ErrDataConflict):
+		return nil, errors.ErrAlreadyExists.Wrap(err)
 	default:
-		return nil, errors.ErrInternalError
+		return nil, errors.Translate(err, errors.ErrUserNotFound)
+	}
+}
+
+// hashPassword replaces the plain password of userInfo with its hash, so
+// it is neither stored nor echoed back.
+func hashPassword(userInfo *models.User) error {
+	if userInfo.Password == "" {
+		return nil
+	}
+
+	passwordHash, err := auth_utils.HashPassword(userInfo.Password)
+	if err != nil {
+		return errors.ErrInternalError.Wrap(err)
 	}
+	userInfo.PasswordHash = passwordHash
+	userInfo.Password = ""
+	return nil
 }
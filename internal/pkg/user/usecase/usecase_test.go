@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/forum-api-back/internal/pkg/auth"
+	events_dispatcher "github.com/forum-api-back/internal/pkg/events/dispatcher"
+	forum_repo "github.com/forum-api-back/internal/pkg/forum/repository"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+	"github.com/forum-api-back/internal/pkg/user"
+	user_repo "github.com/forum-api-back/internal/pkg/user/repository"
+	"github.com/forum-api-back/pkg/errors"
+	"github.com/forum-api-back/pkg/tools/auth_utils"
+)
+
+func newTestUseCase(t *testing.T) (user.UseCase, *memory.Storage) {
+	t.Helper()
+
+	storage := memory.NewStorage()
+	return NewUseCase(user_repo.NewSessionMemoryRepository(storage), forum_repo.NewSessionMemoryRepository(storage),
+		events_dispatcher.NewDispatcher(0)), storage
+}
+
+func checkCode(t *testing.T, err error, code errors.Code) {
+	t.Helper()
+	switch {
+	case code == "" && err != nil:
+		t.Fatalf("error = %v", err)
+	case code != "" && errors.From(err).Code != code:
+		t.Fatalf("error = %v, want code %v", err, code)
+	}
+}
+
+func TestCreateNewUser(t *testing.T) {
+	tests := []struct {
+		name string
+		user models.User
+		want []string
+		code errors.Code
+	}{
+		{"new user", models.User{NickName: "bob", Email: "bob@example.com"}, []string{"bob"}, ""},
+		{"taken nickname", models.User{NickName: "ALICE", Email: "bob@example.com"}, []string{"alice"}, errors.CodeConflict},
+		{"taken email", models.User{NickName: "bob", Email: "ALICE@example.com"}, []string{"alice"}, errors.CodeConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := newTestUseCase(t)
+			ctx := context.Background()
+			if _, err := u.CreateNewUser(ctx, &models.User{NickName: "alice", Email: "alice@example.com"}); err != nil {
+				t.Fatal(err)
+			}
+
+			users, err := u.CreateNewUser(ctx, &tt.user)
+			checkCode(t, err, tt.code)
+			if len(users) != len(tt.want) {
+				t.Fatalf("users = %v, want %v", users, tt.want)
+			}
+			for i, selectedUser := range users {
+				if selectedUser.NickName != tt.want[i] {
+					t.Errorf("user %d = %q, want %q", i, selectedUser.NickName, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCreateNewUserHashesPassword(t *testing.T) {
+	u, storage := newTestUseCase(t)
+
+	users, err := u.CreateNewUser(context.Background(), &models.User{NickName: "alice", Email: "alice@example.com", Password: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if users[0].Password != "" {
+		t.Errorf("password %q is echoed back", users[0].Password)
+	}
+	if !auth_utils.CheckPassword(storage.Users["alice"].PasswordHash, "secret") {
+		t.Errorf("stored hash %q doesn't match the password", storage.Users["alice"].PasswordHash)
+	}
+}
+
+func TestSetUserProfile(t *testing.T) {
+	tests := []struct {
+		name     string
+		caller   string
+		nickname string
+		code     errors.Code
+	}{
+		{"no session, user without password", "", "bob", ""},
+		{"no session, user with password", "", "alice", errors.CodeUnauthorized},
+		{"own session", "ALICE", "alice", ""},
+		{"other session", "bob", "alice", errors.CodeForbidden},
+		{"unknown user", "", "carol", errors.CodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, storage := newTestUseCase(t)
+			ctx := context.Background()
+			for _, newUser := range []*models.User{
+				{NickName: "alice", Email: "alice@example.com", Password: "secret"},
+				{NickName: "bob", Email: "bob@example.com"},
+			} {
+				if _, err := u.CreateNewUser(ctx, newUser); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.caller != "" {
+				ctx = auth.WithCaller(ctx, tt.caller)
+			}
+
+			updatedUser, err := u.SetUserProfile(ctx, &models.User{NickName: tt.nickname, About: "updated"})
+			checkCode(t, err, tt.code)
+			if err == nil && (updatedUser.About != "updated" || storage.Users[tt.nickname].About != "updated") {
+				t.Errorf("about = %q, stored %q, want updated", updatedUser.About, storage.Users[tt.nickname].About)
+			}
+		})
+	}
+}
+
+func TestSetUserProfilePassword(t *testing.T) {
+	tests := []struct {
+		name   string
+		caller string
+		code   errors.Code
+	}{
+		{"no session", "", errors.CodeUnauthorized},
+		{"own session", "bob", ""},
+		{"other session", "alice", errors.CodeForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, storage := newTestUseCase(t)
+			ctx := context.Background()
+			if _, err := u.CreateNewUser(ctx, &models.User{NickName: "bob", Email: "bob@example.com"}); err != nil {
+				t.Fatal(err)
+			}
+			if tt.caller != "" {
+				ctx = auth.WithCaller(ctx, tt.caller)
+			}
+
+			_, err := u.SetUserProfile(ctx, &models.User{NickName: "bob", Password: "taken"})
+			checkCode(t, err, tt.code)
+			if claimed := auth_utils.CheckPassword(storage.Users["bob"].PasswordHash, "taken"); claimed != (tt.code == "") {
+				t.Errorf("password set = %v, want %v", claimed, tt.code == "")
+			}
+		})
+	}
+}
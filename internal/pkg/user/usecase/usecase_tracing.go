@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/user"
+	"github.com/forum-api-back/pkg/tracing"
+)
+
+// TracedUseCase records a span for every method called within a traced request.
+type TracedUseCase struct {
+	ucase user.UseCase
+}
+
+func NewTracedUseCase(ucase user.UseCase) user.UseCase {
+	return &TracedUseCase{
+		ucase: ucase,
+	}
+}
+
+func (u *TracedUseCase) CreateNewUser(ctx context.Context,
+	userInfo *models.User) (_ []*models.User, err error) {
+	ctx, span := tracing.Start(ctx, "user.usecase.CreateNewUser")
+	defer func() { span.End(err) }()
+	return u.ucase.CreateNewUser(ctx, userInfo)
+}
+
+func (u *TracedUseCase) GetUserByNickName(ctx context.Context,
+	userNickName string) (_ *models.User, err error) {
+	ctx, span := tracing.Start(ctx, "user.usecase.GetUserByNickName")
+	defer func() { span.End(err) }()
+	return u.ucase.GetUserByNickName(ctx, userNickName)
+}
+
+func (u *TracedUseCase) GetUsersByForum(ctx context.Context, forumSlug string,
+	paginator *models.UserPaginator) (_ []*models.User, err error) {
+	ctx, span := tracing.Start(ctx, "user.usecase.GetUsersByForum")
+	defer func() { span.End(err) }()
+	return u.ucase.GetUsersByForum(ctx, forumSlug, paginator)
+}
+
+func (u *TracedUseCase) SetUserProfile(ctx context.Context,
+	userInfo *models.User) (_ *models.User, err error) {
+	ctx, span := tracing.Start(ctx, "user.usecase.SetUserProfile")
+	defer func() { span.End(err) }()
+	return u.ucase.SetUserProfile(ctx, userInfo)
+}
@@ -0,0 +1,10 @@
+package webhooks
+
+import "github.com/valyala/fasthttp"
+
+type Handler interface {
+	CreateWebhook(ctx *fasthttp.RequestCtx)
+	GetWebhooks(ctx *fasthttp.RequestCtx)
+	DeleteWebhook(ctx *fasthttp.RequestCtx)
+	GetDeliveries(ctx *fasthttp.RequestCtx)
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/forum-api-back/internal/pkg/events"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/webhooks"
+	webhooks_sender "github.com/forum-api-back/internal/pkg/webhooks/sender"
+	"github.com/forum-api-back/pkg/errors"
+	"github.com/forum-api-back/pkg/tools/http_utils"
+
+	"github.com/valyala/fasthttp"
+)
+
+// webhookEvents are the names of the events webhooks may subscribe to.
+var webhookEvents = map[string]bool{
+	(&events.ThreadCreated{}).Name(): true,
+	(&events.ThreadEdited{}).Name():  true,
+	(&events.PostsCreated{}).Name():  true,
+	(&events.PostEdited{}).Name():    true,
+	(&events.VoteCast{}).Name():      true,
+}
+
+type WebhooksHandler struct {
+	WebhooksUCase webhooks.UseCase
+	// AllowPrivate accepts URLs of loopback, private and link-local hosts.
+	AllowPrivate bool
+}
+
+func NewHandler(webhooksUCase webhooks.UseCase, allowPrivate bool) webhooks.Handler {
+	return &WebhooksHandler{
+		WebhooksUCase: webhooksUCase,
+		AllowPrivate:  allowPrivate,
+	}
+}
+
+func (h *WebhooksHandler) CreateWebhook(ctx *fasthttp.RequestCtx) {
+	forumSlug := ctx.UserValue("slug").(string)
+
+	webhookInfo := &models.WebhookCreate{}
+	if err := json.Unmarshal(ctx.PostBody(), webhookInfo); err != nil {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadRequest.Wrap(err))
+		return
+	}
+
+	details := make([]*errors.FieldError, 0)
+	if forumSlug == "" {
+		details = append(details, &errors.FieldError{Field: "slug", Message: "must not be empty"})
+	}
+	parsedURL, err := url.Parse(webhookInfo.URL)
+	switch {
+	case err != nil || parsedURL.Host == "" || parsedURL.Scheme != "http" && parsedURL.Scheme != "https":
+		details = append(details, &errors.FieldError{Field: "url", Message: "must be an absolute http or https URL"})
+	case !h.AllowPrivate && webhooks_sender.CheckHost(parsedURL.Hostname()) != nil:
+		details = append(details, &errors.FieldError{Field: "url", Message: "must not point to a loopback, private " +
+			"or link-local address"})
+	}
+	for _, name := range webhookInfo.Events {
+		if !webhookEvents[name] {
+			details = append(details, &errors.FieldError{Field: "events", Message: "must be one of thread_created, " +
+				"thread_edited, posts_created, post_edited, vote_cast"})
+			break
+		}
+	}
+	if len(details) != 0 {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(details...))
+		return
+	}
+
+	newWebhook, err := h.WebhooksUCase.CreateWebhook(http_utils.Context(ctx), forumSlug, webhookInfo)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, newWebhook, http.StatusCreated)
+}
+
+func (h *WebhooksHandler) GetWebhooks(ctx *fasthttp.RequestCtx) {
+	forumSlug := ctx.UserValue("slug").(string)
+	if forumSlug == "" {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "slug", Message: "must not be empty"}))
+		return
+	}
+
+	forumWebhooks, err := h.WebhooksUCase.GetWebhooks(http_utils.Context(ctx), forumSlug)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, forumWebhooks, http.StatusOK)
+}
+
+func (h *WebhooksHandler) DeleteWebhook(ctx *fasthttp.RequestCtx) {
+	forumSlug, webhookId, ok := webhookArgs(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.WebhooksUCase.DeleteWebhook(http_utils.Context(ctx), forumSlug, webhookId); err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, struct{}{}, http.StatusOK)
+}
+
+// GetDeliveries lists the deliveries of a webhook from the newest one.
+// The since query argument continues the list after the delivery with
+// that id.
+func (h *WebhooksHandler) GetDeliveries(ctx *fasthttp.RequestCtx) {
+	forumSlug, webhookId, ok := webhookArgs(ctx)
+	if !ok {
+		return
+	}
+
+	paginator := &models.DeliveryPaginator{Limit: models.MaxDeliveriesLimit}
+	details := make([]*errors.FieldError, 0)
+	parseUint := func(field string, value *uint64, max uint64) {
+		if arg := ctx.FormValue(field); len(arg) != 0 {
+			parsedValue, err := strconv.ParseUint(string(arg), 10, 64)
+			switch {
+			case err != nil:
+				details = append(details, &errors.FieldError{Field: field, Message: "must be a non-negative integer"})
+			case parsedValue > max:
+				details = append(details, &errors.FieldError{Field: field, Message: "must not be greater than " +
+					strconv.FormatUint(max, 10)})
+			default:
+				*value = parsedValue
+			}
+		}
+	}
+	parseUint("limit", &paginator.Limit, models.MaxDeliveriesLimit)
+	// Ids are bigint, larger ones can't be sent to the database.
+	parseUint("since", &paginator.Since, math.MaxInt64)
+	if len(details) != 0 {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(details...))
+		return
+	}
+
+	deliveries, err := h.WebhooksUCase.GetDeliveries(http_utils.Context(ctx), forumSlug, webhookId, paginator)
+	if err != nil {
+		http_utils.SetErrorResponse(ctx, err)
+		return
+	}
+
+	http_utils.SetJSONResponse(ctx, deliveries, http.StatusOK)
+}
+
+func webhookArgs(ctx *fasthttp.RequestCtx) (string, uint64, bool) {
+	forumSlug := ctx.UserValue("slug").(string)
+	if forumSlug == "" {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "slug", Message: "must not be empty"}))
+		return "", 0, false
+	}
+
+	webhookId, err := strconv.Atoi(ctx.UserValue("id").(string))
+	if err != nil || webhookId < 1 {
+		http_utils.SetErrorResponse(ctx, errors.ErrBadArguments.WithDetails(
+			&errors.FieldError{Field: "id", Message: "must be a positive integer"}))
+		return "", 0, false
+	}
+
+	return forumSlug, uint64(webhookId), true
+}
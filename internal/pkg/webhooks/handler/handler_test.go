@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/forum-api-back/internal/pkg/events"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/pkg/errors"
+
+	"github.com/valyala/fasthttp"
+)
+
+// recordingUseCase keeps the paginator deliveries are listed with.
+type recordingUseCase struct {
+	paginator *models.DeliveryPaginator
+}
+
+func (u *recordingUseCase) CreateWebhook(ctx context.Context, forumSlug string, webhookInfo *models.WebhookCreate) (*models.Webhook, error) {
+	return &models.Webhook{}, nil
+}
+
+func (u *recordingUseCase) GetWebhooks(ctx context.Context, forumSlug string) ([]*models.Webhook, error) {
+	return []*models.Webhook{}, nil
+}
+
+func (u *recordingUseCase) DeleteWebhook(ctx context.Context, forumSlug string, webhookId uint64) error {
+	return nil
+}
+
+func (u *recordingUseCase) GetDeliveries(ctx context.Context, forumSlug string, webhookId uint64,
+	paginator *models.DeliveryPaginator) ([]*models.WebhookDelivery, error) {
+	u.paginator = paginator
+	return []*models.WebhookDelivery{}, nil
+}
+
+func (u *recordingUseCase) Enqueue(ctx context.Context, event events.Event) error {
+	return nil
+}
+
+func TestGetDeliveriesBoundsPages(t *testing.T) {
+	tests := []struct {
+		args   string
+		status int
+		field  string
+		limit  uint64
+		since  uint64
+	}{
+		{"", http.StatusOK, "", 100, 0},
+		{"?limit=20&since=40", http.StatusOK, "", 20, 40},
+		{"?limit=100&since=9223372036854775807", http.StatusOK, "", 100, 9223372036854775807},
+		{"?limit=101", http.StatusBadRequest, "limit", 0, 0},
+		{"?limit=18446744073709551615", http.StatusBadRequest, "limit", 0, 0},
+		{"?since=9223372036854775808", http.StatusBadRequest, "since", 0, 0},
+		{"?limit=-1", http.StatusBadRequest, "limit", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.args, func(t *testing.T) {
+			webhooksUCase := &recordingUseCase{}
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.SetRequestURI("/api/forum/f/webhooks/1/deliveries" + tt.args)
+			ctx.SetUserValue("slug", "f")
+			ctx.SetUserValue("id", "1")
+
+			NewHandler(webhooksUCase, false).GetDeliveries(ctx)
+
+			if ctx.Response.StatusCode() != tt.status {
+				t.Fatalf("status = %d, want %d", ctx.Response.StatusCode(), tt.status)
+			}
+			if tt.status != http.StatusOK {
+				response := &errors.Error{}
+				if err := json.Unmarshal(ctx.Response.Body(), response); err != nil {
+					t.Fatal(err)
+				}
+				if len(response.Details) != 1 || response.Details[0].Field != tt.field {
+					t.Errorf("details = %s, want one about %s", ctx.Response.Body(), tt.field)
+				}
+				return
+			}
+			if webhooksUCase.paginator.Limit != tt.limit || webhooksUCase.paginator.Since != tt.since {
+				t.Errorf("paginator = %+v, want limit %d since %d", webhooksUCase.paginator, tt.limit, tt.since)
+			}
+		})
+	}
+}
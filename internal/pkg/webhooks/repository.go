@@ -0,0 +1,29 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/models"
+)
+
+// Job is a delivery claimed for an attempt with what sending it takes.
+type Job struct {
+	Delivery *models.WebhookDelivery
+	URL      string
+	Secret   string
+}
+
+type Repository interface {
+	InsertWebhook(ctx context.Context, webhook *models.Webhook) error
+	SelectWebhooksByForum(ctx context.Context, forumSlug string) ([]*models.Webhook, error)
+	SelectWebhookById(ctx context.Context, forumSlug string, webhookId uint64) (*models.Webhook, error)
+	DeleteWebhookById(ctx context.Context, forumSlug string, webhookId uint64) error
+
+	InsertDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error
+	SelectDeliveries(ctx context.Context, webhookId uint64, paginator *models.DeliveryPaginator) ([]*models.WebhookDelivery, error)
+	// ClaimDeliveries returns up to limit pending deliveries due by now and
+	// postpones them by lease, so no other worker picks them up meanwhile.
+	ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*Job, error)
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+}
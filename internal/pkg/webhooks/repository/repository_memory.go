@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+	"github.com/forum-api-back/internal/pkg/webhooks"
+	"github.com/forum-api-back/pkg/errors"
+)
+
+type MemoryRepository struct {
+	storage *memory.Storage
+}
+
+func NewSessionMemoryRepository(storage *memory.Storage) webhooks.Repository {
+	return &MemoryRepository{
+		storage: storage,
+	}
+}
+
+func (r *MemoryRepository) InsertWebhook(ctx context.Context, webhook *models.Webhook) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	selectedForum, ok := r.storage.Forums[memory.Key(webhook.Forum)]
+	if !ok {
+		return errors.ErrForumNotFound
+	}
+
+	webhook.Id = r.storage.NextWebhookId()
+	webhook.Forum = selectedForum.Slug
+	webhook.DateCreated = memory.Now()
+
+	insertedWebhook := *webhook
+	insertedWebhook.Events = append(make([]string, 0, len(webhook.Events)), webhook.Events...)
+	r.storage.Webhooks[webhook.Id] = &insertedWebhook
+
+	return nil
+}
+
+func (r *MemoryRepository) SelectWebhooksByForum(ctx context.Context, forumSlug string) ([]*models.Webhook, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	forumKey := memory.Key(forumSlug)
+	selectedWebhooks := make([]*models.Webhook, 0)
+	for _, webhook := range r.storage.Webhooks {
+		if memory.Key(webhook.Forum) == forumKey {
+			selectedWebhooks = append(selectedWebhooks, copyWebhook(webhook))
+		}
+	}
+	sort.Slice(selectedWebhooks, func(i, j int) bool {
+		return selectedWebhooks[i].Id < selectedWebhooks[j].Id
+	})
+
+	return selectedWebhooks, nil
+}
+
+func (r *MemoryRepository) SelectWebhookById(ctx context.Context, forumSlug string,
+	webhookId uint64) (*models.Webhook, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	webhook, ok := r.storage.Webhooks[webhookId]
+	if !ok || memory.Key(webhook.Forum) != memory.Key(forumSlug) {
+		return nil, errors.ErrWebhookNotFound
+	}
+
+	return copyWebhook(webhook), nil
+}
+
+func (r *MemoryRepository) DeleteWebhookById(ctx context.Context, forumSlug string, webhookId uint64) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	webhook, ok := r.storage.Webhooks[webhookId]
+	if !ok || memory.Key(webhook.Forum) != memory.Key(forumSlug) {
+		return errors.ErrWebhookNotFound
+	}
+	r.storage.RemoveWebhook(webhookId)
+
+	return nil
+}
+
+func (r *MemoryRepository) InsertDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	now := memory.Now()
+	for _, delivery := range deliveries {
+		if _, ok := r.storage.Webhooks[delivery.Webhook]; !ok {
+			continue
+		}
+
+		deliveryId := r.storage.NextDeliveryId()
+		r.storage.Deliveries[deliveryId] = &models.WebhookDelivery{
+			Id:          deliveryId,
+			Webhook:     delivery.Webhook,
+			Event:       delivery.Event,
+			Payload:     delivery.Payload,
+			Status:      models.DeliveryPending,
+			NextAttempt: now,
+			DateCreated: now,
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryRepository) SelectDeliveries(ctx context.Context, webhookId uint64,
+	paginator *models.DeliveryPaginator) ([]*models.WebhookDelivery, error) {
+	r.storage.RLock()
+	defer r.storage.RUnlock()
+
+	deliveries := make([]*models.WebhookDelivery, 0)
+	for _, delivery := range r.storage.Deliveries {
+		if delivery.Webhook != webhookId {
+			continue
+		}
+		if paginator.Since != 0 && delivery.Id >= paginator.Since {
+			continue
+		}
+		deliveries = append(deliveries, copyDelivery(delivery))
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].Id > deliveries[j].Id
+	})
+	if uint64(len(deliveries)) > paginator.Limit {
+		deliveries = deliveries[:paginator.Limit]
+	}
+
+	return deliveries, nil
+}
+
+func (r *MemoryRepository) ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration,
+	limit int) ([]*webhooks.Job, error) {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	due := make([]*models.WebhookDelivery, 0)
+	for _, delivery := range r.storage.Deliveries {
+		if delivery.Status == models.DeliveryPending && !delivery.NextAttempt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].NextAttempt.Equal(due[j].NextAttempt) {
+			return due[i].NextAttempt.Before(due[j].NextAttempt)
+		}
+		return due[i].Id < due[j].Id
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	jobs := make([]*webhooks.Job, 0, len(due))
+	for _, delivery := range due {
+		delivery.NextAttempt = now.Add(lease)
+
+		webhook := r.storage.Webhooks[delivery.Webhook]
+		jobs = append(jobs, &webhooks.Job{
+			Delivery: copyDelivery(delivery),
+			URL:      webhook.URL,
+			Secret:   webhook.Secret,
+		})
+	}
+
+	return jobs, nil
+}
+
+func (r *MemoryRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	r.storage.Lock()
+	defer r.storage.Unlock()
+
+	// The webhook may have been deleted while the delivery was attempted.
+	if _, ok := r.storage.Deliveries[delivery.Id]; !ok {
+		return nil
+	}
+	r.storage.Deliveries[delivery.Id] = copyDelivery(delivery)
+
+	return nil
+}
+
+func copyWebhook(webhook *models.Webhook) *models.Webhook {
+	copiedWebhook := *webhook
+	copiedWebhook.Events = append(make([]string, 0, len(webhook.Events)), webhook.Events...)
+	copiedWebhook.Secret = ""
+	return &copiedWebhook
+}
+
+func copyDelivery(delivery *models.WebhookDelivery) *models.WebhookDelivery {
+	copiedDelivery := *delivery
+	if delivery.DateDelivered != nil {
+		dateDelivered := *delivery.DateDelivered
+		copiedDelivery.DateDelivered = &dateDelivered
+	}
+	return &copiedDelivery
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/webhooks"
+	"github.com/forum-api-back/pkg/logger"
+	"github.com/forum-api-back/pkg/metrics"
+)
+
+// MeasuredRepository observes how long every method takes and logs the
+// calls taking slowQuery or longer.
+type MeasuredRepository struct {
+	repo      webhooks.Repository
+	durations *metrics.HistogramVec
+	slowQuery time.Duration
+}
+
+// NewMeasuredRepository observes the durations of the methods of repo into
+// durations, labeled with the repository and the method. Calls taking
+// slowQuery or longer are logged as well, unless it is zero.
+func NewMeasuredRepository(repo webhooks.Repository, durations *metrics.HistogramVec,
+	slowQuery time.Duration) webhooks.Repository {
+	return &MeasuredRepository{
+		repo:      repo,
+		durations: durations,
+		slowQuery: slowQuery,
+	}
+}
+
+func (r *MeasuredRepository) InsertWebhook(ctx context.Context, webhook *models.Webhook) error {
+	defer r.observe(ctx, "InsertWebhook", time.Now())
+	return r.repo.InsertWebhook(ctx, webhook)
+}
+
+func (r *MeasuredRepository) SelectWebhooksByForum(ctx context.Context, forumSlug string) ([]*models.Webhook, error) {
+	defer r.observe(ctx, "SelectWebhooksByForum", time.Now())
+	return r.repo.SelectWebhooksByForum(ctx, forumSlug)
+}
+
+func (r *MeasuredRepository) SelectWebhookById(ctx context.Context, forumSlug string,
+	webhookId uint64) (*models.Webhook, error) {
+	defer r.observe(ctx, "SelectWebhookById", time.Now())
+	return r.repo.SelectWebhookById(ctx, forumSlug, webhookId)
+}
+
+func (r *MeasuredRepository) DeleteWebhookById(ctx context.Context, forumSlug string, webhookId uint64) error {
+	defer r.observe(ctx, "DeleteWebhookById", time.Now())
+	return r.repo.DeleteWebhookById(ctx, forumSlug, webhookId)
+}
+
+func (r *MeasuredRepository) InsertDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	defer r.observe(ctx, "InsertDeliveries", time.Now())
+	return r.repo.InsertDeliveries(ctx, deliveries)
+}
+
+func (r *MeasuredRepository) SelectDeliveries(ctx context.Context, webhookId uint64,
+	paginator *models.DeliveryPaginator) ([]*models.WebhookDelivery, error) {
+	defer r.observe(ctx, "SelectDeliveries", time.Now())
+	return r.repo.SelectDeliveries(ctx, webhookId, paginator)
+}
+
+func (r *MeasuredRepository) ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration,
+	limit int) ([]*webhooks.Job, error) {
+	defer r.observe(ctx, "ClaimDeliveries", time.Now())
+	return r.repo.ClaimDeliveries(ctx, now, lease, limit)
+}
+
+func (r *MeasuredRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	defer r.observe(ctx, "UpdateDelivery", time.Now())
+	return r.repo.UpdateDelivery(ctx, delivery)
+}
+
+func (r *MeasuredRepository) observe(ctx context.Context, method string, start time.Time) {
+	elapsed := time.Since(start)
+	r.durations.With("webhooks", method).Observe(elapsed.Seconds())
+	if r.slowQuery > 0 && elapsed >= r.slowQuery {
+		logger.FromContext(ctx).Warn("slow query", "repository", "webhooks", "method", method, "duration", elapsed)
+	}
+}
@@ -0,0 +1,312 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/webhooks"
+	"github.com/forum-api-back/pkg/errors"
+	"github.com/forum-api-back/pkg/tools/sql_utils"
+
+	"github.com/lib/pq"
+)
+
+type PostgresqlRepository struct {
+	db *sql.DB
+}
+
+func NewSessionPostgresqlRepository(db *sql.DB) webhooks.Repository {
+	return &PostgresqlRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresqlRepository) InsertWebhook(ctx context.Context, webhook *models.Webhook) error {
+	row := r.db.QueryRowContext(ctx,
+		"INSERT INTO webhooks(forum_slug, url, events, secret) "+
+			"VALUES ($1, $2, $3, $4) "+
+			"RETURNING id, date_created",
+		webhook.Forum,
+		webhook.URL,
+		pq.Array(webhook.Events),
+		webhook.Secret,
+	)
+
+	err := row.Scan(&webhook.Id, &webhook.DateCreated)
+	if sql_utils.Constraint(err) == "webhooks_forum_slug_fkey" {
+		return errors.ErrForumNotFound.Wrap(err)
+	}
+
+	return sql_utils.Error(err, errors.ErrWebhookNotFound)
+}
+
+func (r *PostgresqlRepository) SelectWebhooksByForum(ctx context.Context, forumSlug string) ([]*models.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT id, forum_slug, url, events, date_created "+
+			"FROM webhooks "+
+			"WHERE forum_slug = $1 "+
+			"ORDER BY id",
+		forumSlug,
+	)
+	if err != nil {
+		return nil, sql_utils.Error(err, errors.ErrWebhookNotFound)
+	}
+	defer rows.Close()
+
+	selectedWebhooks := make([]*models.Webhook, 0)
+	for rows.Next() {
+		webhook := &models.Webhook{}
+		err = rows.Scan(
+			&webhook.Id,
+			&webhook.Forum,
+			&webhook.URL,
+			pq.Array(&webhook.Events),
+			&webhook.DateCreated,
+		)
+		if err != nil {
+			return nil, sql_utils.Error(err, errors.ErrWebhookNotFound)
+		}
+
+		selectedWebhooks = append(selectedWebhooks, webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, sql_utils.Error(err, errors.ErrWebhookNotFound)
+	}
+
+	return selectedWebhooks, nil
+}
+
+func (r *PostgresqlRepository) SelectWebhookById(ctx context.Context, forumSlug string,
+	webhookId uint64) (*models.Webhook, error) {
+	row := r.db.QueryRowContext(ctx,
+		"SELECT id, forum_slug, url, events, date_created "+
+			"FROM webhooks "+
+			"WHERE id = $1 AND forum_slug = $2",
+		webhookId,
+		forumSlug,
+	)
+
+	webhook := &models.Webhook{}
+	err := row.Scan(
+		&webhook.Id,
+		&webhook.Forum,
+		&webhook.URL,
+		pq.Array(&webhook.Events),
+		&webhook.DateCreated,
+	)
+	if err != nil {
+		return nil, sql_utils.Error(err, errors.ErrWebhookNotFound)
+	}
+
+	return webhook, nil
+}
+
+func (r *PostgresqlRepository) DeleteWebhookById(ctx context.Context, forumSlug string, webhookId uint64) error {
+	result, err := r.db.ExecContext(ctx,
+		"DELETE FROM webhooks "+
+			"WHERE id = $1 AND forum_slug = $2",
+		webhookId,
+		forumSlug,
+	)
+	if err != nil {
+		return sql_utils.Error(err, errors.ErrWebhookNotFound)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return sql_utils.Error(err, errors.ErrWebhookNotFound)
+	}
+	if deleted == 0 {
+		return errors.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresqlRepository) InsertDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	webhookIds := make([]int64, len(deliveries))
+	eventNames := make([]string, len(deliveries))
+	payloads := make([]string, len(deliveries))
+	for i, delivery := range deliveries {
+		webhookIds[i] = int64(delivery.Webhook)
+		eventNames[i] = delivery.Event
+		payloads[i] = string(delivery.Payload)
+	}
+
+	// Webhooks deleted since they were selected are skipped by the join.
+	_, err := r.db.ExecContext(ctx,
+		"INSERT INTO webhook_deliveries (webhook_id, event, payload) "+
+			"SELECT new_deliveries.webhook_id, new_deliveries.event, new_deliveries.payload "+
+			"FROM unnest($1::INTEGER[], $2::TEXT[], $3::TEXT[]) "+
+			"	AS new_deliveries(webhook_id, event, payload) "+
+			"JOIN webhooks ON (webhooks.id = new_deliveries.webhook_id)",
+		pq.Array(webhookIds),
+		pq.Array(eventNames),
+		pq.Array(payloads),
+	)
+
+	return sql_utils.Error(err, errors.ErrWebhookNotFound)
+}
+
+func (r *PostgresqlRepository) SelectDeliveries(ctx context.Context, webhookId uint64,
+	paginator *models.DeliveryPaginator) ([]*models.WebhookDelivery, error) {
+	var rows *sql.Rows
+	var err error
+	if paginator.Since != 0 {
+		rows, err = r.db.QueryContext(ctx,
+			"SELECT "+deliveryColumns+
+				"FROM webhook_deliveries "+
+				"WHERE webhook_id = $1 AND id < $2 "+
+				"ORDER BY id DESC "+
+				"LIMIT $3",
+			webhookId,
+			paginator.Since,
+			paginator.Limit,
+		)
+	} else {
+		rows, err = r.db.QueryContext(ctx,
+			"SELECT "+deliveryColumns+
+				"FROM webhook_deliveries "+
+				"WHERE webhook_id = $1 "+
+				"ORDER BY id DESC "+
+				"LIMIT $2",
+			webhookId,
+			paginator.Limit,
+		)
+	}
+	if err != nil {
+		return nil, sql_utils.Error(err, errors.ErrWebhookNotFound)
+	}
+	defer rows.Close()
+
+	deliveries := make([]*models.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, sql_utils.Error(err, errors.ErrWebhookNotFound)
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, sql_utils.Error(err, errors.ErrWebhookNotFound)
+	}
+
+	return deliveries, nil
+}
+
+func (r *PostgresqlRepository) ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration,
+	limit int) ([]*webhooks.Job, error) {
+	// SKIP LOCKED lets the instances sharing the database claim different
+	// deliveries instead of waiting for each other.
+	rows, err := r.db.QueryContext(ctx,
+		"WITH claimed AS ( "+
+			"	UPDATE webhook_deliveries SET "+
+			"	next_attempt = $2 "+
+			"	WHERE id IN ( "+
+			"		SELECT id "+
+			"		FROM webhook_deliveries "+
+			"		WHERE status = 'pending' AND next_attempt <= $1 "+
+			"		ORDER BY next_attempt "+
+			"		LIMIT $3 "+
+			"		FOR UPDATE SKIP LOCKED "+
+			"	) "+
+			"	RETURNING "+deliveryColumns+
+			") "+
+			"SELECT claimed.*, webhooks.url, webhooks.secret "+
+			"FROM claimed "+
+			"JOIN webhooks ON (webhooks.id = claimed.webhook_id) "+
+			"ORDER BY claimed.id",
+		now,
+		now.Add(lease),
+		limit,
+	)
+	if err != nil {
+		return nil, sql_utils.Error(err, errors.ErrWebhookNotFound)
+	}
+	defer rows.Close()
+
+	jobs := make([]*webhooks.Job, 0)
+	for rows.Next() {
+		job := &webhooks.Job{Delivery: &models.WebhookDelivery{}}
+		if err := scanDeliveryInto(rows, job.Delivery, &job.URL, &job.Secret); err != nil {
+			return nil, sql_utils.Error(err, errors.ErrWebhookNotFound)
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, sql_utils.Error(err, errors.ErrWebhookNotFound)
+	}
+
+	return jobs, nil
+}
+
+func (r *PostgresqlRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE webhook_deliveries SET "+
+			"status = $2, "+
+			"attempts = $3, "+
+			"response_status = $4, "+
+			"last_error = $5, "+
+			"next_attempt = $6, "+
+			"date_delivered = $7 "+
+			"WHERE id = $1",
+		delivery.Id,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.ResponseStatus,
+		delivery.LastError,
+		delivery.NextAttempt,
+		delivery.DateDelivered,
+	)
+
+	return sql_utils.Error(err, errors.ErrWebhookNotFound)
+}
+
+const deliveryColumns = "id, webhook_id, event, payload, status, attempts, response_status, " +
+	"last_error, next_attempt, date_created, date_delivered "
+
+func scanDelivery(rows *sql.Rows) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{}
+	return delivery, scanDeliveryInto(rows, delivery)
+}
+
+// scanDeliveryInto reads the deliveryColumns of a row followed by extra
+// columns.
+func scanDeliveryInto(rows *sql.Rows, delivery *models.WebhookDelivery, extra ...interface{}) error {
+	var payload string
+	dateDelivered := sql.NullTime{}
+	err := rows.Scan(append([]interface{}{
+		&delivery.Id,
+		&delivery.Webhook,
+		&delivery.Event,
+		&payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.ResponseStatus,
+		&delivery.LastError,
+		&delivery.NextAttempt,
+		&delivery.DateCreated,
+		&dateDelivered,
+	}, extra...)...)
+	if err != nil {
+		return err
+	}
+
+	delivery.Payload = []byte(payload)
+	if dateDelivered.Valid {
+		delivery.DateDelivered = &dateDelivered.Time
+	}
+
+	return nil
+}
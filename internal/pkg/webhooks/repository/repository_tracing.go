@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/webhooks"
+	"github.com/forum-api-back/pkg/tracing"
+)
+
+// TracedRepository records a span for every method called within a traced request.
+type TracedRepository struct {
+	repo webhooks.Repository
+}
+
+func NewTracedRepository(repo webhooks.Repository) webhooks.Repository {
+	return &TracedRepository{
+		repo: repo,
+	}
+}
+
+func (r *TracedRepository) InsertWebhook(ctx context.Context, webhook *models.Webhook) (err error) {
+	ctx, span := tracing.Start(ctx, "webhooks.repository.InsertWebhook")
+	defer func() { span.End(err) }()
+	return r.repo.InsertWebhook(ctx, webhook)
+}
+
+func (r *TracedRepository) SelectWebhooksByForum(ctx context.Context,
+	forumSlug string) (_ []*models.Webhook, err error) {
+	ctx, span := tracing.Start(ctx, "webhooks.repository.SelectWebhooksByForum")
+	defer func() { span.End(err) }()
+	return r.repo.SelectWebhooksByForum(ctx, forumSlug)
+}
+
+func (r *TracedRepository) SelectWebhookById(ctx context.Context, forumSlug string,
+	webhookId uint64) (_ *models.Webhook, err error) {
+	ctx, span := tracing.Start(ctx, "webhooks.repository.SelectWebhookById")
+	defer func() { span.End(err) }()
+	return r.repo.SelectWebhookById(ctx, forumSlug, webhookId)
+}
+
+func (r *TracedRepository) DeleteWebhookById(ctx context.Context, forumSlug string,
+	webhookId uint64) (err error) {
+	ctx, span := tracing.Start(ctx, "webhooks.repository.DeleteWebhookById")
+	defer func() { span.End(err) }()
+	return r.repo.DeleteWebhookById(ctx, forumSlug, webhookId)
+}
+
+func (r *TracedRepository) InsertDeliveries(ctx context.Context,
+	deliveries []*models.WebhookDelivery) (err error) {
+	ctx, span := tracing.Start(ctx, "webhooks.repository.InsertDeliveries")
+	defer func() { span.End(err) }()
+	return r.repo.InsertDeliveries(ctx, deliveries)
+}
+
+func (r *TracedRepository) SelectDeliveries(ctx context.Context, webhookId uint64,
+	paginator *models.DeliveryPaginator) (_ []*models.WebhookDelivery, err error) {
+	ctx, span := tracing.Start(ctx, "webhooks.repository.SelectDeliveries")
+	defer func() { span.End(err) }()
+	return r.repo.SelectDeliveries(ctx, webhookId, paginator)
+}
+
+func (r *TracedRepository) ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration,
+	limit int) (_ []*webhooks.Job, err error) {
+	ctx, span := tracing.Start(ctx, "webhooks.repository.ClaimDeliveries")
+	defer func() { span.End(err) }()
+	return r.repo.ClaimDeliveries(ctx, now, lease, limit)
+}
+
+func (r *TracedRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) (err error) {
+	ctx, span := tracing.Start(ctx, "webhooks.repository.UpdateDelivery")
+	defer func() { span.End(err) }()
+	return r.repo.UpdateDelivery(ctx, delivery)
+}
@@ -0,0 +1,111 @@
+package sender
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// blockedNetworks are the special-purpose ranges, on top of the loopback,
+// link-local, multicast and unspecified ones, that webhooks must not reach:
+// private networks, carrier-grade NAT and the benchmarking range.
+var blockedNetworks = parseNetworks(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"240.0.0.0/4",
+	"fc00::/7",
+)
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
+
+// IsPublic reports whether ip may be reached by webhooks: it is none of the
+// loopback, private, link-local (such as the 169.254.169.254 metadata
+// service) or other special-purpose addresses of the hosting network.
+func IsPublic(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckHost rejects the hosts of webhook URLs known not to be public
+// without resolving them: localhost and addresses IsPublic refuses.
+// Other names are checked once they are resolved, when they are dialed.
+func CheckHost(host string) error {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("host %s is not public", host)
+	}
+	if ip := net.ParseIP(host); ip != nil && !IsPublic(ip) {
+		return fmt.Errorf("address %s is not public", ip)
+	}
+	return nil
+}
+
+// NewClient returns the client deliveries are posted with. Unless
+// allowPrivate is set it only connects to public addresses, whatever the
+// names of webhooks resolve to, and it never follows redirects, which
+// would send payloads where their owners didn't register them: a redirect
+// is a failed attempt.
+func NewClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+	}
+	if !allowPrivate {
+		dialer.Control = dialPublic
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		// No proxy: its address would be checked in place of the ones of
+		// webhooks.
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			ForceAttemptHTTP2:   true,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// dialPublic is called once the address to connect to is resolved, so
+// names pointing at private addresses are refused as well.
+func dialPublic(network, address string, conn syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !IsPublic(ip) {
+		return fmt.Errorf("webhooks: address %s is not public", host)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package sender
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"127.10.0.1", false},
+		{"::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"172.31.255.255", false},
+		{"172.32.0.1", true},
+		{"192.168.1.1", false},
+		{"100.64.0.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:169.254.169.254", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsPublic(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("IsPublic(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	tests := []struct {
+		host string
+		ok   bool
+	}{
+		{"example.com", true},
+		{"93.184.216.34", true},
+		{"localhost", false},
+		{"LOCALHOST.", false},
+		{"api.localhost", false},
+		{"127.0.0.1", false},
+		{"169.254.169.254", false},
+		{"::1", false},
+		{"10.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		if err := CheckHost(tt.host); (err == nil) != tt.ok {
+			t.Errorf("CheckHost(%q) error = %v, want ok %v", tt.host, err, tt.ok)
+		}
+	}
+}
+
+func TestNewClientRefusesPrivateAddresses(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer server.Close()
+
+	// The name resolves to the loopback address of the server only when
+	// it is dialed.
+	url := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	for _, target := range []string{server.URL, url} {
+		response, err := NewClient(time.Second, false).Post(target, "application/json", nil)
+		if err == nil {
+			response.Body.Close()
+			t.Errorf("POST %s succeeded, want the address refused", target)
+		} else if !strings.Contains(err.Error(), "not public") {
+			t.Errorf("POST %s error = %v, want the address refused", target, err)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("server got %d requests, want none", requests)
+	}
+
+	response, err := NewClient(time.Second, true).Post(server.URL, "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST with private addresses allowed error = %v", err)
+	}
+	response.Body.Close()
+	if requests != 1 {
+		t.Errorf("server got %d requests, want 1", requests)
+	}
+}
+
+func TestNewClientDoesNotFollowRedirects(t *testing.T) {
+	var requests int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer target.Close()
+	redirecting := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	defer redirecting.Close()
+
+	response, err := NewClient(time.Second, true).Post(redirecting.URL, "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusTemporaryRedirect {
+		t.Errorf("status = %d, want %d", response.StatusCode, http.StatusTemporaryRedirect)
+	}
+	if requests != 0 {
+		t.Errorf("redirect target got %d requests, want none", requests)
+	}
+}
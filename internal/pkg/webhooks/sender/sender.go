@@ -0,0 +1,184 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/webhooks"
+	"github.com/forum-api-back/pkg/logger"
+)
+
+// batchSize is how many deliveries are claimed, and attempted at once, per
+// poll.
+const batchSize = 16
+
+// maxErrorLength keeps the errors saved with deliveries short.
+const maxErrorLength = 256
+
+type Options struct {
+	MaxAttempts  int
+	Backoff      time.Duration
+	MaxBackoff   time.Duration
+	PollInterval time.Duration
+}
+
+// Sender posts the pending deliveries to their webhooks and retries
+// the failed ones with an exponential backoff.
+type Sender struct {
+	repo    webhooks.Repository
+	client  *http.Client
+	options Options
+}
+
+// NewSender makes a sender whose attempts are bounded by the
+// timeout of the client.
+func NewSender(repo webhooks.Repository, client *http.Client, options Options) *Sender {
+	return &Sender{
+		repo:    repo,
+		client:  client,
+		options: options,
+	}
+}
+
+// Run delivers until ctx is canceled. Deliveries claimed but not finished
+// then are attempted again once their lease is over.
+func (s *Sender) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// A full batch suggests more deliveries are due, so the next one
+		// is claimed without waiting.
+		for s.poll(ctx) == batchSize {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Sender) poll(ctx context.Context) int {
+	if ctx.Err() != nil {
+		return 0
+	}
+
+	// The lease outlasts an attempt, so a delivery is not claimed again
+	// while it is in flight.
+	lease := 2*s.client.Timeout + s.options.PollInterval
+	jobs, err := s.repo.ClaimDeliveries(ctx, time.Now(), lease, batchSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Default().Error("webhooks: can't claim deliveries", "error", err)
+		}
+		return 0
+	}
+
+	wg := sync.WaitGroup{}
+	for _, job := range jobs {
+		wg.Add(1)
+		go func(job *webhooks.Job) {
+			defer wg.Done()
+			s.deliver(ctx, job)
+		}(job)
+	}
+	wg.Wait()
+
+	return len(jobs)
+}
+
+func (s *Sender) deliver(ctx context.Context, job *webhooks.Job) {
+	delivery := job.Delivery
+	statusCode, err := s.send(ctx, job)
+	if ctx.Err() != nil {
+		// Shutting down: the delivery is retried when its lease is over.
+		return
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.ResponseStatus = statusCode
+	switch {
+	case err == nil:
+		delivery.Status = models.DeliveryDelivered
+		delivery.LastError = ""
+		delivery.DateDelivered = &now
+	case delivery.Attempts >= s.options.MaxAttempts:
+		delivery.Status = models.DeliveryFailed
+		delivery.LastError = truncate(err.Error())
+	default:
+		delivery.LastError = truncate(err.Error())
+		delivery.NextAttempt = now.Add(s.backoff(delivery.Attempts))
+	}
+
+	if err := s.repo.UpdateDelivery(context.Background(), delivery); err != nil {
+		logger.Default().Error("webhooks: can't save delivery", "delivery", delivery.Id, "error", err)
+	}
+}
+
+// send posts the payload and returns the response status, with an error
+// unless it is a 2xx one.
+func (s *Sender) send(ctx context.Context, job *webhooks.Job) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, job.URL, bytes.NewReader(job.Delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "forum-api-webhooks")
+	request.Header.Set("X-Forum-Event", job.Delivery.Event)
+	request.Header.Set("X-Forum-Delivery", strconv.FormatUint(job.Delivery.Id, 10))
+	request.Header.Set("X-Forum-Signature", Sign(job.Secret, job.Delivery.Payload))
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	// Reading the body lets the connection be reused.
+	io.Copy(ioutil.Discard, io.LimitReader(response.Body, 64<<10))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("unexpected response status %s", response.Status)
+	}
+	return response.StatusCode, nil
+}
+
+// backoff returns the delay after the given number of failed attempts.
+func (s *Sender) backoff(attempts int) time.Duration {
+	delay := s.options.Backoff
+	for i := 1; i < attempts && delay < s.options.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > s.options.MaxBackoff {
+		delay = s.options.MaxBackoff
+	}
+	return delay
+}
+
+// Sign returns the X-Forum-Signature header of a payload: the hex HMAC-SHA256
+// of the body keyed with the secret of the webhook.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func truncate(message string) string {
+	if len(message) > maxErrorLength {
+		return message[:maxErrorLength]
+	}
+	return message
+}
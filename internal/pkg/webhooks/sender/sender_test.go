@@ -0,0 +1,213 @@
+package sender
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/storage/memory"
+	"github.com/forum-api-back/internal/pkg/webhooks"
+	webhooks_repo "github.com/forum-api-back/internal/pkg/webhooks/repository"
+)
+
+const testSecret = "webhook secret"
+
+var testPayload = []byte(`{"event":"thread_created","forum":"f","data":{}}`)
+
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver answers the deliveries with the given statuses in turn and
+// then with 200.
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*received
+}
+
+func (h *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.requests = append(h.requests, &received{header: r.Header, body: body})
+	status := http.StatusOK
+	if len(h.statuses) != 0 {
+		status, h.statuses = h.statuses[0], h.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+// newTestSender returns a sender with a delivery of testPayload due to the
+// webhook at url, and the repository keeping it.
+func newTestSender(t *testing.T, url string, options Options) (*Sender, webhooks.Repository) {
+	t.Helper()
+	ctx := context.Background()
+
+	storage := memory.NewStorage()
+	storage.Forums["f"] = &models.Forum{Title: "Forum", AuthorNickName: "alice", Slug: "f"}
+	repo := webhooks_repo.NewSessionMemoryRepository(storage)
+
+	webhook := &models.Webhook{Forum: "f", URL: url, Secret: testSecret}
+	if err := repo.InsertWebhook(ctx, webhook); err != nil {
+		t.Fatal(err)
+	}
+	err := repo.InsertDeliveries(ctx, []*models.WebhookDelivery{{Webhook: webhook.Id, Event: "thread_created", Payload: testPayload}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewSender(repo, NewClient(time.Second, true), options), repo
+}
+
+func selectDelivery(t *testing.T, repo webhooks.Repository) *models.WebhookDelivery {
+	t.Helper()
+
+	deliveries, err := repo.SelectDeliveries(context.Background(), 1, &models.DeliveryPaginator{Limit: 1})
+	if err != nil || len(deliveries) != 1 {
+		t.Fatalf("SelectDeliveries() = %v, %v, want one delivery", deliveries, err)
+	}
+	return deliveries[0]
+}
+
+func TestSenderSignsDeliveries(t *testing.T) {
+	h := &receiver{}
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	s, repo := newTestSender(t, server.URL, Options{MaxAttempts: 3, Backoff: time.Minute, MaxBackoff: time.Hour, PollInterval: time.Second})
+	if claimed := s.poll(context.Background()); claimed != 1 {
+		t.Fatalf("poll() claimed %d deliveries, want 1", claimed)
+	}
+
+	if len(h.requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(h.requests))
+	}
+	request := h.requests[0]
+	if string(request.body) != string(testPayload) {
+		t.Errorf("body = %s, want %s", request.body, testPayload)
+	}
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write(request.body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); request.header.Get("X-Forum-Signature") != want {
+		t.Errorf("X-Forum-Signature = %q, want %q", request.header.Get("X-Forum-Signature"), want)
+	}
+	if got := request.header.Get("X-Forum-Event"); got != "thread_created" {
+		t.Errorf("X-Forum-Event = %q, want thread_created", got)
+	}
+	delivery := selectDelivery(t, repo)
+	if got := request.header.Get("X-Forum-Delivery"); got != strconv.FormatUint(delivery.Id, 10) {
+		t.Errorf("X-Forum-Delivery = %q, want %d", got, delivery.Id)
+	}
+
+	if delivery.Status != models.DeliveryDelivered || delivery.Attempts != 1 ||
+		delivery.ResponseStatus != http.StatusOK || delivery.DateDelivered == nil {
+		t.Errorf("delivery = %+v, want delivered at the first attempt", delivery)
+	}
+}
+
+func TestSenderRetries(t *testing.T) {
+	tests := []struct {
+		name        string
+		statuses    []int
+		maxAttempts int
+		status      string
+		attempts    int
+		response    int
+	}{
+		{"delivered after failures", []int{500, 503}, 5, models.DeliveryDelivered, 3, http.StatusOK},
+		{"failed after max attempts", []int{500, 502, 503}, 3, models.DeliveryFailed, 3, http.StatusServiceUnavailable},
+		{"redirects are failures", []int{307, 307}, 2, models.DeliveryFailed, 2, http.StatusTemporaryRedirect},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &receiver{statuses: tt.statuses}
+			server := httptest.NewServer(h)
+			defer server.Close()
+
+			options := Options{MaxAttempts: tt.maxAttempts, Backoff: 20 * time.Millisecond, MaxBackoff: 30 * time.Millisecond, PollInterval: time.Second}
+			s, repo := newTestSender(t, server.URL, options)
+			ctx := context.Background()
+
+			for attempt := 1; attempt <= tt.attempts; attempt++ {
+				before := time.Now()
+				if claimed := s.poll(ctx); claimed != 1 {
+					t.Fatalf("attempt %d claimed %d deliveries, want 1", attempt, claimed)
+				}
+
+				delivery := selectDelivery(t, repo)
+				if delivery.Attempts != attempt {
+					t.Fatalf("attempts = %d, want %d", delivery.Attempts, attempt)
+				}
+				if attempt == tt.attempts {
+					break
+				}
+
+				if delivery.Status != models.DeliveryPending || delivery.LastError == "" {
+					t.Fatalf("delivery after attempt %d = %+v, want pending with an error", attempt, delivery)
+				}
+				backoff := s.backoff(attempt)
+				if delivery.NextAttempt.Before(before.Add(backoff).Truncate(time.Millisecond)) {
+					t.Fatalf("next attempt %v is sooner than the backoff %v", delivery.NextAttempt, backoff)
+				}
+				if claimed := s.poll(ctx); claimed != 0 {
+					t.Fatalf("poll() before the backoff claimed %d deliveries", claimed)
+				}
+				time.Sleep(time.Until(delivery.NextAttempt))
+			}
+
+			delivery := selectDelivery(t, repo)
+			if delivery.Status != tt.status || delivery.ResponseStatus != tt.response {
+				t.Errorf("delivery = %+v, want %s with response %d", delivery, tt.status, tt.response)
+			}
+			if len(h.requests) != tt.attempts {
+				t.Errorf("got %d requests, want %d", len(h.requests), tt.attempts)
+			}
+			if claimed := s.poll(ctx); claimed != 0 {
+				t.Errorf("poll() claimed %d finished deliveries", claimed)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	s := NewSender(nil, &http.Client{}, Options{Backoff: 10 * time.Second, MaxBackoff: time.Minute})
+
+	want := []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute}
+	for i, delay := range want {
+		if got := s.backoff(i + 1); got != delay {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, delay)
+		}
+	}
+}
+
+func TestSenderRefusesPrivateAddresses(t *testing.T) {
+	h := &receiver{}
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	s, repo := newTestSender(t, server.URL, Options{MaxAttempts: 3, Backoff: time.Minute, MaxBackoff: time.Hour, PollInterval: time.Second})
+	s.client = NewClient(time.Second, false)
+	s.poll(context.Background())
+
+	delivery := selectDelivery(t, repo)
+	if delivery.Status != models.DeliveryPending || delivery.Attempts != 1 || !strings.Contains(delivery.LastError, "not public") {
+		t.Errorf("delivery = %+v, want a pending one refused for its address", delivery)
+	}
+	if len(h.requests) != 0 {
+		t.Errorf("got %d requests, want none", len(h.requests))
+	}
+}
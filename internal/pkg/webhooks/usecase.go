@@ -0,0 +1,20 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/events"
+	"github.com/forum-api-back/internal/pkg/models"
+)
+
+type UseCase interface {
+	CreateWebhook(ctx context.Context, forumSlug string, webhookInfo *models.WebhookCreate) (*models.Webhook, error)
+	GetWebhooks(ctx context.Context, forumSlug string) ([]*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, forumSlug string, webhookId uint64) error
+	GetDeliveries(ctx context.Context, forumSlug string, webhookId uint64,
+		paginator *models.DeliveryPaginator) ([]*models.WebhookDelivery, error)
+
+	// Enqueue queues a delivery of the event for every webhook of its
+	// forum that wants it.
+	Enqueue(ctx context.Context, event events.Event) error
+}
@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/forum-api-back/internal/pkg/events"
+	"github.com/forum-api-back/internal/pkg/forum"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/roles"
+	"github.com/forum-api-back/internal/pkg/webhooks"
+	"github.com/forum-api-back/pkg/errors"
+	"github.com/forum-api-back/pkg/tools/auth_utils"
+)
+
+type WebhooksUseCase struct {
+	WebhooksRepo webhooks.Repository
+	ForumRepo    forum.Repository
+	RolesUCase   roles.UseCase
+}
+
+func NewUseCase(webhooksRepo webhooks.Repository, forumRepo forum.Repository,
+	rolesUCase roles.UseCase) webhooks.UseCase {
+	return &WebhooksUseCase{
+		WebhooksRepo: webhooksRepo,
+		ForumRepo:    forumRepo,
+		RolesUCase:   rolesUCase,
+	}
+}
+
+// payload is the body posted to webhooks.
+type payload struct {
+	Event   string       `json:"event"`
+	Forum   string       `json:"forum"`
+	Created time.Time    `json:"created"`
+	Data    events.Event `json:"data"`
+}
+
+func (u *WebhooksUseCase) CreateWebhook(ctx context.Context, forumSlug string,
+	webhookInfo *models.WebhookCreate) (*models.Webhook, error) {
+	if err := u.RolesUCase.Authorize(ctx, forumSlug, models.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	selectedForum, err := u.ForumRepo.SelectForumBySlug(ctx, forumSlug)
+	if err != nil {
+		return nil, errors.Translate(err, forumNotFound(forumSlug))
+	}
+
+	secret := webhookInfo.Secret
+	if secret == "" {
+		randomSecret, err := auth_utils.RandomSecret()
+		if err != nil {
+			return nil, errors.ErrInternalError.Wrap(err)
+		}
+		secret = hex.EncodeToString(randomSecret)
+	}
+
+	newWebhook := &models.Webhook{
+		Forum:  selectedForum.Slug,
+		URL:    webhookInfo.URL,
+		Events: append(make([]string, 0, len(webhookInfo.Events)), webhookInfo.Events...),
+		Secret: secret,
+	}
+	if err := u.WebhooksRepo.InsertWebhook(ctx, newWebhook); err != nil {
+		return nil, errors.Translate(err, forumNotFound(forumSlug))
+	}
+
+	return newWebhook, nil
+}
+
+func (u *WebhooksUseCase) GetWebhooks(ctx context.Context, forumSlug string) ([]*models.Webhook, error) {
+	if err := u.RolesUCase.Authorize(ctx, forumSlug, models.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	return u.WebhooksRepo.SelectWebhooksByForum(ctx, forumSlug)
+}
+
+func (u *WebhooksUseCase) DeleteWebhook(ctx context.Context, forumSlug string, webhookId uint64) error {
+	if err := u.RolesUCase.Authorize(ctx, forumSlug, models.RoleOwner); err != nil {
+		return err
+	}
+
+	err := u.WebhooksRepo.DeleteWebhookById(ctx, forumSlug, webhookId)
+	return errors.Translate(err, webhookNotFound(forumSlug, webhookId))
+}
+
+func (u *WebhooksUseCase) GetDeliveries(ctx context.Context, forumSlug string, webhookId uint64,
+	paginator *models.DeliveryPaginator) ([]*models.WebhookDelivery, error) {
+	if err := u.RolesUCase.Authorize(ctx, forumSlug, models.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.WebhooksRepo.SelectWebhookById(ctx, forumSlug, webhookId); err != nil {
+		return nil, errors.Translate(err, webhookNotFound(forumSlug, webhookId))
+	}
+
+	return u.WebhooksRepo.SelectDeliveries(ctx, webhookId, paginator)
+}
+
+func (u *WebhooksUseCase) Enqueue(ctx context.Context, event events.Event) error {
+	forumSlug := eventForum(event)
+	if forumSlug == "" {
+		return nil
+	}
+
+	forumWebhooks, err := u.WebhooksRepo.SelectWebhooksByForum(ctx, forumSlug)
+	if err != nil || len(forumWebhooks) == 0 {
+		return err
+	}
+
+	body, err := json.Marshal(&payload{
+		Event:   event.Name(),
+		Forum:   forumSlug,
+		Created: time.Now(),
+		Data:    event,
+	})
+	if err != nil {
+		return err
+	}
+
+	deliveries := make([]*models.WebhookDelivery, 0, len(forumWebhooks))
+	for _, webhook := range forumWebhooks {
+		if !wants(webhook, event.Name()) {
+			continue
+		}
+		deliveries = append(deliveries, &models.WebhookDelivery{
+			Webhook: webhook.Id,
+			Event:   event.Name(),
+			Payload: body,
+		})
+	}
+
+	return u.WebhooksRepo.InsertDeliveries(ctx, deliveries)
+}
+
+// eventForum returns the forum whose webhooks get the event, or nothing
+// for the events webhooks are not sent.
+func eventForum(event events.Event) string {
+	switch event := event.(type) {
+	case *events.ThreadCreated:
+		return event.Thread.Forum
+	case *events.ThreadEdited:
+		return event.Thread.Forum
+	case *events.PostsCreated:
+		return event.Thread.Forum
+	case *events.PostEdited:
+		return event.Post.Forum
+	case *events.VoteCast:
+		return event.Thread.Forum
+	default:
+		return ""
+	}
+}
+
+func wants(webhook *models.Webhook, eventName string) bool {
+	if len(webhook.Events) == 0 {
+		return true
+	}
+	for _, name := range webhook.Events {
+		if name == eventName {
+			return true
+		}
+	}
+	return false
+}
+
+func forumNotFound(forumSlug string) *errors.Error {
+	return errors.ErrForumNotFound.WithMessage("Can't find forum by slug: %s", forumSlug)
+}
+
+func webhookNotFound(forumSlug string, webhookId uint64) *errors.Error {
+	return errors.ErrWebhookNotFound.WithMessage("Can't find webhook %d of forum: %s", webhookId, forumSlug)
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/forum-api-back/internal/pkg/events"
+	"github.com/forum-api-back/internal/pkg/models"
+	"github.com/forum-api-back/internal/pkg/webhooks"
+	"github.com/forum-api-back/pkg/tracing"
+)
+
+// TracedUseCase records a span for every method called within a traced request.
+type TracedUseCase struct {
+	ucase webhooks.UseCase
+}
+
+func NewTracedUseCase(ucase webhooks.UseCase) webhooks.UseCase {
+	return &TracedUseCase{
+		ucase: ucase,
+	}
+}
+
+func (u *TracedUseCase) CreateWebhook(ctx context.Context, forumSlug string,
+	webhookInfo *models.WebhookCreate) (_ *models.Webhook, err error) {
+	ctx, span := tracing.Start(ctx, "webhooks.usecase.CreateWebhook")
+	defer func() { span.End(err) }()
+	return u.ucase.CreateWebhook(ctx, forumSlug, webhookInfo)
+}
+
+func (u *TracedUseCase) GetWebhooks(ctx context.Context, forumSlug string) (_ []*models.Webhook, err error) {
+	ctx, span := tracing.Start(ctx, "webhooks.usecase.GetWebhooks")
+	defer func() { span.End(err) }()
+	return u.ucase.GetWebhooks(ctx, forumSlug)
+}
+
+func (u *TracedUseCase) DeleteWebhook(ctx context.Context, forumSlug string, webhookId uint64) (err error) {
+	ctx, span := tracing.Start(ctx, "webhooks.usecase.DeleteWebhook")
+	defer func() { span.End(err) }()
+	return u.ucase.DeleteWebhook(ctx, forumSlug, webhookId)
+}
+
+func (u *TracedUseCase) GetDeliveries(ctx context.Context, forumSlug string, webhookId uint64,
+	paginator *models.DeliveryPaginator) (_ []*models.WebhookDelivery, err error) {
+	ctx, span := tracing.Start(ctx, "webhooks.usecase.GetDeliveries")
+	defer func() { span.End(err) }()
+	return u.ucase.GetDeliveries(ctx, forumSlug, webhookId, paginator)
+}
+
+func (u *TracedUseCase) Enqueue(ctx context.Context, event events.Event) (err error) {
+	ctx, span := tracing.Start(ctx, "webhooks.usecase.Enqueue")
+	defer func() { span.End(err) }()
+	return u.ucase.Enqueue(ctx, event)
+}
@@ -1,57 +1,195 @@
 package errors
 
 import (
+	"context"
+	stderrors "errors"
 	"fmt"
 )
 
+// Code classifies an error; the HTTP layer picks the response status by it.
+type Code string
+
+const (
+	CodeBadRequest   Code = "bad_request"
+	CodeUnauthorized Code = "unauthorized"
+	CodeForbidden    Code = "forbidden"
+	CodeNotFound     Code = "not_found"
+	CodeConflict     Code = "conflict"
+	CodeTimeout      Code = "timeout"
+	CodeInternal     Code = "internal"
+)
+
+// Error is a domain error. The sentinels below are matched with Is, which
+// also matches the copies made by Wrap, WithMessage and WithDetails. The
+// cause is kept for errors.Is/As and never reaches the client.
 type Error struct {
+	Code    Code          `json:"code"`
+	Message string        `json:"message"`
+	Details []*FieldError `json:"details,omitempty"`
+	Items   []*ItemError  `json:"items,omitempty"`
+
+	kind  *Error
+	cause error
+}
+
+// FieldError points at the field or argument of a request that was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
 }
 
-func (err Error) Error() string {
-	return fmt.Sprintf("error: happened %s", err.Message)
+// ItemError describes why a single item of a batch request was rejected.
+type ItemError struct {
+	Index   int    `json:"index"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Err     error  `json:"-"`
 }
 
-func CreateError(err error) error {
-	if _, ok := err.(Error); ok {
-		return err
+func New(code Code, message string) *Error {
+	return &Error{
+		Code:    code,
+		Message: message,
 	}
-
-	return Error{Message: err.Error()}
 }
 
-var (
-	ErrBadRequest error = Error{
-		Message: "bad body of request",
-	}
-	ErrBadArguments error = Error{
-		Message: "bad arguments of request",
-	}
-	ErrDataConflict error = Error{
-		Message: "data conflict",
-	}
-	ErrInternalError error = Error{
-		Message: "internal error",
+func (err *Error) Error() string {
+	if err.cause != nil {
+		return fmt.Sprintf("error: happened %s: %v", err.Message, err.cause)
 	}
-	ErrNotFoundInDB error = Error{
-		Message: "not found in database",
-	}
-	ErrUserNotFound error = Error{
-		Message: "user not found",
-	}
-	ErrForumNotFound error = Error{
-		Message: "forum not found",
-	}
-	ErrThreadNotFound error = Error{
-		Message: "thread not found",
+	return fmt.Sprintf("error: happened %s", err.Message)
+}
+
+func (err *Error) Unwrap() error {
+	return err.cause
+}
+
+// Is reports whether both errors are copies of the same sentinel.
+func (err *Error) Is(target error) bool {
+	targetErr, ok := target.(*Error)
+	return ok && err.root() == targetErr.root()
+}
+
+func (err *Error) root() *Error {
+	if err.kind != nil {
+		return err.kind
 	}
-	ErrPostNotFound error = Error{
-		Message: "post not found",
+	return err
+}
+
+func (err *Error) clone() *Error {
+	copied := *err
+	copied.kind = err.root()
+	return &copied
+}
+
+// Wrap returns a copy of err caused by cause.
+func (err *Error) Wrap(cause error) *Error {
+	copied := err.clone()
+	copied.cause = cause
+	return copied
+}
+
+// WithMessage returns a copy of err with a more specific message.
+func (err *Error) WithMessage(format string, args ...interface{}) *Error {
+	copied := err.clone()
+	copied.Message = fmt.Sprintf(format, args...)
+	return copied
+}
+
+// WithDetails returns a copy of err that points at the given fields.
+func (err *Error) WithDetails(details ...*FieldError) *Error {
+	copied := err.clone()
+	copied.Details = append(append([]*FieldError{}, err.Details...), details...)
+	return copied
+}
+
+func Is(err, target error) bool {
+	return stderrors.Is(err, target)
+}
+
+func As(err error, target interface{}) bool {
+	return stderrors.As(err, target)
+}
+
+func Unwrap(err error) error {
+	return stderrors.Unwrap(err)
+}
+
+// From returns the domain error err is or wraps. Expired contexts become
+// ErrRequestTimeout and any other error ErrInternalError.
+func From(err error) *Error {
+	var domainErr *Error
+	switch {
+	case As(err, &domainErr):
+		return domainErr
+	case Is(err, context.DeadlineExceeded), Is(err, context.Canceled):
+		return ErrRequestTimeout.Wrap(err)
+	default:
+		return ErrInternalError.Wrap(err)
 	}
-	ErrAlreadyExists error = Error{
-		Message: "already exists",
+}
+
+// Translate replaces err with target, keeping err as the cause, when err
+// is target itself or the generic error of its code, such as
+// ErrNotFoundInDB. Other errors, like a failed query or a missing entity
+// of another kind, are returned as they are.
+func Translate(err error, target *Error) error {
+	if err == nil {
+		return nil
 	}
-	ErrEmptyParameters error = Error{
-		Message: "parameters is empty",
+	generic, ok := genericErrors[target.Code]
+	if Is(err, target) || ok && Is(err, generic) {
+		return target.Wrap(err)
 	}
+	return err
+}
+
+var (
+	ErrBadRequest                = New(CodeBadRequest, "bad body of request")
+	ErrBadArguments              = New(CodeBadRequest, "bad arguments of request")
+	ErrDataConflict              = New(CodeConflict, "data conflict")
+	ErrInternalError             = New(CodeInternal, "internal error")
+	ErrNotFoundInDB              = New(CodeNotFound, "not found in database")
+	ErrUserNotFound              = New(CodeNotFound, "user not found")
+	ErrForumNotFound             = New(CodeNotFound, "forum not found")
+	ErrThreadNotFound            = New(CodeNotFound, "thread not found")
+	ErrPostNotFound              = New(CodeNotFound, "post not found")
+	ErrModeratorNotFound         = New(CodeNotFound, "moderator not found")
+	ErrRevisionNotFound          = New(CodeNotFound, "revision not found")
+	ErrWebhookNotFound           = New(CodeNotFound, "webhook not found")
+	ErrParentPostNotFound        = New(CodeConflict, "parent post not found")
+	ErrParentPostInAnotherThread = New(CodeConflict, "parent post was created in another thread")
+	ErrPostDeleted               = New(CodeConflict, "post was deleted")
+	ErrAlreadyExists             = New(CodeConflict, "already exists")
+	ErrForumArchived             = New(CodeConflict, "forum is archived")
+	ErrThreadArchived            = New(CodeConflict, "thread is archived")
+	ErrRequestTimeout            = New(CodeTimeout, "request timeout")
+	ErrEmptyParameters           = New(CodeBadRequest, "parameters is empty")
+	ErrUnauthorized              = New(CodeUnauthorized, "authentication required")
+	ErrInvalidCredentials        = New(CodeUnauthorized, "wrong nickname or password")
+	ErrInvalidToken              = New(CodeUnauthorized, "invalid or expired session token")
+	ErrForbidden                 = New(CodeForbidden, "action is not allowed")
 )
+
+var genericErrors = map[Code]*Error{
+	CodeNotFound: ErrNotFoundInDB,
+	CodeConflict: ErrDataConflict,
+}
+
+// NewBatchError rejects a whole batch and lists every item that caused it.
+// Conflicts with existing data take precedence over missing references
+// when the error for the whole batch is picked.
+func NewBatchError(items []*ItemError) *Error {
+	var cause error = ErrDataConflict
+	for _, item := range items {
+		cause = item.Err
+		if !Is(item.Err, ErrUserNotFound) {
+			break
+		}
+	}
+
+	batchErr := From(cause).clone()
+	batchErr.Items = items
+	return batchErr
+}
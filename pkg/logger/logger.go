@@ -0,0 +1,216 @@
+// Package logger writes leveled, structured log records as JSON objects or
+// as text lines of key=value pairs.
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+func (l Level) String() string {
+	if l < LevelDebug || l > LevelError {
+		return "level(" + strconv.Itoa(int(l)) + ")"
+	}
+	return levelNames[l]
+}
+
+func ParseLevel(name string) (Level, error) {
+	for level, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return Level(level), nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", name)
+}
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Logger writes records of its level and above. Loggers made by With share
+// the output of their parent and add fields to its records.
+type Logger struct {
+	out    *output
+	fields []interface{}
+}
+
+type output struct {
+	mu     sync.Mutex
+	w      io.Writer
+	format string
+	level  Level
+}
+
+// New returns a logger writing records of level and above to w in format,
+// FormatText or FormatJSON.
+func New(w io.Writer, format string, level Level) *Logger {
+	return &Logger{out: &output{w: w, format: format, level: level}}
+}
+
+var (
+	defaultMu     sync.RWMutex
+	defaultLogger = New(os.Stderr, FormatText, LevelInfo)
+)
+
+// Default returns the logger of the process, writing text to stderr until
+// SetDefault replaces it.
+func Default() *Logger {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultLogger
+}
+
+func SetDefault(l *Logger) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultLogger = l
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying l, so that code serving a
+// request logs with its fields.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger carried by ctx or the default one.
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(contextKey{}).(*Logger); ok {
+		return l
+	}
+	return Default()
+}
+
+// With returns a logger adding fields, alternating keys and values, to
+// every record.
+func (l *Logger) With(fields ...interface{}) *Logger {
+	return &Logger{
+		out:    l.out,
+		fields: append(append(make([]interface{}, 0, len(l.fields)+len(fields)), l.fields...), fields...),
+	}
+}
+
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.out.level
+}
+
+func (l *Logger) Debug(msg string, fields ...interface{}) {
+	l.log(LevelDebug, msg, fields)
+}
+
+func (l *Logger) Info(msg string, fields ...interface{}) {
+	l.log(LevelInfo, msg, fields)
+}
+
+func (l *Logger) Warn(msg string, fields ...interface{}) {
+	l.log(LevelWarn, msg, fields)
+}
+
+func (l *Logger) Error(msg string, fields ...interface{}) {
+	l.log(LevelError, msg, fields)
+}
+
+// Printf logs an error, so that the logger can serve as fasthttp.Logger,
+// which only reports errors.
+func (l *Logger) Printf(format string, args ...interface{}) {
+	l.log(LevelError, fmt.Sprintf(format, args...), nil)
+}
+
+func (l *Logger) log(level Level, msg string, fields []interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+
+	all := append(append(make([]interface{}, 0, len(l.fields)+len(fields)), l.fields...), fields...)
+	if len(all)%2 != 0 {
+		all = append(all, "(missing)")
+	}
+
+	var buf bytes.Buffer
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	if l.out.format == FormatJSON {
+		writeJSON(&buf, now, level, msg, all)
+	} else {
+		writeText(&buf, now, level, msg, all)
+	}
+
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	l.out.w.Write(buf.Bytes())
+}
+
+func writeJSON(buf *bytes.Buffer, now string, level Level, msg string, fields []interface{}) {
+	buf.WriteString(`{"time":`)
+	writeJSONValue(buf, now)
+	buf.WriteString(`,"level":`)
+	writeJSONValue(buf, level.String())
+	buf.WriteString(`,"msg":`)
+	writeJSONValue(buf, msg)
+	for i := 0; i < len(fields); i += 2 {
+		buf.WriteByte(',')
+		writeJSONValue(buf, fmt.Sprint(fields[i]))
+		buf.WriteByte(':')
+		writeJSONValue(buf, plain(fields[i+1]))
+	}
+	buf.WriteString("}\n")
+}
+
+func writeJSONValue(buf *bytes.Buffer, value interface{}) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		encoded, _ = json.Marshal(fmt.Sprint(value))
+	}
+	buf.Write(encoded)
+}
+
+func writeText(buf *bytes.Buffer, now string, level Level, msg string, fields []interface{}) {
+	buf.WriteString(now)
+	buf.WriteByte(' ')
+	buf.WriteString(strings.ToUpper(level.String()))
+	buf.WriteByte(' ')
+	buf.WriteString(msg)
+	for i := 0; i < len(fields); i += 2 {
+		buf.WriteByte(' ')
+		buf.WriteString(fmt.Sprint(fields[i]))
+		buf.WriteByte('=')
+		value := fmt.Sprint(plain(fields[i+1]))
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = strconv.Quote(value)
+		}
+		buf.WriteString(value)
+	}
+	buf.WriteByte('\n')
+}
+
+// plain turns values without a useful JSON encoding into strings.
+func plain(value interface{}) interface{} {
+	switch v := value.(type) {
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return value
+	}
+}
@@ -0,0 +1,287 @@
+// Package metrics keeps counters, gauges and histograms and writes them in
+// the Prometheus text exposition format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefBuckets suit the latencies of HTTP requests, in seconds.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// QueryBuckets suit the latencies of database queries, in seconds.
+var QueryBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1}
+
+type Registry struct {
+	mu       sync.Mutex
+	families []family
+}
+
+// family is a metric with its series.
+type family interface {
+	write(w *bufio.Writer)
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(f family) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.families = append(r.families, f)
+}
+
+// Write writes every metric in the order they were registered.
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	families := append([]family(nil), r.families...)
+	r.mu.Unlock()
+
+	buffered := bufio.NewWriter(w)
+	for _, f := range families {
+		f.write(buffered)
+	}
+	return buffered.Flush()
+}
+
+type desc struct {
+	name       string
+	help       string
+	kind       string
+	labelNames []string
+}
+
+func (d *desc) writeHeader(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.name, escapeHelp(d.help), d.name, d.kind)
+}
+
+// labels renders label pairs, extra ones following those of the metric.
+func (d *desc) labels(values []string, extra ...string) string {
+	if len(d.labelNames) == 0 && len(extra) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(values)+len(extra)/2)
+	for i, name := range d.labelNames {
+		pairs = append(pairs, name+`="`+escapeLabel(values[i])+`"`)
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+`="`+escapeLabel(extra[i+1])+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// vec keeps the series of a metric by label values.
+type vec struct {
+	desc
+	mu     sync.Mutex
+	series map[string]interface{}
+	keys   map[string][]string
+	create func() interface{}
+}
+
+func (v *vec) with(values []string) interface{} {
+	if len(values) != len(v.labelNames) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", v.name, len(v.labelNames), len(values)))
+	}
+
+	key := strings.Join(values, "\xff")
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	series, ok := v.series[key]
+	if !ok {
+		series = v.create()
+		v.series[key] = series
+		v.keys[key] = append([]string(nil), values...)
+	}
+	return series
+}
+
+// each calls fn for the series ordered by their label values.
+func (v *vec) each(fn func(values []string, series interface{})) {
+	v.mu.Lock()
+	keys := make([]string, 0, len(v.series))
+	for key := range v.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	values := make([][]string, len(keys))
+	series := make([]interface{}, len(keys))
+	for i, key := range keys {
+		values[i], series[i] = v.keys[key], v.series[key]
+	}
+	v.mu.Unlock()
+
+	for i := range keys {
+		fn(values[i], series[i])
+	}
+}
+
+type Counter struct {
+	mu    sync.Mutex
+	value float64
+}
+
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+func (c *Counter) Add(delta float64) {
+	c.mu.Lock()
+	c.value += delta
+	c.mu.Unlock()
+}
+
+func (c *Counter) get() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.value
+}
+
+type CounterVec struct {
+	vec
+}
+
+func (r *Registry) Counter(name, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{vec{
+		desc:   desc{name: name, help: help, kind: "counter", labelNames: labelNames},
+		series: make(map[string]interface{}),
+		keys:   make(map[string][]string),
+		create: func() interface{} { return &Counter{} },
+	}}
+	r.register(c)
+	return c
+}
+
+// With returns the counter of the label values, creating it at zero.
+func (c *CounterVec) With(labelValues ...string) *Counter {
+	return c.with(labelValues).(*Counter)
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.writeHeader(w)
+	c.each(func(values []string, series interface{}) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, c.labels(values), formatValue(series.(*Counter).get()))
+	})
+}
+
+type Histogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func (h *Histogram) Observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i, bound := range h.buckets {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+// Since observes the seconds passed since start.
+func (h *Histogram) Since(start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
+
+type HistogramVec struct {
+	vec
+}
+
+func (r *Registry) Histogram(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	h := &HistogramVec{vec{
+		desc:   desc{name: name, help: help, kind: "histogram", labelNames: labelNames},
+		series: make(map[string]interface{}),
+		keys:   make(map[string][]string),
+		create: func() interface{} {
+			return &Histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+		},
+	}}
+	r.register(h)
+	return h
+}
+
+func (h *HistogramVec) With(labelValues ...string) *Histogram {
+	return h.with(labelValues).(*Histogram)
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.writeHeader(w)
+	h.each(func(values []string, series interface{}) {
+		histogram := series.(*Histogram)
+		histogram.mu.Lock()
+		counts := append([]uint64(nil), histogram.counts...)
+		sum, count := histogram.sum, histogram.count
+		histogram.mu.Unlock()
+
+		for i, bound := range histogram.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labels(values, "le", formatValue(bound)), counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labels(values, "le", "+Inf"), count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labels(values), formatValue(sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labels(values), count)
+	})
+}
+
+// gaugeFunc is a gauge, or a counter kept elsewhere, read when written.
+type gaugeFunc struct {
+	desc
+	value func() float64
+}
+
+func (r *Registry) GaugeFunc(name, help string, value func() float64) {
+	r.register(&gaugeFunc{desc: desc{name: name, help: help, kind: "gauge"}, value: value})
+}
+
+// CounterFunc registers a counter whose value is kept elsewhere, like the
+// statistics of sql.DB.
+func (r *Registry) CounterFunc(name, help string, value func() float64) {
+	r.register(&gaugeFunc{desc: desc{name: name, help: help, kind: "counter"}, value: value})
+}
+
+func (g *gaugeFunc) write(w *bufio.Writer) {
+	g.writeHeader(w)
+	fmt.Fprintf(w, "%s %s\n", g.name, formatValue(g.value()))
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func write(t *testing.T, registry *Registry) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := registry.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestHistogram(t *testing.T) {
+	registry := NewRegistry()
+	latencies := registry.Histogram("latency_seconds", "Latency.", []float64{.1, .5, 1}, "route")
+	histogram := latencies.With("/api/forum/{slug}")
+	for _, value := range []float64{.05, .1, .3, .7, 2} {
+		histogram.Observe(value)
+	}
+
+	want := `# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/api/forum/{slug}",le="0.1"} 2
+latency_seconds_bucket{route="/api/forum/{slug}",le="0.5"} 3
+latency_seconds_bucket{route="/api/forum/{slug}",le="1"} 4
+latency_seconds_bucket{route="/api/forum/{slug}",le="+Inf"} 5
+latency_seconds_sum{route="/api/forum/{slug}"} 3.15
+latency_seconds_count{route="/api/forum/{slug}"} 5
+`
+	if got := write(t, registry); got != want {
+		t.Errorf("exposition:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestHistogramWithoutLabels(t *testing.T) {
+	registry := NewRegistry()
+	registry.Histogram("query_seconds", "Queries.", []float64{1}).With().Observe(3)
+
+	got := write(t, registry)
+	for _, line := range []string{
+		`query_seconds_bucket{le="1"} 0`,
+		`query_seconds_bucket{le="+Inf"} 1`,
+		`query_seconds_sum 3`,
+		`query_seconds_count 1`,
+	} {
+		if !strings.Contains(got, line+"\n") {
+			t.Errorf("exposition lacks %q:\n%s", line, got)
+		}
+	}
+}
+
+func TestCounter(t *testing.T) {
+	registry := NewRegistry()
+	requests := registry.Counter("requests_total", "Requests.", "method", "status")
+	requests.With("POST", "201").Inc()
+	requests.With("GET", "200").Add(2)
+	requests.With("GET", "200").Inc()
+
+	want := `# HELP requests_total Requests.
+# TYPE requests_total counter
+requests_total{method="GET",status="200"} 3
+requests_total{method="POST",status="201"} 1
+`
+	if got := write(t, registry); got != want {
+		t.Errorf("exposition:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestEscaping(t *testing.T) {
+	registry := NewRegistry()
+	registry.Counter("escaped_total", "A \\ help\non two lines with \"quotes\".", "value").
+		With("back\\slash \"quoted\"\nnext line").Inc()
+
+	want := `# HELP escaped_total A \\ help\non two lines with "quotes".
+# TYPE escaped_total counter
+escaped_total{value="back\\slash \"quoted\"\nnext line"} 1
+`
+	if got := write(t, registry); got != want {
+		t.Errorf("exposition:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	registry := NewRegistry()
+	registry.GaugeFunc("open_connections", "Open connections.", func() float64 { return 4 })
+	registry.CounterFunc("waits_total", "Waits.", func() float64 { return math.Inf(1) })
+
+	want := `# HELP open_connections Open connections.
+# TYPE open_connections gauge
+open_connections 4
+# HELP waits_total Waits.
+# TYPE waits_total counter
+waits_total +Inf
+`
+	if got := write(t, registry); got != want {
+		t.Errorf("exposition:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWrongLabelCount(t *testing.T) {
+	registry := NewRegistry()
+	requests := registry.Counter("requests_total", "Requests.", "method")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("With accepted a wrong number of label values")
+		}
+	}()
+	requests.With("GET", "200")
+}
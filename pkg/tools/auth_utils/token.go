@@ -0,0 +1,61 @@
+package auth_utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// Signer issues tokens made of a random id and its HMAC, so forged tokens
+// are rejected before the id is looked up.
+type Signer struct {
+	secret []byte
+}
+
+func NewSigner(secret []byte) *Signer {
+	return &Signer{secret: secret}
+}
+
+// RandomSecret returns a key for a signer whose tokens are only valid
+// while the process runs.
+func RandomSecret() ([]byte, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// NewToken returns a fresh id and the signed token carrying it.
+func (s *Signer) NewToken() (id, token string, err error) {
+	rawId := make([]byte, 32)
+	if _, err := rand.Read(rawId); err != nil {
+		return "", "", err
+	}
+
+	id = hex.EncodeToString(rawId)
+	return id, id + "." + s.sign(id), nil
+}
+
+// Verify returns the id carried by a token with a valid signature.
+func (s *Signer) Verify(token string) (string, bool) {
+	dot := strings.IndexByte(token, '.')
+	if dot < 0 {
+		return "", false
+	}
+
+	id, signature := token[:dot], token[dot+1:]
+	if !hmac.Equal([]byte(signature), []byte(s.sign(id))) {
+		return "", false
+	}
+	return id, true
+}
+
+func (s *Signer) sign(id string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
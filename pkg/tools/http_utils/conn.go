@@ -0,0 +1,120 @@
+package http_utils
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// NewWatchedListener wraps ln so that handlers wrapped by WithContext learn
+// about clients closing their connections while a request is processed.
+func NewWatchedListener(ln net.Listener) net.Listener {
+	return &watchedListener{Listener: ln}
+}
+
+type watchedListener struct {
+	net.Listener
+}
+
+func (l *watchedListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	return &watchedConn{Conn: conn}, nil
+}
+
+// watchedConn reads from the connection in the background while a handler
+// runs. The server doesn't read the connection at that time, so a read
+// error means the client has gone; bytes of a pipelined request are kept
+// and returned by the next Read.
+type watchedConn struct {
+	net.Conn
+
+	mu           sync.Mutex
+	buffered     []byte
+	readErr      error
+	readDeadline time.Time
+}
+
+func (c *watchedConn) Read(b []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.buffered) != 0 {
+		n := copy(b, c.buffered)
+		c.buffered = c.buffered[n:]
+		return n, nil
+	}
+	if c.readErr != nil {
+		return 0, c.readErr
+	}
+
+	return c.Conn.Read(b)
+}
+
+func (c *watchedConn) SetDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.readDeadline = t
+	c.mu.Unlock()
+
+	return c.Conn.SetDeadline(t)
+}
+
+func (c *watchedConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.readDeadline = t
+	c.mu.Unlock()
+
+	return c.Conn.SetReadDeadline(t)
+}
+
+// watch calls onClose if the client closes the connection before the
+// returned stop function is called.
+func (c *watchedConn) watch(onClose func()) (stop func()) {
+	stopped := make(chan struct{})
+	done := make(chan struct{})
+
+	c.mu.Lock()
+	pending := len(c.buffered) != 0 || c.readErr != nil
+	c.mu.Unlock()
+	if pending {
+		close(done)
+	} else {
+		c.Conn.SetReadDeadline(time.Time{})
+		go func() {
+			defer close(done)
+
+			buf := make([]byte, 1)
+			n, err := c.Conn.Read(buf)
+
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.buffered = append(c.buffered, buf[:n]...)
+			if err == nil {
+				return
+			}
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				return
+			}
+
+			select {
+			case <-stopped:
+			default:
+				onClose()
+			}
+			c.readErr = err
+		}()
+	}
+
+	return func() {
+		close(stopped)
+		c.Conn.SetReadDeadline(time.Now())
+		<-done
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.Conn.SetReadDeadline(c.readDeadline)
+	}
+}
@@ -0,0 +1,57 @@
+package http_utils
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/forum-api-back/pkg/errors"
+
+	"github.com/valyala/fasthttp"
+)
+
+const contextKey = "http_utils.context"
+
+// WithContext attaches a context.Context to every request passed to
+// handler. The context expires after timeout (when it is positive) and is
+// canceled as soon as the client closes its connection.
+func WithContext(handler fasthttp.RequestHandler, timeout time.Duration) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		var requestCtx context.Context
+		var cancel context.CancelFunc
+		if timeout > 0 {
+			requestCtx, cancel = context.WithTimeout(context.Background(), timeout)
+		} else {
+			requestCtx, cancel = context.WithCancel(context.Background())
+		}
+		defer cancel()
+
+		if conn, ok := ctx.Conn().(*watchedConn); ok {
+			stop := conn.watch(cancel)
+			defer stop()
+		}
+
+		ctx.SetUserValue(contextKey, requestCtx)
+		handler(ctx)
+
+		// A failure after the deadline is most likely caused by it.
+		if ctx.Response.StatusCode() >= http.StatusBadRequest && requestCtx.Err() == context.DeadlineExceeded {
+			ctx.Response.ResetBody()
+			SetErrorResponse(ctx, errors.ErrRequestTimeout.Wrap(requestCtx.Err()))
+		}
+	}
+}
+
+// Context returns the context attached by WithContext.
+func Context(ctx *fasthttp.RequestCtx) context.Context {
+	if requestCtx, ok := ctx.UserValue(contextKey).(context.Context); ok {
+		return requestCtx
+	}
+	return context.Background()
+}
+
+// SetContext replaces the context of a request, so middlewares can add
+// values to it for the handlers they wrap.
+func SetContext(ctx *fasthttp.RequestCtx, requestCtx context.Context) {
+	ctx.SetUserValue(contextKey, requestCtx)
+}
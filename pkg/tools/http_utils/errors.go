@@ -0,0 +1,33 @@
+package http_utils
+
+import (
+	"net/http"
+
+	"github.com/forum-api-back/pkg/errors"
+
+	"github.com/valyala/fasthttp"
+)
+
+var statusCodes = map[errors.Code]int{
+	errors.CodeBadRequest:   http.StatusBadRequest,
+	errors.CodeUnauthorized: http.StatusUnauthorized,
+	errors.CodeForbidden:    http.StatusForbidden,
+	errors.CodeNotFound:     http.StatusNotFound,
+	errors.CodeConflict:     http.StatusConflict,
+	errors.CodeTimeout:      http.StatusGatewayTimeout,
+	errors.CodeInternal:     http.StatusInternalServerError,
+}
+
+// StatusCode returns the HTTP status err is reported with.
+func StatusCode(err error) int {
+	if statusCode, ok := statusCodes[errors.From(err).Code]; ok {
+		return statusCode
+	}
+	return http.StatusInternalServerError
+}
+
+// SetErrorResponse reports err to the client. Every handler goes through
+// it, so the same error always gets the same status and body.
+func SetErrorResponse(ctx *fasthttp.RequestCtx, err error) {
+	SetJSONResponse(ctx, errors.From(err), StatusCode(err))
+}
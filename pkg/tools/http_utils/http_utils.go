@@ -2,9 +2,10 @@ package http_utils
 
 import (
 	"encoding/json"
-	"log"
 	"net/http"
 
+	"github.com/forum-api-back/pkg/logger"
+
 	"github.com/valyala/fasthttp"
 )
 
@@ -13,14 +14,11 @@ func SetJSONResponse(ctx *fasthttp.RequestCtx, body interface{}, statusCode int)
 
 	result, err := json.Marshal(body)
 	if err != nil {
+		logger.FromContext(Context(ctx)).Error("can't marshal response", "error", err)
 		ctx.SetStatusCode(http.StatusBadRequest)
-		if _, err := ctx.Write([]byte("{\"error\": \"can't marshal body\"}")); err != nil {
-			log.Fatal(err)
-		}
+		ctx.SetBodyString("{\"error\": \"can't marshal body\"}")
 		return
 	}
 	ctx.SetStatusCode(statusCode)
-	if _, err := ctx.Write(result); err != nil {
-		log.Fatal(err)
-	}
+	ctx.SetBody(result)
 }
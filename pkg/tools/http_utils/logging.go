@@ -0,0 +1,81 @@
+package http_utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/forum-api-back/pkg/logger"
+
+	"github.com/valyala/fasthttp"
+)
+
+const RequestIDHeader = "X-Request-Id"
+
+// maxRequestIDLength bounds the ids taken from clients.
+const maxRequestIDLength = 64
+
+// WithRequestID gives every request an id, the one sent by the client in
+// X-Request-Id when it is sensible, and returns it in the same header. The
+// handler runs with log, carrying the id, in its context, so repositories
+// and usecases serving the request log it too. It must be wrapped by
+// WithContext.
+func WithRequestID(handler fasthttp.RequestHandler, log *logger.Logger) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		requestID := string(ctx.Request.Header.Peek(RequestIDHeader))
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		ctx.Response.Header.Set(RequestIDHeader, requestID)
+
+		requestLog := log.With("request_id", requestID)
+		SetContext(ctx, logger.NewContext(Context(ctx), requestLog))
+		handler(ctx)
+	}
+}
+
+// WithAccessLog logs every request of a route once it is served, with the
+// logger of its context. It must wrap WithContext to see the responses of
+// timed out requests.
+func WithAccessLog(handler fasthttp.RequestHandler, method, path string) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		start := time.Now()
+		handler(ctx)
+
+		status := ctx.Response.StatusCode()
+		log := logger.FromContext(Context(ctx))
+		record := log.Info
+		if status >= http.StatusInternalServerError {
+			record = log.Error
+		}
+		record("request",
+			"method", method,
+			"route", path,
+			"path", string(ctx.Path()),
+			"status", status,
+			"duration", time.Since(start),
+			"size", len(ctx.Response.Body()),
+			"remote_addr", ctx.RemoteIP().String())
+	}
+}
+
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range requestID {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,36 @@
+package http_utils
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/forum-api-back/pkg/metrics"
+
+	"github.com/valyala/fasthttp"
+)
+
+// WithMetrics counts the requests of a route by response status into
+// requests and observes their latency into durations. Both are labeled
+// with the method and the path pattern of the route, so ids in paths
+// don't make new series.
+func WithMetrics(handler fasthttp.RequestHandler, method, path string,
+	requests *metrics.CounterVec, durations *metrics.HistogramVec) fasthttp.RequestHandler {
+	latency := durations.With(method, path)
+	return func(ctx *fasthttp.RequestCtx) {
+		start := time.Now()
+		handler(ctx)
+		latency.Since(start)
+		requests.With(method, path, strconv.Itoa(ctx.Response.StatusCode())).Inc()
+	}
+}
+
+// MetricsHandler serves the metrics of registry in the Prometheus text
+// exposition format.
+func MetricsHandler(registry *metrics.Registry) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		ctx.SetContentType("text/plain; version=0.0.4; charset=utf-8")
+		if err := registry.Write(ctx); err != nil {
+			SetErrorResponse(ctx, err)
+		}
+	}
+}
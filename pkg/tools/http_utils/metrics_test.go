@@ -0,0 +1,55 @@
+package http_utils
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/forum-api-back/pkg/metrics"
+
+	"github.com/fasthttp/router"
+	"github.com/valyala/fasthttp"
+)
+
+func TestWithMetricsLabelsRoutes(t *testing.T) {
+	registry := metrics.NewRegistry()
+	requests := registry.Counter("http_requests_total", "Requests.", "method", "route", "status")
+	latencies := registry.Histogram("http_request_duration_seconds", "Latency.", []float64{10}, "method", "route")
+
+	const path = "/api/forum/{slug}/details"
+	mainRouter := router.New()
+	mainRouter.GET(path, WithMetrics(func(ctx *fasthttp.RequestCtx) {
+		if ctx.UserValue("slug") == "missing" {
+			ctx.SetStatusCode(http.StatusNotFound)
+		}
+	}, http.MethodGet, path, requests, latencies))
+
+	for _, slug := range []string{"first", "second", "missing"} {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod(http.MethodGet)
+		ctx.Request.SetRequestURI("/api/forum/" + slug + "/details")
+		mainRouter.Handler(ctx)
+	}
+
+	var buf bytes.Buffer
+	if err := registry.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	for _, line := range []string{
+		`http_requests_total{method="GET",route="/api/forum/{slug}/details",status="200"} 2`,
+		`http_requests_total{method="GET",route="/api/forum/{slug}/details",status="404"} 1`,
+		`http_request_duration_seconds_bucket{method="GET",route="/api/forum/{slug}/details",le="+Inf"} 3`,
+		`http_request_duration_seconds_count{method="GET",route="/api/forum/{slug}/details"} 3`,
+	} {
+		if !strings.Contains(got, line+"\n") {
+			t.Errorf("exposition lacks %q:\n%s", line, got)
+		}
+	}
+	for _, slug := range []string{"first", "second", "missing"} {
+		if strings.Contains(got, "/api/forum/"+slug) {
+			t.Errorf("exposition labels a series with the slug %s:\n%s", slug, got)
+		}
+	}
+}
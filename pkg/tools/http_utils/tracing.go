@@ -0,0 +1,44 @@
+package http_utils
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/forum-api-back/pkg/logger"
+	"github.com/forum-api-back/pkg/tracing"
+
+	"github.com/valyala/fasthttp"
+)
+
+const TraceparentHeader = "traceparent"
+
+// WithTracing runs every request of a route in a span named after the
+// method and the path pattern, continuing the trace of a traceparent
+// header. The usecases and repositories called by the handler record its
+// child spans. Records logged while serving the request carry the trace
+// id. It must be wrapped by WithContext.
+func WithTracing(handler fasthttp.RequestHandler, tracer *tracing.Tracer, method, path string) fasthttp.RequestHandler {
+	name := method + " " + path
+	return func(ctx *fasthttp.RequestCtx) {
+		parent, _ := tracing.ParseTraceparent(string(ctx.Request.Header.Peek(TraceparentHeader)))
+		requestCtx, span := tracer.Start(Context(ctx), name, parent)
+		requestLog := logger.FromContext(requestCtx).With("trace_id", span.Context().TraceID.String())
+		SetContext(ctx, logger.NewContext(requestCtx, requestLog))
+
+		handler(ctx)
+
+		status := ctx.Response.StatusCode()
+		span.SetAttribute("http.method", method)
+		span.SetAttribute("http.route", path)
+		span.SetAttribute("http.target", string(ctx.RequestURI()))
+		span.SetAttribute("http.status_code", status)
+		if requestID := ctx.Response.Header.Peek(RequestIDHeader); len(requestID) != 0 {
+			span.SetAttribute("request_id", string(requestID))
+		}
+		var err error
+		if status >= http.StatusInternalServerError {
+			err = fmt.Errorf("%d %s", status, http.StatusText(status))
+		}
+		span.End(err)
+	}
+}
@@ -0,0 +1,59 @@
+package sql_utils
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/forum-api-back/pkg/errors"
+
+	"github.com/lib/pq"
+)
+
+const (
+	ForeignKeyViolation = "23503"
+	UniqueViolation     = "23505"
+	QueryCanceled       = "57014"
+	RaiseException      = "P0001"
+)
+
+// Error turns a database error into a domain error wrapping it: missing
+// rows become notFound, constraint violations ErrDataConflict, canceled
+// queries ErrRequestTimeout and anything else ErrInternalError. Domain
+// errors are returned as they are.
+func Error(err error, notFound *errors.Error) error {
+	if err == nil {
+		return nil
+	}
+
+	var domainErr *errors.Error
+	if errors.As(err, &domainErr) {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return notFound.Wrap(err)
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return errors.ErrRequestTimeout.Wrap(err)
+	}
+
+	if pqErr, ok := err.(*pq.Error); ok {
+		switch pqErr.Code {
+		case ForeignKeyViolation, UniqueViolation:
+			return errors.ErrDataConflict.Wrap(err)
+		case QueryCanceled:
+			return errors.ErrRequestTimeout.Wrap(err)
+		}
+	}
+
+	return errors.ErrInternalError.Wrap(err)
+}
+
+// Constraint returns the name of the constraint err violates, if any.
+func Constraint(err error) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Constraint
+	}
+	return ""
+}
@@ -0,0 +1,281 @@
+// Package tracing records spans of the work done for a request and hands
+// them to an exporter. Traces are propagated in the W3C traceparent format.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+type TraceID [16]byte
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+type SpanID [8]byte
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanContext identifies a span across processes.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+func (c SpanContext) IsValid() bool {
+	return c.TraceID != TraceID{} && c.SpanID != SpanID{}
+}
+
+// Traceparent formats c as the value of a traceparent header.
+func (c SpanContext) Traceparent() string {
+	flags := "00"
+	if c.Sampled {
+		flags = "01"
+	}
+	return "00-" + c.TraceID.String() + "-" + c.SpanID.String() + "-" + flags
+}
+
+// ParseTraceparent parses the value of a traceparent header. Versions
+// after 00 are read as far as 00 defines them. Malformed headers give an
+// invalid context, so that callers may ignore the error.
+func ParseTraceparent(header string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 {
+		return SpanContext{}, fmt.Errorf("tracing: malformed traceparent %q", header)
+	}
+
+	var sc SpanContext
+	version := make([]byte, 1)
+	if err := decodeHex(version, parts[0]); err != nil || version[0] == 0xff || (version[0] == 0 && len(parts) != 4) {
+		return SpanContext{}, fmt.Errorf("tracing: malformed traceparent %q", header)
+	}
+	if err := decodeHex(sc.TraceID[:], parts[1]); err != nil {
+		return SpanContext{}, fmt.Errorf("tracing: malformed trace id %q", parts[1])
+	}
+	if err := decodeHex(sc.SpanID[:], parts[2]); err != nil {
+		return SpanContext{}, fmt.Errorf("tracing: malformed parent id %q", parts[2])
+	}
+	flags := make([]byte, 1)
+	if err := decodeHex(flags, parts[3]); err != nil {
+		return SpanContext{}, fmt.Errorf("tracing: malformed trace flags %q", parts[3])
+	}
+	sc.Sampled = flags[0]&1 == 1
+
+	if !sc.IsValid() {
+		return SpanContext{}, fmt.Errorf("tracing: traceparent %q has a zero id", header)
+	}
+	return sc, nil
+}
+
+// decodeHex decodes lowercase hex filling dst exactly.
+func decodeHex(dst []byte, value string) error {
+	if len(value) != 2*len(dst) || strings.ToLower(value) != value {
+		return fmt.Errorf("tracing: want %d hex digits", 2*len(dst))
+	}
+	_, err := hex.Decode(dst, []byte(value))
+	return err
+}
+
+// SpanData is a finished span as it is exported.
+type SpanData struct {
+	Service    string                 `json:"service"`
+	Name       string                 `json:"name"`
+	TraceID    string                 `json:"trace_id"`
+	SpanID     string                 `json:"span_id"`
+	ParentID   string                 `json:"parent_id,omitempty"`
+	Start      time.Time              `json:"start"`
+	End        time.Time              `json:"end"`
+	DurationMs float64                `json:"duration_ms"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+type Exporter interface {
+	// Export is called with every sampled span once it ends, from the
+	// goroutine ending it.
+	Export(span *SpanData) error
+	Close() error
+}
+
+// Tracer starts the root spans of requests.
+type Tracer struct {
+	service     string
+	exporter    Exporter
+	sampleRatio float64
+	onError     func(err error)
+}
+
+// NewTracer returns a tracer exporting spans of service. Traces continued
+// from a parent keep its sampling decision; new ones are sampled with
+// sampleRatio probability. Export failures are passed to onError.
+func NewTracer(service string, exporter Exporter, sampleRatio float64, onError func(err error)) *Tracer {
+	return &Tracer{
+		service:     service,
+		exporter:    exporter,
+		sampleRatio: sampleRatio,
+		onError:     onError,
+	}
+}
+
+// Start starts the root span of a request, continuing the trace of parent
+// when it is valid.
+func (t *Tracer) Start(ctx context.Context, name string, parent SpanContext) (context.Context, *Span) {
+	span := &Span{
+		tracer: t,
+		name:   name,
+		start:  time.Now(),
+	}
+	if parent.IsValid() {
+		span.context = SpanContext{TraceID: parent.TraceID, Sampled: parent.Sampled}
+		span.parentID = parent.SpanID
+	} else {
+		span.context.Sampled = t.sample()
+		randomID(span.context.TraceID[:])
+	}
+	randomID(span.context.SpanID[:])
+
+	return context.WithValue(ctx, contextKey{}, span), span
+}
+
+// Close closes the exporter once no more spans end.
+func (t *Tracer) Close() error {
+	return t.exporter.Close()
+}
+
+func (t *Tracer) sample() bool {
+	if t.sampleRatio >= 1 {
+		return true
+	}
+	if t.sampleRatio <= 0 {
+		return false
+	}
+	var value [8]byte
+	randomID(value[:])
+	return float64(binary.BigEndian.Uint64(value[:])>>11)/(1<<53) < t.sampleRatio
+}
+
+type contextKey struct{}
+
+// Start starts a child of the span in ctx. Without one, or when the trace
+// isn't sampled, it returns ctx and a nil span, on which every method does
+// nothing, so that work done outside of traced requests costs nothing.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	parent := FromContext(ctx)
+	if parent == nil || !parent.context.Sampled {
+		return ctx, nil
+	}
+
+	span := &Span{
+		tracer:   parent.tracer,
+		name:     name,
+		start:    time.Now(),
+		context:  SpanContext{TraceID: parent.context.TraceID, Sampled: true},
+		parentID: parent.context.SpanID,
+	}
+	randomID(span.context.SpanID[:])
+
+	return context.WithValue(ctx, contextKey{}, span), span
+}
+
+// FromContext returns the span of ctx or nil.
+func FromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(contextKey{}).(*Span)
+	return span
+}
+
+type Span struct {
+	tracer   *Tracer
+	name     string
+	start    time.Time
+	context  SpanContext
+	parentID SpanID
+
+	mu         sync.Mutex
+	attributes map[string]interface{}
+	ended      bool
+}
+
+func (s *Span) Context() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.context
+}
+
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil || !s.context.Sampled {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.attributes == nil {
+		s.attributes = make(map[string]interface{})
+	}
+	s.attributes[key] = value
+}
+
+// End ends the span, as failed with err unless it is nil, and exports it
+// when its trace is sampled. Later calls do nothing.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	end := time.Now()
+
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	attributes := s.attributes
+	s.mu.Unlock()
+
+	if !s.context.Sampled {
+		return
+	}
+	data := &SpanData{
+		Service:    s.tracer.service,
+		Name:       s.name,
+		TraceID:    s.context.TraceID.String(),
+		SpanID:     s.context.SpanID.String(),
+		Start:      s.start,
+		End:        end,
+		DurationMs: float64(end.Sub(s.start)) / float64(time.Millisecond),
+		Attributes: attributes,
+	}
+	if s.parentID != (SpanID{}) {
+		data.ParentID = s.parentID.String()
+	}
+	if err != nil {
+		data.Error = err.Error()
+	}
+
+	if exportErr := s.tracer.exporter.Export(data); exportErr != nil && s.tracer.onError != nil {
+		s.tracer.onError(exportErr)
+	}
+}
+
+func randomID(id []byte) {
+	for {
+		if _, err := rand.Read(id); err != nil {
+			panic(fmt.Sprintf("tracing: can't read random bytes: %v", err))
+		}
+		for _, b := range id {
+			if b != 0 {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	const (
+		traceId = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanId  = "00f067aa0ba902b7"
+	)
+
+	tests := []struct {
+		name    string
+		header  string
+		sampled bool
+		wantErr bool
+	}{
+		{"sampled", "00-" + traceId + "-" + spanId + "-01", true, false},
+		{"not sampled", "00-" + traceId + "-" + spanId + "-00", false, false},
+		{"other flags", "00-" + traceId + "-" + spanId + "-03", true, false},
+		{"surrounding spaces", " 00-" + traceId + "-" + spanId + "-01 ", true, false},
+		{"future version", "01-" + traceId + "-" + spanId + "-01", true, false},
+		{"future version with more fields", "cc-" + traceId + "-" + spanId + "-01-what-the-future-holds", true, false},
+		{"version 00 with more fields", "00-" + traceId + "-" + spanId + "-01-extra", false, true},
+		{"version ff", "ff-" + traceId + "-" + spanId + "-01", false, true},
+		{"uppercase trace id", "00-4BF92F3577B34DA6A3CE929D0E0E4736-" + spanId + "-01", false, true},
+		{"uppercase parent id", "00-" + traceId + "-00F067AA0BA902B7-01", false, true},
+		{"uppercase version", "0A-" + traceId + "-" + spanId + "-01", false, true},
+		{"zero trace id", "00-00000000000000000000000000000000-" + spanId + "-01", false, true},
+		{"zero parent id", "00-" + traceId + "-0000000000000000-01", false, true},
+		{"short trace id", "00-4bf92f3577b34da6a3ce929d0e0e47-" + spanId + "-01", false, true},
+		{"not hex", "00-" + traceId + "-" + "00f067aa0ba902bz" + "-01", false, true},
+		{"missing flags", "00-" + traceId + "-" + spanId, false, true},
+		{"empty", "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, err := ParseTraceparent(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTraceparent(%q) error = %v, want error %v", tt.header, err, tt.wantErr)
+			}
+			if err != nil {
+				if sc.IsValid() {
+					t.Errorf("ParseTraceparent(%q) = %+v on error", tt.header, sc)
+				}
+				return
+			}
+			if sc.TraceID.String() != traceId || sc.SpanID.String() != spanId || sc.Sampled != tt.sampled {
+				t.Errorf("ParseTraceparent(%q) = %s", tt.header, sc.Traceparent())
+			}
+		})
+	}
+}
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	for _, sampled := range []bool{true, false} {
+		sc := SpanContext{Sampled: sampled}
+		randomID(sc.TraceID[:])
+		randomID(sc.SpanID[:])
+
+		parsed, err := ParseTraceparent(sc.Traceparent())
+		if err != nil || parsed != sc {
+			t.Errorf("ParseTraceparent(%q) = %+v, %v, want %+v", sc.Traceparent(), parsed, err, sc)
+		}
+	}
+}
+
+// recordingExporter keeps the spans it exports.
+type recordingExporter struct {
+	mu    sync.Mutex
+	spans []*SpanData
+}
+
+func (e *recordingExporter) Export(span *SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, span)
+	return nil
+}
+
+func (e *recordingExporter) Close() error {
+	return nil
+}
+
+func TestSampling(t *testing.T) {
+	parent := SpanContext{}
+	randomID(parent.TraceID[:])
+	randomID(parent.SpanID[:])
+	sampledParent := parent
+	sampledParent.Sampled = true
+
+	tests := []struct {
+		name   string
+		ratio  float64
+		parent SpanContext
+		want   bool
+	}{
+		{"new trace, ratio 1", 1, SpanContext{}, true},
+		{"new trace, ratio 0", 0, SpanContext{}, false},
+		{"sampled parent, ratio 0", 0, sampledParent, true},
+		{"unsampled parent, ratio 1", 1, parent, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := &recordingExporter{}
+			tracer := NewTracer("forum", exporter, tt.ratio, nil)
+
+			ctx, root := tracer.Start(context.Background(), "request", tt.parent)
+			_, child := Start(ctx, "query")
+			child.SetAttribute("rows", 1)
+			child.End(nil)
+			root.End(nil)
+
+			if root.Context().Sampled != tt.want {
+				t.Errorf("sampled = %v, want %v", root.Context().Sampled, tt.want)
+			}
+			if tt.parent.IsValid() && root.Context().TraceID != tt.parent.TraceID {
+				t.Errorf("trace id = %s, want the parent's %s", root.Context().TraceID, tt.parent.TraceID)
+			}
+			if (child != nil) != tt.want {
+				t.Errorf("child span = %v, want one only when sampled", child)
+			}
+			wantExported := 0
+			if tt.want {
+				wantExported = 2
+			}
+			if len(exporter.spans) != wantExported {
+				t.Errorf("exported %d spans, want %d", len(exporter.spans), wantExported)
+			}
+		})
+	}
+}
+
+func TestExportedSpansCarryParents(t *testing.T) {
+	parent := SpanContext{Sampled: true}
+	randomID(parent.TraceID[:])
+	randomID(parent.SpanID[:])
+
+	exporter := &recordingExporter{}
+	tracer := NewTracer("forum", exporter, 0, nil)
+	ctx, root := tracer.Start(context.Background(), "request", parent)
+	childCtx, child := Start(ctx, "usecase")
+	_, grandchild := Start(childCtx, "repository")
+	grandchild.SetAttribute("table", "posts")
+	grandchild.End(errors.New("no rows"))
+	grandchild.End(nil)
+	child.End(nil)
+	root.End(nil)
+
+	if len(exporter.spans) != 3 {
+		t.Fatalf("exported %d spans, want 3", len(exporter.spans))
+	}
+	byName := make(map[string]*SpanData)
+	for _, span := range exporter.spans {
+		byName[span.Name] = span
+		if span.TraceID != parent.TraceID.String() || span.Service != "forum" {
+			t.Errorf("span %s is in trace %s of %s, want %s of forum", span.Name, span.TraceID, span.Service, parent.TraceID)
+		}
+	}
+
+	wantParents := map[string]string{
+		"request":    parent.SpanID.String(),
+		"usecase":    root.Context().SpanID.String(),
+		"repository": child.Context().SpanID.String(),
+	}
+	for name, wantParent := range wantParents {
+		if span := byName[name]; span == nil || span.ParentID != wantParent {
+			t.Errorf("span %s = %+v, want parent %s", name, span, wantParent)
+		}
+	}
+
+	repositorySpan := byName["repository"]
+	if repositorySpan.Error != "no rows" || repositorySpan.Attributes["table"] != "posts" {
+		t.Errorf("repository span = %+v, want the error and attributes it ended with", repositorySpan)
+	}
+	if repositorySpan.End.Before(repositorySpan.Start) || repositorySpan.DurationMs < 0 {
+		t.Errorf("repository span ends at %v before its start %v", repositorySpan.End, repositorySpan.Start)
+	}
+}
+
+func TestNewTraceHasNoParent(t *testing.T) {
+	exporter := &recordingExporter{}
+	tracer := NewTracer("forum", exporter, 1, nil)
+	_, root := tracer.Start(context.Background(), "request", SpanContext{})
+	root.End(nil)
+
+	if len(exporter.spans) != 1 || exporter.spans[0].ParentID != "" {
+		t.Fatalf("exported %+v, want a span without parent", exporter.spans)
+	}
+	if !root.Context().IsValid() {
+		t.Errorf("span context %+v has a zero id", root.Context())
+	}
+}
+
+func TestNilSpan(t *testing.T) {
+	ctx, span := Start(context.Background(), "untraced")
+	if span != nil || FromContext(ctx) != nil {
+		t.Fatal("Start without a traced request returned a span")
+	}
+	span.SetAttribute("key", "value")
+	span.End(errors.New("ignored"))
+	if span.Context().IsValid() {
+		t.Error("nil span has a valid context")
+	}
+}